@@ -1,13 +1,20 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/Nowap83/FrameRate/backend/internal/config"
 	"github.com/Nowap83/FrameRate/backend/internal/database"
+	"github.com/Nowap83/FrameRate/backend/internal/middleware"
 	"github.com/Nowap83/FrameRate/backend/internal/router"
+	"github.com/Nowap83/FrameRate/backend/internal/service"
 	"github.com/Nowap83/FrameRate/backend/internal/utils"
 	internalValidator "github.com/Nowap83/FrameRate/backend/internal/validator"
 	"github.com/gin-contrib/cors"
@@ -72,16 +79,25 @@ func main() {
 	allowedOriginsEnv := os.Getenv("CORS_ALLOWED_ORIGINS")
 	var allowedOrigins []string
 	if allowedOriginsEnv != "" {
-		allowedOrigins = strings.Split(allowedOriginsEnv, ",")
+		for _, origin := range strings.Split(allowedOriginsEnv, ",") {
+			allowedOrigins = append(allowedOrigins, strings.TrimSpace(origin))
+		}
 	} else {
 		allowedOrigins = []string{"http://localhost:5173"}
 	}
 
+	// avec AllowCredentials (cookies de session), les origines doivent être explicites
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			utils.Log.Fatal("CORS_ALLOWED_ORIGINS cannot contain '*' when credentials are allowed")
+		}
+	}
+
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     allowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", middleware.CSRFHeaderName},
+		ExposeHeaders:    []string{"Content-Length", middleware.CSRFHeaderName},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -89,7 +105,22 @@ func main() {
 	// Serve static files
 	r.Static("/uploads", "./uploads")
 
-	router.SetupRoutes(r, db, rdb, emailService)
+	// tâches de fond (emails, notifications) hors du chemin des requêtes
+	dispatcher := service.NewDispatcher(4, 1000)
+	dispatcher.Start()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// événements temps réel (SSE), diffusés entre réplicas via Redis pub/sub
+	events := service.NewEventBroker(rdb)
+	go events.Run(ctx)
+
+	// tâches périodiques, enregistrées par les services dans SetupRoutes
+	scheduler := service.NewScheduler(dispatcher)
+
+	router.SetupRoutes(r, db, rdb, emailService, dispatcher, scheduler, events)
+	go scheduler.Run(ctx)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -101,7 +132,30 @@ func main() {
 		zap.String("env", os.Getenv("ENV")),
 	)
 
-	if err := r.Run(":" + port); err != nil {
-		utils.Log.Fatal("Failed to start server", zap.Error(err))
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: r,
+	}
+	// les flux SSE ne se terminent jamais d'eux-mêmes
+	srv.RegisterOnShutdown(events.Close)
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			utils.Log.Fatal("Failed to start server", zap.Error(err))
+		}
+	}()
+
+	<-ctx.Done()
+	utils.Log.Info("Shutting down server...")
+
+	// arrêt propre : requêtes en cours, puis tâches déjà en file
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		utils.Log.Error("Server shutdown failed", zap.Error(err))
+	}
+	if err := dispatcher.Stop(shutdownCtx); err != nil {
+		utils.Log.Error("Background tasks did not finish in time", zap.Error(err))
 	}
 }
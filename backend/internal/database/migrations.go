@@ -1,6 +1,8 @@
 package database
 
 import (
+	"errors"
+
 	"github.com/Nowap83/FrameRate/backend/internal/model"
 	"github.com/Nowap83/FrameRate/backend/internal/utils"
 	"go.uber.org/zap"
@@ -13,6 +15,12 @@ func AutoMigrateAll(db *gorm.DB) {
 
 	err := db.AutoMigrate(
 		&model.User{},
+		&model.Role{},
+		&model.MagicLinkToken{},
+		&model.AuditEvent{},
+		&model.Follow{},
+		&model.Block{},
+		&model.Mute{},
 
 		// Movie models
 		&model.Movie{},
@@ -26,11 +34,147 @@ func AutoMigrateAll(db *gorm.DB) {
 		&model.Track{},
 		&model.Rate{},
 		&model.Review{},
+		&model.ReviewLike{},
+		&model.ReviewComment{},
+		&model.ReviewRevision{},
+		&model.UserList{},
+		&model.UserListEntry{},
+		&model.ListCollaborator{},
+		&model.Notification{},
+		&model.NotificationPreference{},
+		&model.Report{},
+		&model.ReportAction{},
+		&model.WatchGoal{},
+		&model.Challenge{},
+		&model.ChallengeParticipant{},
+		&model.ChallengeEntry{},
+		&model.Badge{},
+		&model.UserBadge{},
+		&model.Recommendation{},
+		&model.RecommendationRun{},
 	)
 
 	if err != nil {
 		utils.Log.Fatal("Migration failed", zap.Error(err))
 	}
 
+	if err := seedRoles(db); err != nil {
+		utils.Log.Fatal("Role seeding failed", zap.Error(err))
+	}
+
+	if err := seedBadges(db); err != nil {
+		utils.Log.Fatal("Badge seeding failed", zap.Error(err))
+	}
+
+	if err := migrateLegacyAdmins(db); err != nil {
+		utils.Log.Fatal("Legacy admin migration failed", zap.Error(err))
+	}
+
+	if err := renderLegacyReviews(db); err != nil {
+		utils.Log.Fatal("Review HTML backfill failed", zap.Error(err))
+	}
+
+	if err := backfillListEntryAuthors(db); err != nil {
+		utils.Log.Fatal("List entry author backfill failed", zap.Error(err))
+	}
+
 	utils.Log.Info("Database migrated successfully")
 }
+
+// crée les rôles système et resynchronise leurs permissions avec le code
+func seedRoles(db *gorm.DB) error {
+	for _, role := range model.DefaultRoles() {
+		var existing model.Role
+		err := db.Where("name = ?", role.Name).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if err := db.Create(&role).Error; err != nil {
+				return err
+			}
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		existing.Description = role.Description
+		existing.Permissions = role.Permissions
+		existing.IsSystem = true
+		if err := db.Save(&existing).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// crée les badges par défaut absents ; un badge modifié ou supprimé par un
+// administrateur n'est jamais rétabli
+func seedBadges(db *gorm.DB) error {
+	for _, badge := range model.DefaultBadges() {
+		var count int64
+		if err := db.Unscoped().Model(&model.Badge{}).Where("code = ?", badge.Code).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		if err := db.Create(&badge).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ancien flag users.is_admin => rôle admin, puis suppression de la colonne
+func migrateLegacyAdmins(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&model.User{}, "is_admin") {
+		return nil
+	}
+
+	if err := db.Exec("UPDATE users SET role = ? WHERE is_admin = ?", model.RoleAdmin, true).Error; err != nil {
+		return err
+	}
+
+	utils.Log.Info("Migrated legacy is_admin flag to roles")
+	return db.Migrator().DropColumn(&model.User{}, "is_admin")
+}
+
+// critiques antérieures au rendu Markdown => génère content_html, par lots.
+// parcours par clé : une critique dont le rendu reste vide (espaces seuls)
+// ne doit pas être relue indéfiniment
+func renderLegacyReviews(db *gorm.DB) error {
+	const batchSize = 500
+
+	var lastUserID, lastMovieID uint
+	for {
+		var reviews []model.Review
+		err := db.Where("(content_html IS NULL OR content_html = '') AND TRIM(content) <> ''").
+			Where("user_id > ? OR (user_id = ? AND movie_id > ?)", lastUserID, lastUserID, lastMovieID).
+			Order("user_id, movie_id").
+			Limit(batchSize).
+			Find(&reviews).Error
+		if err != nil {
+			return err
+		}
+
+		for _, review := range reviews {
+			err := db.Model(&model.Review{}).
+				Where("user_id = ? AND movie_id = ?", review.UserID, review.MovieID).
+				UpdateColumn("content_html", utils.RenderMarkdown(review.Content)).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(reviews) < batchSize {
+			return nil
+		}
+		last := reviews[len(reviews)-1]
+		lastUserID, lastMovieID = last.UserID, last.MovieID
+	}
+}
+
+// films ajoutés avant les listes collaboratives => attribués au propriétaire
+func backfillListEntryAuthors(db *gorm.DB) error {
+	return db.Exec(`UPDATE user_list_entries SET added_by_id =
+		(SELECT user_lists.user_id FROM user_lists WHERE user_lists.id = user_list_entries.list_id)
+		WHERE added_by_id IS NULL`).Error
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestRenderLegacyReviews(t *testing.T) {
+	utils.Log = zap.NewNop()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.Review{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	// plus d'un lot de critiques dont le rendu reste vide : le backfill
+	// doit quand même se terminer
+	blanks := make([]model.Review, 0, 600)
+	for i := 1; i <= 600; i++ {
+		blanks = append(blanks, model.Review{UserID: uint(i), MovieID: 1, Content: strings.Repeat(" ", i%3+1)})
+	}
+	if err := db.CreateInBatches(blanks, 100).Error; err != nil {
+		t.Fatalf("Failed to seed reviews: %v", err)
+	}
+	db.Create(&model.Review{UserID: 1, MovieID: 2, Content: "**great**"})
+	db.Create(&model.Review{UserID: 700, MovieID: 1, Content: "plain"})
+
+	if err := renderLegacyReviews(db); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var rendered []model.Review
+	db.Where("content_html <> ''").Order("user_id").Find(&rendered)
+	if len(rendered) != 2 || !strings.Contains(rendered[0].ContentHTML, "<strong>great</strong>") || !strings.Contains(rendered[1].ContentHTML, "plain") {
+		t.Errorf("expected the two non-blank reviews to be rendered, got %+v", rendered)
+	}
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+)
+
+// REQUESTS
+
+type SuspendUserRequest struct {
+	Reason string     `json:"reason" binding:"required,max=500"`
+	Until  *time.Time `json:"until,omitempty"` // absent => suspension sans limite
+}
+
+// RESPONSES
+
+// vue admin d'un compte : profil public + état de modération
+type AdminUserResponse struct {
+	UserResponse
+	IsSuspended      bool       `json:"is_suspended"`
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspendedUntil   *time.Time `json:"suspended_until,omitempty"`
+	SuspensionReason *string    `json:"suspension_reason,omitempty"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
+}
+
+type AdminUserDetailResponse struct {
+	User        AdminUserResponse `json:"user"`
+	Stats       *UserStats        `json:"stats"`
+	LastLoginAt *time.Time        `json:"last_login_at,omitempty"`
+}
+
+// CONVERTERS
+
+func ToAdminUserResponse(user *model.User) AdminUserResponse {
+	response := AdminUserResponse{
+		UserResponse:     ToUserResponse(user),
+		IsSuspended:      user.IsSuspended(time.Now()),
+		SuspendedAt:      user.SuspendedAt,
+		SuspendedUntil:   user.SuspendedUntil,
+		SuspensionReason: user.SuspensionReason,
+	}
+	if user.DeletedAt.Valid {
+		deletedAt := user.DeletedAt.Time
+		response.DeletedAt = &deletedAt
+	}
+	return response
+}
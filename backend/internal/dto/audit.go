@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+)
+
+// infos de la requête HTTP transmises aux services pour l'audit
+type RequestMeta struct {
+	IP        string
+	UserAgent string
+}
+
+type AuditEventResponse struct {
+	ID        uint                   `json:"id"`
+	Action    string                 `json:"action"`
+	ActorID   *uint                  `json:"actor_id,omitempty"`
+	TargetID  *uint                  `json:"target_id,omitempty"`
+	IP        string                 `json:"ip"`
+	UserAgent string                 `json:"user_agent"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+type PaginatedAuditEventsResponse struct {
+	Events     []AuditEventResponse `json:"events"`
+	Total      int64                `json:"total"`
+	Page       int                  `json:"page"`
+	Limit      int                  `json:"limit"`
+	TotalPages int                  `json:"total_pages"`
+}
+
+func ToAuditEventResponse(event *model.AuditEvent) AuditEventResponse {
+	return AuditEventResponse{
+		ID:        event.ID,
+		Action:    event.Action,
+		ActorID:   event.ActorID,
+		TargetID:  event.TargetID,
+		IP:        event.IP,
+		UserAgent: event.UserAgent,
+		Metadata:  event.Metadata,
+		CreatedAt: event.CreatedAt,
+	}
+}
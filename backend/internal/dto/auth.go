@@ -23,6 +23,14 @@ type VerifyEmailRequest struct {
 	Token string `json:"token" binding:"required"`
 }
 
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type MagicLinkLoginRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
 type UpdateProfileRequest struct {
 	Username       *string       `json:"username,omitempty" binding:"omitempty,username"`
 	Bio            *string       `json:"bio,omitempty" binding:"omitempty,max=500"`
@@ -31,7 +39,8 @@ type UpdateProfileRequest struct {
 	FamilyName     *string       `json:"family_name,omitempty" binding:"omitempty,max=100"`
 	Location       *string       `json:"location,omitempty" binding:"omitempty,max=100"`
 	Website        *string       `json:"website,omitempty" binding:"omitempty,max=255"`
-	FavoriteFilms  []model.Movie `json:"favorite_films,omitempty"` // List of Movies
+	TimeZone       *string       `json:"time_zone,omitempty" binding:"omitempty,timezone"` // IANA, ex: Europe/Paris
+	FavoriteFilms  []model.Movie `json:"favorite_films,omitempty"`                         // List of Movies
 }
 
 type ChangePasswordRequest struct {
@@ -51,7 +60,9 @@ type UserResponse struct {
 	FamilyName     *string   `json:"family_name,omitempty"`
 	Location       *string   `json:"location,omitempty"`
 	Website        *string   `json:"website,omitempty"`
+	TimeZone       string    `json:"time_zone"`
 	IsVerified     bool      `json:"is_verified"`
+	Role           string    `json:"role"`
 	IsAdmin        bool      `json:"is_admin"`
 	CreatedAt      time.Time `json:"created_at"`
 }
@@ -61,20 +72,20 @@ type RegisterResponse struct {
 }
 
 type PaginatedUsersResponse struct {
-	Users      []UserResponse `json:"users"`
-	Total      int64          `json:"total"`
-	Page       int            `json:"page"`
-	Limit      int            `json:"limit"`
-	TotalPages int            `json:"total_pages"`
+	Users      []AdminUserResponse `json:"users"`
+	Total      int64               `json:"total"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+	TotalPages int                 `json:"total_pages"`
 }
 
 type LoginResponse struct {
-	Token string       `json:"token"`
+	Token string       `json:"token,omitempty"` // vide en mode cookie
 	User  UserResponse `json:"user"`
 }
 
 type VerifyEmailResponse struct {
-	Token   string       `json:"token"`
+	Token   string       `json:"token,omitempty"` // vide en mode cookie
 	User    UserResponse `json:"user"`
 	Message string       `json:"message"`
 }
@@ -86,6 +97,7 @@ type UserStats struct {
 	Following          int64          `json:"following"`
 	Followers          int64          `json:"followers"`
 	RatingDistribution map[string]int `json:"rating_distribution"`
+	Goal               *GoalProgress  `json:"goal,omitempty"` // objectif de l'année en cours, s'il est fixé
 }
 
 type ProfileResponse struct {
@@ -112,8 +124,10 @@ func ToUserResponse(user *model.User) UserResponse {
 		FamilyName:     user.FamilyName,
 		Location:       user.Location,
 		Website:        user.Website,
+		TimeZone:       user.TimeZone,
 		IsVerified:     user.IsVerified,
-		IsAdmin:        user.IsAdmin,
+		Role:           user.Role,
+		IsAdmin:        user.Role == model.RoleAdmin,
 		CreatedAt:      user.CreatedAt,
 	}
 }
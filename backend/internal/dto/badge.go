@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+)
+
+// REQUESTS
+
+type CreateBadgeRequest struct {
+	Code        string          `json:"code" binding:"required,min=2,max=50"`
+	Name        string          `json:"name" binding:"required,max=100"`
+	Description string          `json:"description" binding:"max=255"`
+	Icon        string          `json:"icon" binding:"max=100"`
+	Rule        model.BadgeRule `json:"rule"`
+	Position    int             `json:"position"`
+}
+
+// Rule remplace entièrement la règle existante ; le code n'est pas modifiable
+type UpdateBadgeRequest struct {
+	Name        *string          `json:"name,omitempty" binding:"omitempty,max=100"`
+	Description *string          `json:"description,omitempty" binding:"omitempty,max=255"`
+	Icon        *string          `json:"icon,omitempty" binding:"omitempty,max=100"`
+	Rule        *model.BadgeRule `json:"rule,omitempty"`
+	Position    *int             `json:"position,omitempty"`
+}
+
+// RESPONSES
+
+type BadgeResponse struct {
+	ID          uint            `json:"id"`
+	Code        string          `json:"code"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Icon        string          `json:"icon"`
+	Rule        model.BadgeRule `json:"rule"`
+	Position    int             `json:"position"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// Progress : valeur actuelle de la métrique de la règle, plafonnée à Threshold
+type UserBadgeResponse struct {
+	ID          uint       `json:"id"`
+	Code        string     `json:"code"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Icon        string     `json:"icon"`
+	AwardedAt   *time.Time `json:"awarded_at,omitempty"`
+	Progress    int        `json:"progress"`
+	Threshold   int        `json:"threshold"`
+}
+
+type UserBadgesResponse struct {
+	Earned []UserBadgeResponse `json:"earned"` // plus récents d'abord
+	Locked []UserBadgeResponse `json:"locked"` // ordre d'affichage des badges
+}
+
+// CONVERTERS
+
+func ToBadgeResponse(badge *model.Badge) BadgeResponse {
+	return BadgeResponse{
+		ID:          badge.ID,
+		Code:        badge.Code,
+		Name:        badge.Name,
+		Description: badge.Description,
+		Icon:        badge.Icon,
+		Rule:        badge.Rule,
+		Position:    badge.Position,
+		UpdatedAt:   badge.UpdatedAt,
+	}
+}
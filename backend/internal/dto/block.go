@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// RESPONSES
+
+// utilisateur bloqué ou masqué, avec la date de l'action
+type RestrictedUserResponse struct {
+	User      ReviewAuthorResponse `json:"user"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+type PaginatedRestrictedUsersResponse struct {
+	Users      []RestrictedUserResponse `json:"users"`
+	Total      int64                    `json:"total"`
+	Page       int                      `json:"page"`
+	Limit      int                      `json:"limit"`
+	TotalPages int                      `json:"total_pages"`
+}
@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+)
+
+// REQUESTS
+
+// Year : année en cours (fuseau de l'utilisateur) si absente
+type SetGoalRequest struct {
+	Year   *int `json:"year,omitempty" binding:"omitempty,min=1900,max=2200"`
+	Target int  `json:"target" binding:"required,min=1,max=10000"`
+}
+
+type CreateChallengeRequest struct {
+	Title       string                  `json:"title" binding:"required,min=3,max=100"`
+	Description string                  `json:"description" binding:"max=500"`
+	Criteria    model.ChallengeCriteria `json:"criteria"`
+	CountBy     string                  `json:"count_by" binding:"omitempty,oneof=films countries genres directors decades"`
+	Target      int                     `json:"target" binding:"required,min=1,max=10000"`
+	StartsAt    *time.Time              `json:"starts_at,omitempty"`
+	EndsAt      *time.Time              `json:"ends_at,omitempty"`
+}
+
+// Criteria remplace entièrement les critères existants ; la progression des
+// participants est alors recalculée
+type UpdateChallengeRequest struct {
+	Title       *string                  `json:"title,omitempty" binding:"omitempty,min=3,max=100"`
+	Description *string                  `json:"description,omitempty" binding:"omitempty,max=500"`
+	Criteria    *model.ChallengeCriteria `json:"criteria,omitempty"`
+	CountBy     *string                  `json:"count_by,omitempty" binding:"omitempty,oneof=films countries genres directors decades"`
+	Target      *int                     `json:"target,omitempty" binding:"omitempty,min=1,max=10000"`
+	StartsAt    *time.Time               `json:"starts_at,omitempty"`
+	EndsAt      *time.Time               `json:"ends_at,omitempty"`
+}
+
+// RESPONSES
+
+type GoalProgress struct {
+	Year      int  `json:"year"`
+	Target    int  `json:"target"`
+	Watched   int  `json:"watched"`
+	Completed bool `json:"completed"`
+}
+
+type ChallengeResponse struct {
+	ID           uint                    `json:"id"`
+	Title        string                  `json:"title"`
+	Description  string                  `json:"description"`
+	Criteria     model.ChallengeCriteria `json:"criteria"`
+	CountBy      string                  `json:"count_by"`
+	Target       int                     `json:"target"`
+	StartsAt     *time.Time              `json:"starts_at"`
+	EndsAt       *time.Time              `json:"ends_at"`
+	Participants int64                   `json:"participants"`
+	Joined       bool                    `json:"joined"`
+	Progress     *ChallengeProgress      `json:"progress,omitempty"` // si rejoint
+	CreatedAt    time.Time               `json:"created_at"`
+}
+
+type ChallengeProgress struct {
+	Progress    int        `json:"progress"`
+	Completed   bool       `json:"completed"`
+	CompletedAt *time.Time `json:"completed_at"`
+	JoinedAt    time.Time  `json:"joined_at"`
+}
+
+type PaginatedChallengesResponse struct {
+	Challenges []ChallengeResponse `json:"challenges"`
+	Total      int64               `json:"total"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+	TotalPages int                 `json:"total_pages"`
+}
+
+// CONVERTERS
+
+// participant nil : défi non rejoint
+func ToChallengeResponse(challenge *model.Challenge, participants int64, participant *model.ChallengeParticipant) ChallengeResponse {
+	response := ChallengeResponse{
+		ID:           challenge.ID,
+		Title:        challenge.Title,
+		Description:  challenge.Description,
+		Criteria:     challenge.Criteria,
+		CountBy:      challenge.CountBy,
+		Target:       challenge.Target,
+		StartsAt:     challenge.StartsAt,
+		EndsAt:       challenge.EndsAt,
+		Participants: participants,
+		Joined:       participant != nil,
+		CreatedAt:    challenge.CreatedAt,
+	}
+	if participant != nil {
+		response.Progress = &ChallengeProgress{
+			Progress:    participant.Progress,
+			Completed:   participant.CompletedAt != nil,
+			CompletedAt: participant.CompletedAt,
+			JoinedAt:    participant.CreatedAt,
+		}
+	}
+	return response
+}
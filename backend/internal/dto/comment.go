@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+// REQUESTS
+
+type CreateCommentRequest struct {
+	Content  string `json:"content" binding:"required,min=1,max=2000"`
+	ParentID *uint  `json:"parent_id,omitempty"`
+}
+
+type UpdateCommentRequest struct {
+	Content string `json:"content" binding:"required,min=1,max=2000"`
+}
+
+// RESPONSES
+
+type CommentResponse struct {
+	ID        uint                  `json:"id"`
+	Author    *ReviewAuthorResponse `json:"author"` // nil : compte supprimé
+	ParentID  *uint                 `json:"parent_id,omitempty"`
+	Content   string                `json:"content"` // vide si supprimé
+	IsEdited  bool                  `json:"is_edited"`
+	IsDeleted bool                  `json:"is_deleted"`
+	CreatedAt time.Time             `json:"created_at"`
+	Replies   []CommentResponse     `json:"replies,omitempty"` // uniquement sur les commentaires de premier niveau
+}
+
+type PaginatedCommentsResponse struct {
+	Comments       []CommentResponse `json:"comments"`
+	CommentsLocked bool              `json:"comments_locked"`
+	Total          int64             `json:"total"` // commentaires de premier niveau
+	Page           int               `json:"page"`
+	Limit          int               `json:"limit"`
+	TotalPages     int               `json:"total_pages"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+// RESPONSES
+
+type CompatibilityMovie struct {
+	TmdbID      int      `json:"tmdb_id"`
+	Title       string   `json:"title"`
+	ReleaseYear int      `json:"release_year"`
+	PosterURL   string   `json:"poster_url"`
+	YourRating  *float32 `json:"your_rating,omitempty"`
+	TheirRating *float32 `json:"their_rating,omitempty"`
+}
+
+// Score : 0 à 100, nil s'il y a trop peu de films notés en commun.
+// Pearson et Cosine : entre -1 et 1, nil si non calculables
+type TasteCompatibilityResponse struct {
+	User                 ReviewAuthorResponse `json:"user"`
+	SharedRatings        int                  `json:"shared_ratings"`
+	Score                *int                 `json:"score"`
+	Pearson              *float64             `json:"pearson"`
+	Cosine               *float64             `json:"cosine"`
+	BothLoved            []CompatibilityMovie `json:"both_loved"`
+	Disagreements        []CompatibilityMovie `json:"disagreements"`
+	OnYourWatchlist      []CompatibilityMovie `json:"on_your_watchlist"`  // bien notés par l'autre membre
+	OnTheirWatchlist     []CompatibilityMovie `json:"on_their_watchlist"` // bien notés par vous ; vide si sa watchlist est masquée
+	TheirWatchlistHidden bool                 `json:"their_watchlist_hidden"`
+}
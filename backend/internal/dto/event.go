@@ -0,0 +1,16 @@
+package dto
+
+import "encoding/json"
+
+// événement temps réel envoyé sur le flux SSE de l'utilisateur
+type StreamEvent struct {
+	ID   string          `json:"id"` // "<ms>-<seq>", croissant ; sert de Last-Event-ID
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// nouvel état de l'utilisateur sur un film (track, note ou critique modifiés)
+type InteractionEvent struct {
+	TmdbID      int                      `json:"tmdb_id"`
+	Interaction *UserInteractionResponse `json:"interaction"`
+}
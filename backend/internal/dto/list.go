@@ -0,0 +1,112 @@
+package dto
+
+import "time"
+
+// REQUESTS
+
+type CreateListRequest struct {
+	Title       string `json:"title" binding:"required,min=1,max=100"`
+	Description string `json:"description" binding:"max=2000"`
+	IsRanked    bool   `json:"is_ranked"`
+	IsPublic    *bool  `json:"is_public"` // public par défaut
+}
+
+type UpdateListRequest struct {
+	Title       *string `json:"title" binding:"omitempty,min=1,max=100"`
+	Description *string `json:"description" binding:"omitempty,max=2000"`
+	IsRanked    *bool   `json:"is_ranked"`
+	IsPublic    *bool   `json:"is_public"`
+	Version     *int    `json:"version"` // si fourni, refusé quand la liste a changé depuis
+}
+
+type AddListEntryRequest struct {
+	TmdbID int    `json:"tmdb_id" binding:"required,min=1"`
+	Note   string `json:"note" binding:"max=1000"`
+}
+
+type UpdateListEntryRequest struct {
+	Note    string `json:"note" binding:"max=1000"`
+	Version *int   `json:"version"`
+}
+
+// nouvel ordre complet de la liste, basé sur la version lue par le client
+type ReorderListRequest struct {
+	EntryIDs []uint `json:"entry_ids" binding:"required,min=1"`
+	Version  int    `json:"version" binding:"required,min=1"`
+}
+
+type InviteListCollaboratorRequest struct {
+	UserID uint   `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+type UpdateListCollaboratorRequest struct {
+	Role string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+// RESPONSES
+
+type ListResponse struct {
+	ID          uint                 `json:"id"`
+	Owner       ReviewAuthorResponse `json:"owner"`
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	IsRanked    bool                 `json:"is_ranked"`
+	IsPublic    bool                 `json:"is_public"`
+	Version     int                  `json:"version"`
+	MyRole      string               `json:"my_role,omitempty"` // owner, editor ou viewer
+	EntryCount  int64                `json:"entry_count"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+type PaginatedListsResponse struct {
+	Lists      []ListResponse `json:"lists"`
+	Total      int64          `json:"total"`
+	Page       int            `json:"page"`
+	Limit      int            `json:"limit"`
+	TotalPages int            `json:"total_pages"`
+}
+
+// statut du propriétaire de la liste sur le film
+type ListEntryOwnerStatus struct {
+	IsWatched   bool     `json:"is_watched"`
+	IsWatchlist bool     `json:"is_watchlist"`
+	Rating      *float32 `json:"rating,omitempty"`
+	HasReview   bool     `json:"has_review"`
+}
+
+type ListEntryResponse struct {
+	ID          uint                  `json:"id"`
+	Rank        *int                  `json:"rank,omitempty"` // listes classées uniquement
+	Note        string                `json:"note,omitempty"`
+	AddedAt     time.Time             `json:"added_at"`
+	AddedBy     *ReviewAuthorResponse `json:"added_by,omitempty"`
+	TmdbID      int                   `json:"tmdb_id"`
+	Title       string                `json:"title"`
+	ReleaseYear int                   `json:"release_year"`
+	PosterURL   string                `json:"poster_url"`
+	OwnerStatus ListEntryOwnerStatus  `json:"owner_status"`
+}
+
+type ListDetailResponse struct {
+	ListResponse
+	Entries    []ListEntryResponse `json:"entries"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+	TotalPages int                 `json:"total_pages"`
+}
+
+type ListCollaboratorResponse struct {
+	User        ReviewAuthorResponse `json:"user"`
+	Role        string               `json:"role"`
+	Status      string               `json:"status"` // pending ou accepted
+	InvitedAt   time.Time            `json:"invited_at"`
+	RespondedAt *time.Time           `json:"responded_at,omitempty"`
+}
+
+type ListInvitationResponse struct {
+	List      ListResponse `json:"list"`
+	Role      string       `json:"role"`
+	InvitedAt time.Time    `json:"invited_at"`
+}
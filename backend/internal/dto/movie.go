@@ -112,6 +112,11 @@ type RateMovieRequest struct {
 	Rating float32 `json:"rating" binding:"min=0,max=5"`
 }
 
+type UpdateReviewRequest struct {
+	Content   string `json:"content" binding:"required"`
+	IsSpoiler *bool  `json:"is_spoiler,omitempty"`
+}
+
 type LogMovieRequest struct {
 	Rating      *float32   `json:"rating,omitempty"`
 	ReviewText  *string    `json:"review_text,omitempty"`
@@ -120,9 +125,13 @@ type LogMovieRequest struct {
 }
 
 type ReviewResponse struct {
-	Content   string    `json:"content"`
-	IsSpoiler bool      `json:"is_spoiler"`
-	CreatedAt time.Time `json:"created_at"`
+	Content     string     `json:"content"`      // source Markdown
+	ContentHTML string     `json:"content_html"` // rendu assaini
+	IsSpoiler   bool       `json:"is_spoiler"`
+	LikeCount   int64      `json:"like_count"`
+	IsEdited    bool       `json:"is_edited"`
+	EditedAt    *time.Time `json:"edited_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
 }
 
 type UserReviewResponse struct {
@@ -133,7 +142,12 @@ type UserReviewResponse struct {
 	PosterURL     string     `json:"poster_url"`
 	Rating        *float32   `json:"rating,omitempty"`
 	Content       string     `json:"content"`
+	ContentHTML   string     `json:"content_html"`
 	IsSpoiler     bool       `json:"is_spoiler"`
+	IsRedacted    bool       `json:"is_redacted"` // vue par un autre membre qui n'a pas vu le film
+	LikeCount     int64      `json:"like_count"`
+	IsEdited      bool       `json:"is_edited"`
+	EditedAt      *time.Time `json:"edited_at,omitempty"`
 	WatchedDate   *time.Time `json:"watched_date,omitempty"`
 	ReviewedAt    time.Time  `json:"reviewed_at"`
 }
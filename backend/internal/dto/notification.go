@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// REQUESTS
+
+type MarkNotificationsReadRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1,max=100"`
+}
+
+type NotificationPreferenceInput struct {
+	Type    string `json:"type" binding:"required"`
+	Enabled bool   `json:"enabled"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceInput `json:"preferences" binding:"required,min=1,dive"`
+}
+
+// RESPONSES
+
+type NotificationResponse struct {
+	ID        uint                  `json:"id"`
+	Type      string                `json:"type"`
+	Actor     *ReviewAuthorResponse `json:"actor,omitempty"`
+	Payload   json.RawMessage       `json:"payload"` // structure selon type
+	IsRead    bool                  `json:"is_read"`
+	ReadAt    *time.Time            `json:"read_at,omitempty"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+type PaginatedNotificationsResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	UnreadCount   int64                  `json:"unread_count"`
+	Total         int64                  `json:"total"`
+	Page          int                    `json:"page"`
+	Limit         int                    `json:"limit"`
+	TotalPages    int                    `json:"total_pages"`
+}
+
+type NotificationPreferenceResponse struct {
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+}
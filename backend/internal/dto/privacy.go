@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+)
+
+// REQUESTS
+
+// champs absents => inchangés
+type UpdatePrivacySettingsRequest struct {
+	ProfileVisibility *string `json:"profile_visibility,omitempty" binding:"omitempty,oneof=public followers private"`
+	ShowWatchlist     *bool   `json:"show_watchlist,omitempty"`
+	ShowRatings       *bool   `json:"show_ratings,omitempty"`
+	ShowDiaryDates    *bool   `json:"show_diary_dates,omitempty"`
+	Discoverable      *bool   `json:"discoverable,omitempty"`
+	Comparable        *bool   `json:"comparable,omitempty"`
+}
+
+// RESPONSES
+
+type PrivacySettingsResponse struct {
+	ProfileVisibility string `json:"profile_visibility"`
+	ShowWatchlist     bool   `json:"show_watchlist"`
+	ShowRatings       bool   `json:"show_ratings"`
+	ShowDiaryDates    bool   `json:"show_diary_dates"`
+	Discoverable      bool   `json:"discoverable"`
+	Comparable        bool   `json:"comparable"`
+}
+
+func ToPrivacySettingsResponse(user *model.User) PrivacySettingsResponse {
+	return PrivacySettingsResponse{
+		ProfileVisibility: user.ProfileVisibility,
+		ShowWatchlist:     user.ShowWatchlist,
+		ShowRatings:       user.ShowRatings,
+		ShowDiaryDates:    user.ShowDiaryDates,
+		Discoverable:      user.Discoverable,
+		Comparable:        user.Comparable,
+	}
+}
+
+// profil vu par un autre utilisateur : ni email ni nom civil
+type PublicUserResponse struct {
+	ID             uint      `json:"id"`
+	Username       string    `json:"username"`
+	ProfilePicture *string   `json:"profile_picture_url,omitempty"`
+	Bio            *string   `json:"bio,omitempty"`
+	Location       *string   `json:"location,omitempty"`
+	Website        *string   `json:"website,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Restricted : profil non visible, seuls User et IsFollowing sont renseignés
+type PublicProfileResponse struct {
+	User           PublicUserResponse `json:"user"`
+	Restricted     bool               `json:"restricted"`
+	IsFollowing    bool               `json:"is_following"`
+	ShowWatchlist  bool               `json:"show_watchlist"`
+	Stats          *UserStats         `json:"stats,omitempty"`
+	Favorites      []model.Movie      `json:"favorites,omitempty"`
+	RecentActivity []model.Movie      `json:"recent_activity,omitempty"`
+}
+
+type UserSearchResponse struct {
+	Users []ReviewAuthorResponse `json:"users"`
+}
+
+func ToPublicUserResponse(user *model.User) PublicUserResponse {
+	return PublicUserResponse{
+		ID:             user.ID,
+		Username:       user.Username,
+		ProfilePicture: user.ProfilePictureURL,
+		Bio:            user.Bio,
+		Location:       user.Location,
+		Website:        user.Website,
+		CreatedAt:      user.CreatedAt,
+	}
+}
@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+)
+
+// RESPONSES
+
+type RecommendationReasonResponse struct {
+	Kind   string   `json:"kind"`
+	TmdbID int      `json:"tmdb_id,omitempty"` // film de l'historique à l'origine de la raison
+	Title  string   `json:"title,omitempty"`
+	Rating *float32 `json:"rating,omitempty"`
+	Name   string   `json:"name,omitempty"`
+	Text   string   `json:"text"` // explication prête à afficher
+}
+
+type RecommendationResponse struct {
+	TmdbID      int                            `json:"tmdb_id"`
+	Title       string                         `json:"title"`
+	ReleaseYear int                            `json:"release_year"`
+	PosterURL   string                         `json:"poster_url"`
+	Score       float64                        `json:"score"` // entre 0 et 1
+	Reasons     []RecommendationReasonResponse `json:"reasons"`
+}
+
+// Pending : premier calcul en cours, la liste est encore vide
+type RecommendationsResponse struct {
+	Recommendations []RecommendationResponse `json:"recommendations"`
+	ComputedAt      *time.Time               `json:"computed_at"`
+	Pending         bool                     `json:"pending"`
+	Total           int64                    `json:"total"`
+	Page            int                      `json:"page"`
+	Limit           int                      `json:"limit"`
+	TotalPages      int                      `json:"total_pages"`
+}
+
+// CONVERTERS
+
+func ToRecommendationResponse(recommendation *model.Recommendation) RecommendationResponse {
+	reasons := make([]RecommendationReasonResponse, 0, len(recommendation.Reasons))
+	for _, reason := range recommendation.Reasons {
+		reasons = append(reasons, RecommendationReasonResponse{
+			Kind:   reason.Kind,
+			TmdbID: reason.TmdbID,
+			Title:  reason.Title,
+			Rating: reason.Rating,
+			Name:   reason.Name,
+			Text:   recommendationReasonText(reason),
+		})
+	}
+	return RecommendationResponse{
+		TmdbID:      recommendation.Movie.TmdbID,
+		Title:       recommendation.Movie.Title,
+		ReleaseYear: recommendation.Movie.ReleaseYear,
+		PosterURL:   recommendation.Movie.PosterURL,
+		Score:       recommendation.Score,
+		Reasons:     reasons,
+	}
+}
+
+func recommendationReasonText(reason model.RecommendationReason) string {
+	switch reason.Kind {
+	case model.RecommendationReasonRated:
+		if reason.Rating != nil {
+			return fmt.Sprintf("Because you rated %s %.1f", reason.Title, *reason.Rating)
+		}
+		return fmt.Sprintf("Because you liked %s", reason.Title)
+	case model.RecommendationReasonDirector:
+		return fmt.Sprintf("Directed by %s, like %s", reason.Name, reason.Title)
+	case model.RecommendationReasonWriter:
+		return fmt.Sprintf("Written by %s, like %s", reason.Name, reason.Title)
+	case model.RecommendationReasonActor:
+		return fmt.Sprintf("With %s, like %s", reason.Name, reason.Title)
+	case model.RecommendationReasonGenre:
+		return fmt.Sprintf("%s, like %s", reason.Name, reason.Title)
+	case model.RecommendationReasonPopular:
+		return "Highly rated by FrameRate members"
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package dto
+
+import "time"
+
+// REQUESTS
+
+// TmdbID obligatoire pour une critique (identifiée par son auteur UserID et le film)
+type CreateReportRequest struct {
+	TargetType string `json:"target_type" binding:"required,oneof=review profile avatar"`
+	UserID     uint   `json:"user_id" binding:"required"`
+	TmdbID     *int   `json:"tmdb_id,omitempty"`
+	Reason     string `json:"reason" binding:"required"`
+	Details    string `json:"details" binding:"max=1000"`
+}
+
+type ModerationActionRequest struct {
+	Action string     `json:"action" binding:"required"`
+	Note   string     `json:"note" binding:"max=1000"`
+	Until  *time.Time `json:"until,omitempty"` // suspend uniquement ; absent => sans limite
+}
+
+// RESPONSES
+
+type ReportMovieResponse struct {
+	TmdbID int    `json:"tmdb_id"`
+	Title  string `json:"title"`
+}
+
+type ReportResponse struct {
+	ID                uint                  `json:"id"`
+	TargetType        string                `json:"target_type"`
+	TargetUser        ReviewAuthorResponse  `json:"target_user"`
+	Movie             *ReportMovieResponse  `json:"movie,omitempty"`
+	Reporter          *ReviewAuthorResponse `json:"reporter"` // null : signalé automatiquement
+	Reason            string                `json:"reason"`
+	Details           string                `json:"details,omitempty"`
+	Snapshot          string                `json:"snapshot"`
+	Status            string                `json:"status"`
+	TargetReportCount int64                 `json:"target_report_count"` // signalements ouverts sur le même contenu
+	ResolvedAt        *time.Time            `json:"resolved_at,omitempty"`
+	CreatedAt         time.Time             `json:"created_at"`
+}
+
+// état actuel du contenu signalé (nil si supprimé depuis)
+type ReportTargetStateResponse struct {
+	Content           *string `json:"content,omitempty"`
+	IsSpoiler         bool    `json:"is_spoiler,omitempty"`
+	SpoilerForced     bool    `json:"spoiler_forced,omitempty"`
+	IsHidden          bool    `json:"is_hidden,omitempty"`
+	Bio               *string `json:"bio,omitempty"`
+	ProfilePictureURL *string `json:"profile_picture_url,omitempty"`
+	IsSuspended       bool    `json:"is_suspended"`
+}
+
+type ReportActionResponse struct {
+	ID        uint                 `json:"id"`
+	Action    string               `json:"action"`
+	Note      string               `json:"note,omitempty"`
+	Moderator ReviewAuthorResponse `json:"moderator"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+type ReportDetailResponse struct {
+	ReportResponse
+	Current *ReportTargetStateResponse `json:"current"`
+	Actions []ReportActionResponse     `json:"actions"`
+}
+
+type PaginatedReportsResponse struct {
+	Reports    []ReportResponse `json:"reports"`
+	Total      int64            `json:"total"`
+	Page       int              `json:"page"`
+	Limit      int              `json:"limit"`
+	TotalPages int              `json:"total_pages"`
+}
@@ -0,0 +1,66 @@
+package dto
+
+import "time"
+
+// auteur affiché à côté d'une critique
+type ReviewAuthorResponse struct {
+	ID                uint    `json:"id"`
+	Username          string  `json:"username"`
+	ProfilePictureURL *string `json:"profile_picture_url,omitempty"`
+}
+
+type MovieReviewItemResponse struct {
+	Author      ReviewAuthorResponse `json:"author"`
+	Rating      *float32             `json:"rating,omitempty"`
+	Content     string               `json:"content"`
+	ContentHTML string               `json:"content_html"`
+	IsSpoiler   bool                 `json:"is_spoiler"`
+	IsRedacted  bool                 `json:"is_redacted"` // critique spoiler (content vide) ou passages ||spoiler|| masqués
+	LikeCount   int64                `json:"like_count"`
+	LikedByMe   bool                 `json:"liked_by_me"`
+	IsEdited    bool                 `json:"is_edited"`
+	EditedAt    *time.Time           `json:"edited_at,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+type PaginatedMovieReviewsResponse struct {
+	Reviews    []MovieReviewItemResponse `json:"reviews"`
+	Total      int64                     `json:"total"`
+	Page       int                       `json:"page"`
+	Limit      int                       `json:"limit"`
+	TotalPages int                       `json:"total_pages"`
+}
+
+type ReviewLikeResponse struct {
+	Liked     bool  `json:"liked"`
+	LikeCount int64 `json:"like_count"`
+}
+
+// critique du classement "populaires de la semaine", avec son film
+type PopularReviewResponse struct {
+	MovieReviewItemResponse
+	Movie PopularReviewMovieResponse `json:"movie"`
+}
+
+type PopularReviewMovieResponse struct {
+	TmdbID      int    `json:"tmdb_id"`
+	Title       string `json:"title"`
+	ReleaseYear int    `json:"release_year"`
+	PosterURL   string `json:"poster_url"`
+}
+
+// une version d'une critique ; ReplacedAt nil pour la version courante
+// (date de suppression si la critique a été supprimée)
+type ReviewRevisionResponse struct {
+	Content    string     `json:"content"`
+	IsSpoiler  bool       `json:"is_spoiler"`
+	WrittenAt  time.Time  `json:"written_at"`
+	ReplacedAt *time.Time `json:"replaced_at,omitempty"`
+}
+
+type ReviewHistoryResponse struct {
+	Current   ReviewRevisionResponse   `json:"current"`
+	Revisions []ReviewRevisionResponse `json:"revisions"`         // de la plus récente à la plus ancienne
+	Deleted   bool                     `json:"deleted,omitempty"` // supprimée par son auteur (modérateurs)
+}
@@ -0,0 +1,82 @@
+package dto
+
+// RESPONSES
+
+type YearStatsResponse struct {
+	Year             int                `json:"year"`
+	TotalFilms       int                `json:"total_films"`
+	TotalHours       float64            `json:"total_hours"`
+	Rewatches        int                `json:"rewatches"`
+	Months           []MonthStats       `json:"months"`   // 12 entrées, janvier d'abord
+	Weekdays         []WeekdayStats     `json:"weekdays"` // 7 entrées, lundi d'abord
+	TopGenres        []StatsCountItem   `json:"top_genres"`
+	TopDirectors     []StatsCountItem   `json:"top_directors"`
+	TopActors        []StatsCountItem   `json:"top_actors"`
+	TopCountries     []StatsCountItem   `json:"top_countries"`
+	Decades          []DecadeStats      `json:"decades"`
+	ReleaseYears     []ReleaseYearStats `json:"release_years"`
+	AverageRating    *float64           `json:"average_rating"`
+	CommunityAverage *float64           `json:"community_average"` // autres membres, sur les mêmes films
+	MostRewatched    []RewatchedMovie   `json:"most_rewatched"`
+	LongestStreak    StreakStats        `json:"longest_streak"`
+	MetadataPending  int                `json:"metadata_pending"` // films dont genres et générique sont en cours d'import
+}
+
+type MonthStats struct {
+	Month int     `json:"month"`
+	Films int     `json:"films"`
+	Hours float64 `json:"hours"`
+}
+
+type WeekdayStats struct {
+	Weekday string `json:"weekday"`
+	Films   int    `json:"films"`
+}
+
+// ID : identifiant TMDB (genre, personne) ou code ISO du pays
+type StatsCountItem struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	ImageURL string `json:"image_url,omitempty"`
+	Films    int64  `json:"films"`
+}
+
+type DecadeStats struct {
+	Decade int `json:"decade"`
+	Films  int `json:"films"`
+}
+
+type ReleaseYearStats struct {
+	Year  int `json:"year"`
+	Films int `json:"films"`
+}
+
+type RewatchedMovie struct {
+	TmdbID     int    `json:"tmdb_id"`
+	Title      string `json:"title"`
+	PosterURL  string `json:"poster_url"`
+	WatchCount int    `json:"watch_count"`
+}
+
+// dates AAAA-MM-JJ dans le fuseau de l'utilisateur
+type StreakStats struct {
+	Days  int    `json:"days"`
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// Days : uniquement les jours avec au moins un film, dans l'ordre
+type ActivityResponse struct {
+	TimeZone      string        `json:"time_zone"`
+	From          string        `json:"from"`
+	To            string        `json:"to"`
+	Total         int           `json:"total"`
+	Days          []ActivityDay `json:"days"`
+	CurrentStreak int           `json:"current_streak"`
+	LongestStreak StreakStats   `json:"longest_streak"`
+}
+
+type ActivityDay struct {
+	Date  string `json:"date"`
+	Films int    `json:"films"`
+}
@@ -35,6 +35,8 @@ type TMDBMovieDetails struct {
 	OriginalLanguage string       `json:"original_language"`
 	Genres           []TMDBGenre  `json:"genres"`
 	Credits          *TMDBCredits `json:"credits,omitempty"`
+
+	ProductionCountries []TMDBCountry `json:"production_countries"`
 }
 
 type TMDBGenre struct {
@@ -42,6 +44,11 @@ type TMDBGenre struct {
 	Name string `json:"name"`
 }
 
+type TMDBCountry struct {
+	Code string `json:"iso_3166_1"`
+	Name string `json:"name"`
+}
+
 type TMDBCredits struct {
 	ID   int              `json:"id"`
 	Cast []TMDBCastMember `json:"cast"`
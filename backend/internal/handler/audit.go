@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"github.com/Nowap83/FrameRate/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// (Admin) lists audit events, filterable by action, actor, target and date range
+func (h *AuditHandler) ListEvents(c *gin.Context) {
+	page, limit := parsePagination(c, 50, 100)
+
+	filter := repository.AuditFilter{Action: c.Query("action")}
+
+	for param, dest := range map[string]**uint{"actor_id": &filter.ActorID, "target_id": &filter.TargetID} {
+		if value := c.Query(param); value != "" {
+			id, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+				return
+			}
+			parsed := uint(id)
+			*dest = &parsed
+		}
+	}
+
+	for param, dest := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := c.Query(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param + " date (expected RFC3339)"})
+				return
+			}
+			*dest = &t
+		}
+	}
+
+	response, err := h.auditService.ListEvents(filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// returns the current user's recent security activity
+func (h *AuditHandler) GetMySecurityActivity(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	page, limit := parsePagination(c, 20, 50)
+
+	response, err := h.auditService.ListSecurityActivity(userID.(uint), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
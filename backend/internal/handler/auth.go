@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/middleware"
 	"github.com/Nowap83/FrameRate/backend/internal/service"
 	internalValidator "github.com/Nowap83/FrameRate/backend/internal/validator"
 	"github.com/gin-gonic/gin"
@@ -41,6 +42,9 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	response, err := h.authService.Register(input)
 	if err != nil {
+		if respondContentViolation(c, err) {
+			return
+		}
 		switch err.Error() {
 		case "email already exists":
 			c.JSON(http.StatusConflict, gin.H{
@@ -81,7 +85,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		})
 		return
 	}
-	response, err := h.authService.Login(input)
+	response, err := h.authService.Login(input, requestMeta(c))
 	if err != nil {
 		switch err.Error() {
 		case "invalid credentials":
@@ -90,12 +94,18 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{
 				"error": "Please verify your email before logging in. Check your inbox.",
 			})
+		case "account suspended":
+			c.JSON(http.StatusForbidden, gin.H{"error": "Your account has been suspended"})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Login failed"})
 		}
 		return
 	}
 
+	if !startSession(c, &response.Token) {
+		return
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -124,5 +134,91 @@ func (h *AuthHandler) VerifyEmail(c *gin.Context) {
 		return
 	}
 
+	if !startSession(c, &response.Token) {
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+//
+// MAGIC LINK
+//
+
+func (h *AuthHandler) RequestMagicLink(c *gin.Context) {
+	var input dto.MagicLinkRequest
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		if validationErr, ok := err.(validator.ValidationErrors); ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"errors": internalValidator.FormatValidationErrors(validationErr),
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON format"})
+		return
+	}
+
+	response, err := h.authService.RequestMagicLink(input, requestMeta(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send login link"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// le token est envoyé en POST (et non en GET) pour que les scanners de liens
+// des clients mail ne consomment pas le lien à usage unique
+func (h *AuthHandler) LoginWithMagicLink(c *gin.Context) {
+	var input dto.MagicLinkLoginRequest
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Login token is required"})
+		return
+	}
+
+	response, err := h.authService.LoginWithMagicLink(input.Token, requestMeta(c))
+	if err != nil {
+		switch err.Error() {
+		case "invalid or expired login link":
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired login link"})
+		case "account suspended":
+			c.JSON(http.StatusForbidden, gin.H{"error": "Your account has been suspended"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Login failed"})
+		}
+		return
+	}
+
+	if !startSession(c, &response.Token) {
+		return
+	}
+
 	c.JSON(http.StatusOK, response)
 }
+
+//
+// LOGOUT
+//
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	middleware.ClearSessionCookies(c)
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// en mode cookie, pose les cookies de session et retire le JWT du body
+func startSession(c *gin.Context, token *string) bool {
+	if !middleware.CookieModeEnabled() {
+		return true
+	}
+
+	if err := middleware.SetSessionCookies(c, *token); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start session"})
+		return false
+	}
+
+	*token = ""
+	return true
+}
@@ -7,8 +7,10 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/middleware"
 	"github.com/Nowap83/FrameRate/backend/internal/model"
 	"github.com/Nowap83/FrameRate/backend/internal/repository"
 	"github.com/Nowap83/FrameRate/backend/internal/service"
@@ -24,12 +26,23 @@ import (
 )
 
 // MockEmailSender for tests
-type MockEmailSender struct{}
+type MockEmailSender struct {
+	magicLinks chan string
+}
 
 func (m *MockEmailSender) SendVerificationEmail(to, username, token string) error {
 	return nil
 }
 
+func (m *MockEmailSender) SendMagicLinkEmail(to, username, token string) error {
+	if m.magicLinks != nil {
+		m.magicLinks <- token
+	}
+	return nil
+}
+
+var testEmailSender = &MockEmailSender{magicLinks: make(chan string, 10)}
+
 func setupAuthHandlerTest() (*gin.Engine, *gorm.DB) {
 	utils.Log = zap.NewNop()
 	gin.SetMode(gin.TestMode)
@@ -39,16 +52,18 @@ func setupAuthHandlerTest() (*gin.Engine, *gorm.DB) {
 	}
 
 	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	db.AutoMigrate(&model.User{}, &model.Movie{}, &model.Track{}, &model.Rate{}, &model.Review{})
+	db.AutoMigrate(&model.User{}, &model.MagicLinkToken{}, &model.Movie{}, &model.Track{}, &model.Rate{}, &model.Review{}, &model.ReviewLike{}, &model.ReviewComment{}, &model.ReviewRevision{}, &model.Block{}, &model.Mute{})
 
 	userRepo := repository.NewUserRepository(db)
-	authService := service.NewAuthService(userRepo, &MockEmailSender{})
+	authService := service.NewAuthService(userRepo, testEmailSender)
 	authHandler := NewAuthHandler(authService)
 
 	r := gin.New()
 	r.POST("/register", authHandler.Register)
 	r.POST("/login", authHandler.Login)
 	r.GET("/verify-email", authHandler.VerifyEmail)
+	r.POST("/magic-link", authHandler.RequestMagicLink)
+	r.POST("/magic-link/verify", authHandler.LoginWithMagicLink)
 
 	return r, db
 }
@@ -197,3 +212,106 @@ func TestAuthHandler_VerifyEmail(t *testing.T) {
 		t.Errorf("expected 400 Bad Request for invalid token, got %d", w3.Code)
 	}
 }
+
+func TestAuthHandler_MagicLink(t *testing.T) {
+	r, db := setupAuthHandlerTest()
+	os.Setenv("JWT_SECRET", "testsecret")
+
+	user := &model.User{
+		Username:   "magicuser",
+		Email:      "magic@example.com",
+		IsVerified: true,
+	}
+	db.Create(user)
+
+	// 1. Same response for known and unknown emails
+	for _, email := range []string{"magic@example.com", "unknown@example.com"} {
+		body, _ := json.Marshal(dto.MagicLinkRequest{Email: email})
+		req, _ := http.NewRequest("POST", "/magic-link", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200 OK for %s, got %d", email, w.Code)
+		}
+	}
+
+	var token string
+	select {
+	case token = <-testEmailSender.magicLinks:
+	case <-time.After(time.Second):
+		t.Fatalf("expected a magic link to be sent")
+	}
+
+	// 2. Login with the link
+	body, _ := json.Marshal(dto.MagicLinkLoginRequest{Token: token})
+	req, _ := http.NewRequest("POST", "/magic-link/verify", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", w.Code)
+	}
+	var resp dto.LoginResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Token == "" || resp.User.ID != user.ID {
+		t.Errorf("expected login response for magicuser")
+	}
+
+	// 3. Single use
+	req2, _ := http.NewRequest("POST", "/magic-link/verify", bytes.NewBuffer(body))
+	req2.Header.Set("Content-Type", "application/json")
+	w2 := httptest.NewRecorder()
+	r.ServeHTTP(w2, req2)
+
+	if w2.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 Unauthorized on reuse, got %d", w2.Code)
+	}
+}
+
+func TestAuthHandler_Login_CookieMode(t *testing.T) {
+	r, db := setupAuthHandlerTest()
+	os.Setenv("JWT_SECRET", "testsecret")
+	os.Setenv("AUTH_COOKIE_MODE", "true")
+	defer os.Unsetenv("AUTH_COOKIE_MODE")
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
+	db.Create(&model.User{
+		Username:     "cookieuser",
+		Email:        "cookie@example.com",
+		IsVerified:   true,
+		PasswordHash: string(hash),
+	})
+
+	body, _ := json.Marshal(dto.LoginRequest{Login: "cookieuser", Password: "password"})
+	req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", w.Code)
+	}
+
+	var resp dto.LoginResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Token != "" {
+		t.Errorf("expected token to be omitted from the body in cookie mode")
+	}
+
+	cookies := map[string]*http.Cookie{}
+	for _, cookie := range w.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+
+	session := cookies[middleware.SessionCookieName]
+	if session == nil || !session.HttpOnly || !session.Secure || session.Value == "" {
+		t.Errorf("expected a Secure HttpOnly session cookie, got %+v", session)
+	}
+	csrf := cookies[middleware.CSRFCookieName]
+	if csrf == nil || csrf.HttpOnly || csrf.Value != w.Header().Get(middleware.CSRFHeaderName) {
+		t.Errorf("expected a readable CSRF cookie matching the CSRF header, got %+v", csrf)
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type BadgeHandler struct {
+	achievementService *service.AchievementService
+}
+
+func NewBadgeHandler(achievementService *service.AchievementService) *BadgeHandler {
+	return &BadgeHandler{
+		achievementService: achievementService,
+	}
+}
+
+// lists the current user's earned badges and the locked ones with their progress
+func (h *BadgeHandler) GetMyBadges(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	response, err := h.achievementService.GetUserBadges(userID.(uint))
+	if err != nil {
+		respondBadgeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// (Admin) lists badge definitions with their rules
+func (h *BadgeHandler) ListBadges(c *gin.Context) {
+	badges, err := h.achievementService.ListBadges()
+	if err != nil {
+		respondBadgeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"badges": badges})
+}
+
+// (Admin) creates a badge; members who already qualify receive it in the background
+func (h *BadgeHandler) CreateBadge(c *gin.Context) {
+	var input dto.CreateBadgeRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+	response, err := h.achievementService.CreateBadge(adminID.(uint), input, requestMeta(c))
+	if err != nil {
+		respondBadgeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// (Admin) updates a badge definition or rule
+func (h *BadgeHandler) UpdateBadge(c *gin.Context) {
+	badgeID, ok := parseIDParam(c, "id", "Invalid badge ID")
+	if !ok {
+		return
+	}
+
+	var input dto.UpdateBadgeRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+	response, err := h.achievementService.UpdateBadge(adminID.(uint), badgeID, input, requestMeta(c))
+	if err != nil {
+		respondBadgeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// (Admin) deletes a badge; it no longer appears among members' badges
+func (h *BadgeHandler) DeleteBadge(c *gin.Context) {
+	badgeID, ok := parseIDParam(c, "id", "Invalid badge ID")
+	if !ok {
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+	if err := h.achievementService.DeleteBadge(adminID.(uint), badgeID, requestMeta(c)); err != nil {
+		respondBadgeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Badge deleted successfully"})
+}
+
+func respondBadgeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrBadgeNotFound), errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrBadgeCodeTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidBadge):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
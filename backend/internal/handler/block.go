@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Nowap83/FrameRate/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type BlockHandler struct {
+	blockService *service.BlockService
+}
+
+func NewBlockHandler(blockService *service.BlockService) *BlockHandler {
+	return &BlockHandler{
+		blockService: blockService,
+	}
+}
+
+// blocks a user: both users disappear from each other's views
+func (h *BlockHandler) Block(c *gin.Context) {
+	targetID, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.blockService.Block(userID.(uint), targetID); err != nil {
+		respondBlockError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User blocked successfully"})
+}
+
+func (h *BlockHandler) Unblock(c *gin.Context) {
+	targetID, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.blockService.Unblock(userID.(uint), targetID); err != nil {
+		respondBlockError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unblocked successfully"})
+}
+
+// mutes a user: their content is filtered out of the current user's views only
+func (h *BlockHandler) Mute(c *gin.Context) {
+	targetID, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.blockService.Mute(userID.(uint), targetID); err != nil {
+		respondBlockError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User muted successfully"})
+}
+
+func (h *BlockHandler) Unmute(c *gin.Context) {
+	targetID, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.blockService.Unmute(userID.(uint), targetID); err != nil {
+		respondBlockError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unmuted successfully"})
+}
+
+func (h *BlockHandler) GetBlockedUsers(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	page, limit := parsePagination(c, 20, 50)
+
+	response, err := h.blockService.GetBlockedUsers(userID.(uint), page, limit)
+	if err != nil {
+		respondBlockError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *BlockHandler) GetMutedUsers(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	page, limit := parsePagination(c, 20, 50)
+
+	response, err := h.blockService.GetMutedUsers(userID.(uint), page, limit)
+	if err != nil {
+		respondBlockError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func respondBlockError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCannotBlockSelf), errors.Is(err, service.ErrCannotMuteSelf):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
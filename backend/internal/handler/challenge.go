@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ChallengeHandler struct {
+	challengeService *service.ChallengeService
+}
+
+func NewChallengeHandler(challengeService *service.ChallengeService) *ChallengeHandler {
+	return &ChallengeHandler{
+		challengeService: challengeService,
+	}
+}
+
+// returns the current user's goal for ?year= (defaults to the current year)
+func (h *ChallengeHandler) GetGoal(c *gin.Context) {
+	year, ok := parseYearQuery(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := h.challengeService.GetGoal(userID.(uint), year)
+	if err != nil {
+		respondChallengeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// sets (or replaces) the current user's yearly film-count goal
+func (h *ChallengeHandler) SetGoal(c *gin.Context) {
+	var input dto.SetGoalRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := h.challengeService.SetGoal(userID.(uint), input)
+	if err != nil {
+		respondChallengeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// removes the current user's goal for ?year= (defaults to the current year)
+func (h *ChallengeHandler) DeleteGoal(c *gin.Context) {
+	year, ok := parseYearQuery(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.challengeService.DeleteGoal(userID.(uint), year); err != nil {
+		respondChallengeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Goal deleted successfully"})
+}
+
+// lists ongoing and upcoming challenges with the current user's progress
+func (h *ChallengeHandler) ListChallenges(c *gin.Context) {
+	page, limit := parsePagination(c, 20, 50)
+
+	userID, _ := c.Get("userID")
+	response, err := h.challengeService.ListChallenges(userID.(uint), page, limit)
+	if err != nil {
+		respondChallengeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// lists the challenges the current user joined, including ended ones
+func (h *ChallengeHandler) GetMyChallenges(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	challenges, err := h.challengeService.GetMyChallenges(userID.(uint))
+	if err != nil {
+		respondChallengeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"challenges": challenges})
+}
+
+func (h *ChallengeHandler) GetChallenge(c *gin.Context) {
+	challengeID, ok := parseIDParam(c, "id", "Invalid challenge ID")
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := h.challengeService.GetChallenge(userID.(uint), challengeID)
+	if err != nil {
+		respondChallengeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// joins a challenge; films already watched during its period are counted
+func (h *ChallengeHandler) JoinChallenge(c *gin.Context) {
+	challengeID, ok := parseIDParam(c, "id", "Invalid challenge ID")
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := h.challengeService.JoinChallenge(userID.(uint), challengeID)
+	if err != nil {
+		respondChallengeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// leaves a challenge and discards its progress
+func (h *ChallengeHandler) LeaveChallenge(c *gin.Context) {
+	challengeID, ok := parseIDParam(c, "id", "Invalid challenge ID")
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.challengeService.LeaveChallenge(userID.(uint), challengeID); err != nil {
+		respondChallengeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Challenge left successfully"})
+}
+
+// (Admin) creates a challenge
+func (h *ChallengeHandler) CreateChallenge(c *gin.Context) {
+	var input dto.CreateChallengeRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+	response, err := h.challengeService.CreateChallenge(adminID.(uint), input, requestMeta(c))
+	if err != nil {
+		respondChallengeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// (Admin) updates a challenge; participants' progress is recomputed
+func (h *ChallengeHandler) UpdateChallenge(c *gin.Context) {
+	challengeID, ok := parseIDParam(c, "id", "Invalid challenge ID")
+	if !ok {
+		return
+	}
+
+	var input dto.UpdateChallengeRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+	response, err := h.challengeService.UpdateChallenge(adminID.(uint), challengeID, input, requestMeta(c))
+	if err != nil {
+		respondChallengeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// (Admin) deletes a challenge
+func (h *ChallengeHandler) DeleteChallenge(c *gin.Context) {
+	challengeID, ok := parseIDParam(c, "id", "Invalid challenge ID")
+	if !ok {
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+	if err := h.challengeService.DeleteChallenge(adminID.(uint), challengeID, requestMeta(c)); err != nil {
+		respondChallengeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Challenge deleted successfully"})
+}
+
+// reads the optional ?year= query; 0 means the current year
+func parseYearQuery(c *gin.Context) (int, bool) {
+	raw := c.Query("year")
+	if raw == "" {
+		return 0, true
+	}
+	year, err := strconv.Atoi(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+		return 0, false
+	}
+	return year, true
+}
+
+func respondChallengeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrChallengeNotFound), errors.Is(err, service.ErrGoalNotFound),
+		errors.Is(err, service.ErrChallengeNotJoined), errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrChallengeEnded):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidChallenge):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	commentService *service.CommentService
+}
+
+func NewCommentHandler(commentService *service.CommentService) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+	}
+}
+
+// lists a review's comment threads, paginated on top-level comments
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	tmdbID, reviewUserID, ok := parseReviewParams(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	page, limit := parsePagination(c, 20, 50)
+
+	response, err := h.commentService.GetComments(userID.(uint), tmdbID, reviewUserID, page, limit)
+	if err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// comments on a review, or replies to one of its comments
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	tmdbID, reviewUserID, ok := parseReviewParams(c)
+	if !ok {
+		return
+	}
+
+	var input dto.CreateCommentRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := h.commentService.CreateComment(userID.(uint), tmdbID, reviewUserID, input)
+	if err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// edits one of the current user's comments
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	commentID, ok := parseIDParam(c, "id", "Invalid comment ID")
+	if !ok {
+		return
+	}
+
+	var input dto.UpdateCommentRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := h.commentService.UpdateComment(userID.(uint), commentID, input)
+	if err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// deletes a comment, leaving a tombstone so replies stay attached
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	commentID, ok := parseIDParam(c, "id", "Invalid comment ID")
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.commentService.DeleteComment(userID.(uint), commentID); err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted"})
+}
+
+// (Review author) locks new comments on their review
+func (h *CommentHandler) LockComments(c *gin.Context) {
+	h.setLocked(c, true)
+}
+
+// (Review author) unlocks comments on their review
+func (h *CommentHandler) UnlockComments(c *gin.Context) {
+	h.setLocked(c, false)
+}
+
+func (h *CommentHandler) setLocked(c *gin.Context, locked bool) {
+	tmdbID, reviewUserID, ok := parseReviewParams(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.commentService.SetCommentsLocked(userID.(uint), tmdbID, reviewUserID, locked); err != nil {
+		respondCommentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comments_locked": locked})
+}
+
+func parseReviewParams(c *gin.Context) (int, uint, bool) {
+	tmdbID, err := strconv.Atoi(c.Param("tmdb_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid movie ID"})
+		return 0, 0, false
+	}
+	reviewUserID, ok := parseIDParam(c, "user_id", "Invalid user ID")
+	if !ok {
+		return 0, 0, false
+	}
+	return tmdbID, reviewUserID, true
+}
+
+func respondCommentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrReviewNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+	case errors.Is(err, service.ErrCommentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+	case errors.Is(err, service.ErrCommentsLocked),
+		errors.Is(err, service.ErrNotCommentAuthor),
+		errors.Is(err, service.ErrCannotDeleteComment),
+		errors.Is(err, service.ErrNotReviewAuthor):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCommentDeleted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidParentComment),
+		errors.Is(err, service.ErrEmptyComment):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	eventHeartbeatInterval = 25 * time.Second
+	eventRetryMillis       = 3000
+)
+
+type EventHandler struct {
+	broker    *service.EventBroker
+	heartbeat time.Duration
+}
+
+func NewEventHandler(broker *service.EventBroker) *EventHandler {
+	return &EventHandler{
+		broker:    broker,
+		heartbeat: eventHeartbeatInterval,
+	}
+}
+
+// streams the current user's events over Server-Sent Events; reconnecting with
+// Last-Event-ID (header, or last_event_id query) replays what was missed
+func (h *EventHandler) Stream(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	sub, backlog, err := h.broker.Subscribe(userID.(uint), lastEventID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // pas de buffering côté nginx
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", eventRetryMillis)
+
+	lastSent := lastEventID
+	for _, event := range backlog {
+		writeStreamEvent(w, event)
+		if event.ID != "" {
+			lastSent = event.ID
+		}
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-sub.Done():
+			return
+		case event := <-sub.Events():
+			// déjà envoyé lors du rattrapage
+			if lastSent != "" && !service.IsNewerEvent(event.ID, lastSent) {
+				continue
+			}
+			writeStreamEvent(w, event)
+			lastSent = event.ID
+			w.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			w.Flush()
+		}
+	}
+}
+
+func writeStreamEvent(w io.Writer, event dto.StreamEvent) {
+	if event.ID != "" {
+		fmt.Fprintf(w, "id: %s\n", event.ID)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, event.Data)
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/service"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func setupEventHandlerTest() (*httptest.Server, *service.EventBroker) {
+	utils.Log = zap.NewNop()
+	gin.SetMode(gin.TestMode)
+
+	broker := service.NewEventBroker(nil)
+	eventHandler := NewEventHandler(broker)
+	eventHandler.heartbeat = 20 * time.Millisecond
+
+	r := gin.New()
+	r.GET("/events", func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		c.Next()
+	}, eventHandler.Stream)
+
+	return httptest.NewServer(r), broker
+}
+
+// lit le flux jusqu'à trouver chacun des fragments attendus, dans l'ordre
+func readStreamUntil(t *testing.T, scanner *bufio.Scanner, expected ...string) {
+	t.Helper()
+	for _, want := range expected {
+		found := false
+		for scanner.Scan() {
+			if strings.Contains(scanner.Text(), want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("expected %q in stream", want)
+		}
+	}
+}
+
+func TestEventHandler_StreamsAndResumes(t *testing.T) {
+	server, broker := setupEventHandlerTest()
+	defer server.Close()
+
+	broker.Publish(1, service.EventProfileUpdated, map[string]string{"username": "before"})
+	_, history, _ := broker.Subscribe(1, "0-0")
+	firstID := history[1].ID
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events", nil)
+	req.Header.Set("Last-Event-ID", firstID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Errorf("unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	readStreamUntil(t, scanner, "retry:")
+
+	broker.Publish(1, service.EventProfileUpdated, map[string]string{"username": "after"})
+	readStreamUntil(t, scanner, "event: profile.updated", `data: {"username":"after"}`, ": heartbeat")
+}
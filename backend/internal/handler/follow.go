@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Nowap83/FrameRate/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type FollowHandler struct {
+	followService *service.FollowService
+}
+
+func NewFollowHandler(followService *service.FollowService) *FollowHandler {
+	return &FollowHandler{
+		followService: followService,
+	}
+}
+
+// follows another user
+func (h *FollowHandler) Follow(c *gin.Context) {
+	targetID, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.followService.Follow(userID.(uint), targetID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrCannotFollowSelf):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User followed successfully"})
+}
+
+// unfollows a user
+func (h *FollowHandler) Unfollow(c *gin.Context) {
+	targetID, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.followService.Unfollow(userID.(uint), targetID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unfollowed successfully"})
+}
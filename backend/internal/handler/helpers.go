@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/service"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	internalValidator "github.com/Nowap83/FrameRate/backend/internal/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+// IP et user agent de la requête, pour le journal d'audit
+func requestMeta(c *gin.Context) dto.RequestMeta {
+	return dto.RequestMeta{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+// lit page/limit avec valeurs par défaut et plafond
+func parsePagination(c *gin.Context, defaultLimit, maxLimit int) (int, int) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+	if err != nil || limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	return page, limit
+}
+
+// lit un identifiant numérique dans l'URL ; répond 400 si invalide
+func parseIDParam(c *gin.Context, name, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// bind JSON + erreurs de validation formatées ; répond 400 si invalide,
+// avec un message générique si le corps n'est pas un JSON conforme
+func bindJSON(c *gin.Context, input interface{}) bool {
+	if err := c.ShouldBindJSON(input); err != nil {
+		var validationErr validator.ValidationErrors
+		if errors.As(err, &validationErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": internalValidator.FormatValidationErrors(validationErr)})
+			return false
+		}
+		// détail (types Go, offsets) gardé pour les logs
+		utils.Log.Debug("Invalid JSON body", zap.String("path", c.FullPath()), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON format"})
+		return false
+	}
+	return true
+}
+
+// contenu refusé par la politique de contenu : répond 422 avec le champ et
+// les règles en cause ; false si err est d'une autre nature
+func respondContentViolation(c *gin.Context, err error) bool {
+	var violation *service.ContentViolationError
+	if !errors.As(err, &violation) {
+		return false
+	}
+	c.JSON(http.StatusUnprocessableEntity, gin.H{
+		"errors": map[string]string{violation.Field: violation.Field + " is not allowed by the content policy"},
+		"rules":  violation.Rules,
+	})
+	return true
+}
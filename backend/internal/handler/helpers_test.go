@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestBindJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	utils.Log = zap.NewNop()
+
+	type input struct {
+		Name  string `json:"name" binding:"required"`
+		Count int    `json:"count"`
+	}
+	tests := []struct {
+		name      string
+		body      string
+		wantOK    bool
+		wantField string
+	}{
+		{"Valid body", `{"name":"heat","count":2}`, true, ""},
+		{"Validation error", `{"count":2}`, false, "errors"},
+		{"Malformed JSON", `{"name":`, false, "error"},
+		{"Wrong type", `{"name":"heat","count":"two"}`, false, "error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			var target input
+			if ok := bindJSON(c, &target); ok != tt.wantOK {
+				t.Fatalf("expected %v, got %v", tt.wantOK, ok)
+			}
+			if tt.wantOK {
+				return
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+			if w.Code != http.StatusBadRequest || len(resp) != 1 || resp[tt.wantField] == nil {
+				t.Errorf("expected 400 with %q, got %d %s", tt.wantField, w.Code, w.Body.String())
+			}
+			if tt.wantField == "error" && resp["error"] != "invalid JSON format" {
+				t.Errorf("expected the generic message, got %v", resp["error"])
+			}
+			if tt.wantField == "errors" {
+				if fields, _ := resp["errors"].(map[string]interface{}); fields["name"] == nil {
+					t.Errorf("expected a message for name, got %v", resp["errors"])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,383 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ListHandler struct {
+	listService *service.ListService
+}
+
+func NewListHandler(listService *service.ListService) *ListHandler {
+	return &ListHandler{
+		listService: listService,
+	}
+}
+
+func (h *ListHandler) CreateList(c *gin.Context) {
+	var input dto.CreateListRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := h.listService.CreateList(userID.(uint), input)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// lists the current user's lists, private ones included
+func (h *ListHandler) GetMyLists(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	h.userLists(c, userID.(uint))
+}
+
+// lists another user's public lists
+func (h *ListHandler) GetUserLists(c *gin.Context) {
+	ownerID, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+	h.userLists(c, ownerID)
+}
+
+// returns a list with its movies and the owner's watched/rated status for each
+func (h *ListHandler) GetList(c *gin.Context) {
+	listID, ok := parseIDParam(c, "id", "Invalid list ID")
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	page, limit := parsePagination(c, 50, 100)
+
+	response, err := h.listService.GetList(userID.(uint), listID, page, limit)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ListHandler) UpdateList(c *gin.Context) {
+	listID, ok := parseIDParam(c, "id", "Invalid list ID")
+	if !ok {
+		return
+	}
+
+	var input dto.UpdateListRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := h.listService.UpdateList(userID.(uint), listID, input)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ListHandler) DeleteList(c *gin.Context) {
+	listID, ok := parseIDParam(c, "id", "Invalid list ID")
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.listService.DeleteList(userID.(uint), listID); err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "List deleted"})
+}
+
+// appends a movie to the list
+func (h *ListHandler) AddEntry(c *gin.Context) {
+	listID, ok := parseIDParam(c, "id", "Invalid list ID")
+	if !ok {
+		return
+	}
+
+	var input dto.AddListEntryRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := h.listService.AddEntry(userID.(uint), listID, input)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// edits the note attached to a list entry
+func (h *ListHandler) UpdateEntry(c *gin.Context) {
+	listID, entryID, ok := parseListEntryParams(c)
+	if !ok {
+		return
+	}
+
+	var input dto.UpdateListEntryRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := h.listService.UpdateEntry(userID.(uint), listID, entryID, input)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ListHandler) RemoveEntry(c *gin.Context) {
+	listID, entryID, ok := parseListEntryParams(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := h.listService.RemoveEntry(userID.(uint), listID, entryID)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// replaces the order of the whole list, 409 if it changed since the given version
+func (h *ListHandler) ReorderList(c *gin.Context) {
+	listID, ok := parseIDParam(c, "id", "Invalid list ID")
+	if !ok {
+		return
+	}
+
+	var input dto.ReorderListRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := h.listService.ReorderList(userID.(uint), listID, input)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// lists other users' lists the current user collaborates on
+func (h *ListHandler) GetSharedLists(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	page, limit := parsePagination(c, 20, 50)
+
+	response, err := h.listService.GetSharedLists(userID.(uint), page, limit)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ListHandler) GetCollaborators(c *gin.Context) {
+	listID, ok := parseIDParam(c, "id", "Invalid list ID")
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	collaborators, err := h.listService.GetCollaborators(userID.(uint), listID)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"collaborators": collaborators})
+}
+
+// invites a user as editor or viewer (owner only)
+func (h *ListHandler) InviteCollaborator(c *gin.Context) {
+	listID, ok := parseIDParam(c, "id", "Invalid list ID")
+	if !ok {
+		return
+	}
+
+	var input dto.InviteListCollaboratorRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := h.listService.InviteCollaborator(userID.(uint), listID, input)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// changes a collaborator's role (owner only)
+func (h *ListHandler) UpdateCollaborator(c *gin.Context) {
+	listID, collaboratorID, ok := parseListCollaboratorParams(c)
+	if !ok {
+		return
+	}
+
+	var input dto.UpdateListCollaboratorRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := h.listService.UpdateCollaboratorRole(userID.(uint), listID, collaboratorID, input)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// removes a collaborator or cancels an invitation; collaborators may remove themselves to leave
+func (h *ListHandler) RemoveCollaborator(c *gin.Context) {
+	listID, collaboratorID, ok := parseListCollaboratorParams(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.listService.RemoveCollaborator(userID.(uint), listID, collaboratorID); err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collaborator removed"})
+}
+
+// lists the current user's pending list invitations
+func (h *ListHandler) GetInvitations(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	invitations, err := h.listService.GetInvitations(userID.(uint))
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
+
+func (h *ListHandler) AcceptInvitation(c *gin.Context) {
+	listID, ok := parseIDParam(c, "id", "Invalid list ID")
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := h.listService.AcceptInvitation(userID.(uint), listID)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ListHandler) DeclineInvitation(c *gin.Context) {
+	listID, ok := parseIDParam(c, "id", "Invalid list ID")
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := h.listService.DeclineInvitation(userID.(uint), listID); err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation declined"})
+}
+
+func (h *ListHandler) userLists(c *gin.Context, ownerID uint) {
+	userID, _ := c.Get("userID")
+	page, limit := parsePagination(c, 20, 50)
+
+	response, err := h.listService.GetUserLists(userID.(uint), ownerID, page, limit)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func parseListEntryParams(c *gin.Context) (uint, uint, bool) {
+	listID, ok := parseIDParam(c, "id", "Invalid list ID")
+	if !ok {
+		return 0, 0, false
+	}
+	entryID, ok := parseIDParam(c, "entry_id", "Invalid entry ID")
+	if !ok {
+		return 0, 0, false
+	}
+	return listID, entryID, true
+}
+
+func parseListCollaboratorParams(c *gin.Context) (uint, uint, bool) {
+	listID, ok := parseIDParam(c, "id", "Invalid list ID")
+	if !ok {
+		return 0, 0, false
+	}
+	userID, ok := parseIDParam(c, "user_id", "Invalid user ID")
+	if !ok {
+		return 0, 0, false
+	}
+	return listID, userID, true
+}
+
+func respondListError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrListNotFound),
+		errors.Is(err, service.ErrListEntryNotFound),
+		errors.Is(err, service.ErrCollaboratorNotFound),
+		errors.Is(err, service.ErrListInvitationNotFound),
+		errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrListForbidden),
+		errors.Is(err, service.ErrListCollaboratorForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrMovieAlreadyInList),
+		errors.Is(err, service.ErrAlreadyCollaborator),
+		errors.Is(err, service.ErrListVersionConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidListOrder),
+		errors.Is(err, service.ErrListTitleEmpty),
+		errors.Is(err, service.ErrInvalidListRole),
+		errors.Is(err, service.ErrCannotInviteSelf):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
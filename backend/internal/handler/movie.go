@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -81,6 +82,9 @@ func (h *MovieHandler) LogMovie(c *gin.Context) {
 	}
 
 	if err := h.movieService.LogMovie(userID.(uint), tmdbID, req); err != nil {
+		if respondContentViolation(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log movie", "details": err.Error()})
 		return
 	}
@@ -88,6 +92,96 @@ func (h *MovieHandler) LogMovie(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Movie logged successfully"})
 }
 
+// updates an existing tracking entry; fields left out are unchanged
+func (h *MovieHandler) UpdateTrack(c *gin.Context) {
+	var req dto.TrackMovieRequest
+	h.updateEntry(c, &req, func(userID uint, tmdbID int) error {
+		return h.movieService.UpdateTrack(userID, tmdbID, req)
+	}, "Movie tracking updated successfully")
+}
+
+// removes a movie from the user's diary, along with its rating and review
+func (h *MovieHandler) DeleteTrack(c *gin.Context) {
+	h.deleteEntry(c, h.movieService.DeleteTrack, "Movie removed from your diary")
+}
+
+// changes an existing rating without touching the watched state
+func (h *MovieHandler) UpdateRating(c *gin.Context) {
+	var req dto.RateMovieRequest
+	h.updateEntry(c, &req, func(userID uint, tmdbID int) error {
+		return h.movieService.UpdateRating(userID, tmdbID, req)
+	}, "Movie rating updated successfully")
+}
+
+func (h *MovieHandler) DeleteRating(c *gin.Context) {
+	h.deleteEntry(c, h.movieService.DeleteRating, "Movie rating deleted")
+}
+
+func (h *MovieHandler) UpdateReview(c *gin.Context) {
+	var req dto.UpdateReviewRequest
+	h.updateEntry(c, &req, func(userID uint, tmdbID int) error {
+		return h.movieService.UpdateReview(userID, tmdbID, req)
+	}, "Movie review updated successfully")
+}
+
+// deletes the user's review with its likes and comments
+func (h *MovieHandler) DeleteReview(c *gin.Context) {
+	h.deleteEntry(c, h.movieService.DeleteReview, "Movie review deleted")
+}
+
+func (h *MovieHandler) updateEntry(c *gin.Context, req interface{}, action func(userID uint, tmdbID int) error, message string) {
+	tmdbID, err := strconv.Atoi(c.Param("tmdb_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid movie ID"})
+		return
+	}
+
+	if !bindJSON(c, req) {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := action(userID.(uint), tmdbID); err != nil {
+		respondMovieEntryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+func (h *MovieHandler) deleteEntry(c *gin.Context, action func(userID uint, tmdbID int) error, message string) {
+	tmdbID, err := strconv.Atoi(c.Param("tmdb_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid movie ID"})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := action(userID.(uint), tmdbID); err != nil {
+		respondMovieEntryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+func respondMovieEntryError(c *gin.Context, err error) {
+	if respondContentViolation(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrTrackNotFound),
+		errors.Is(err, service.ErrRatingNotFound),
+		errors.Is(err, service.ErrReviewNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidRatingStep),
+		errors.Is(err, service.ErrReviewEmpty):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
 func (h *MovieHandler) GetMovieInteraction(c *gin.Context) {
 	tmdbID, err := strconv.Atoi(c.Param("tmdb_id"))
 	if err != nil {
@@ -23,7 +23,7 @@ func setupMovieHandlerTest() (*gin.Engine, *gorm.DB) {
 	gin.SetMode(gin.TestMode)
 
 	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	db.AutoMigrate(&model.User{}, &model.Movie{}, &model.Track{}, &model.Rate{}, &model.Review{})
+	db.AutoMigrate(&model.User{}, &model.Movie{}, &model.Track{}, &model.Rate{}, &model.Review{}, &model.ReviewLike{}, &model.ReviewComment{}, &model.ReviewRevision{}, &model.Block{}, &model.Mute{})
 
 	movieRepo := repository.NewMovieRepository(db)
 	tmdbService := service.NewTMDBService(nil) // It's fine if we pre-populate movies
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// returns the current user's inbox, newest first (?unread=true for unread only)
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	page, limit := parsePagination(c, 20, 50)
+	unreadOnly := c.Query("unread") == "true"
+
+	response, err := h.notificationService.GetNotifications(userID.(uint), unreadOnly, page, limit)
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	count, err := h.notificationService.CountUnread(userID.(uint))
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread_count": count})
+}
+
+// marks the given notifications as read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	var input dto.MarkNotificationsReadRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	count, err := h.notificationService.MarkRead(userID.(uint), input.IDs)
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"marked": count})
+}
+
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	count, err := h.notificationService.MarkAllRead(userID.(uint))
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"marked": count})
+}
+
+// returns every notification type with whether the current user receives it
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	preferences, err := h.notificationService.GetPreferences(userID.(uint))
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"preferences": preferences})
+}
+
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	var input dto.UpdateNotificationPreferencesRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	preferences, err := h.notificationService.UpdatePreferences(userID.(uint), input)
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"preferences": preferences})
+}
+
+func respondNotificationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidNotificationType):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
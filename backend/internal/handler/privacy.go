@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// returns the current user's privacy settings
+func (h *UserHandler) GetPrivacySettings(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	response, err := h.userService.GetPrivacySettings(userID.(uint))
+	if err != nil {
+		respondUserReadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// updates the current user's privacy settings (omitted fields are unchanged)
+func (h *UserHandler) UpdatePrivacySettings(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var input dto.UpdatePrivacySettingsRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	response, err := h.userService.UpdatePrivacySettings(userID.(uint), input)
+	if err != nil {
+		respondUserReadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// searches discoverable users by username
+func (h *UserHandler) SearchUsers(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	response, err := h.userService.SearchUsers(userID.(uint), c.Query("q"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// returns another user's profile, restricted to a basic card when it is not visible
+func (h *UserHandler) GetUserProfile(c *gin.Context) {
+	targetID, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("userID")
+
+	response, err := h.userService.GetUserProfile(userID.(uint), targetID)
+	if err != nil {
+		respondUserReadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// retrieves another user's watched films
+func (h *UserHandler) GetUserFilms(c *gin.Context) {
+	targetID, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("userID")
+	page, limit := parsePagination(c, 20, 50)
+
+	response, err := h.userService.GetUserFilms(userID.(uint), targetID, page, limit)
+	if err != nil {
+		respondUserReadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// retrieves another user's reviews
+func (h *UserHandler) GetUserReviews(c *gin.Context) {
+	targetID, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("userID")
+	page, limit := parsePagination(c, 20, 50)
+
+	response, err := h.userService.GetUserReviews(userID.(uint), targetID, page, limit)
+	if err != nil {
+		respondUserReadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// retrieves another user's watchlist
+func (h *UserHandler) GetUserWatchlist(c *gin.Context) {
+	targetID, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("userID")
+	page, limit := parsePagination(c, 20, 50)
+
+	response, err := h.userService.GetUserWatchlist(userID.(uint), targetID, page, limit)
+	if err != nil {
+		respondUserReadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// compares the current user's ratings with another user's
+func (h *UserHandler) GetTasteCompatibility(c *gin.Context) {
+	targetID, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+	userID, _ := c.Get("userID")
+
+	response, err := h.userService.GetTasteCompatibility(userID.(uint), targetID)
+	if err != nil {
+		respondUserReadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func respondUserReadError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, service.ErrProfilePrivate):
+		c.JSON(http.StatusForbidden, gin.H{"error": "This profile is private"})
+	case errors.Is(err, service.ErrWatchlistPrivate):
+		c.JSON(http.StatusForbidden, gin.H{"error": "This watchlist is private"})
+	case errors.Is(err, service.ErrComparisonDisabled):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCompareSelf):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
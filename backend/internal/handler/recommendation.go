@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Nowap83/FrameRate/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type RecommendationHandler struct {
+	recommendationService *service.RecommendationService
+}
+
+func NewRecommendationHandler(recommendationService *service.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{
+		recommendationService: recommendationService,
+	}
+}
+
+// returns the current user's precomputed recommendations with their explanations
+func (h *RecommendationHandler) GetMyRecommendations(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	page, limit := parsePagination(c, 20, 50)
+
+	response, err := h.recommendationService.GetRecommendations(userID.(uint), page, limit)
+	if err != nil {
+		respondRecommendationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func respondRecommendationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"github.com/Nowap83/FrameRate/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	moderationService *service.ModerationService
+}
+
+func NewReportHandler(moderationService *service.ModerationService) *ReportHandler {
+	return &ReportHandler{
+		moderationService: moderationService,
+	}
+}
+
+// reports a review, a profile or an avatar to the moderators
+func (h *ReportHandler) CreateReport(c *gin.Context) {
+	var input dto.CreateReportRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := h.moderationService.CreateReport(userID.(uint), input)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// (Admin) moderation queue, oldest first, filterable by status, target_type and reason
+func (h *ReportHandler) GetReports(c *gin.Context) {
+	page, limit := parsePagination(c, 20, 100)
+
+	filter := repository.ReportFilter{
+		Status:     c.Query("status"),
+		TargetType: c.Query("target_type"),
+		Reason:     c.Query("reason"),
+	}
+
+	response, err := h.moderationService.GetReports(filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// (Admin) report with the current state of its target and its action history
+func (h *ReportHandler) GetReport(c *gin.Context) {
+	reportID, ok := parseIDParam(c, "id", "Invalid report ID")
+	if !ok {
+		return
+	}
+
+	response, err := h.moderationService.GetReport(reportID)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// (Admin) applies a moderation action to a report's target
+func (h *ReportHandler) ApplyAction(c *gin.Context) {
+	reportID, ok := parseIDParam(c, "id", "Invalid report ID")
+	if !ok {
+		return
+	}
+
+	var input dto.ModerationActionRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	moderatorID, _ := c.Get("userID")
+	response, err := h.moderationService.ApplyAction(moderatorID.(uint), reportID, input, requestMeta(c))
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func respondReportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrReportNotFound),
+		errors.Is(err, service.ErrReportTargetNotFound),
+		errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrModerationActionDenied),
+		errors.Is(err, service.ErrCannotModerateSelf):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAlreadyReported),
+		errors.Is(err, service.ErrReportClosed),
+		errors.Is(err, service.ErrReportNotClosed),
+		errors.Is(err, service.ErrModerationTargetRequired):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidReportReason),
+		errors.Is(err, service.ErrInvalidModerationAction),
+		errors.Is(err, service.ErrCannotReportSelf),
+		errors.Is(err, service.ErrSuspensionInPast):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
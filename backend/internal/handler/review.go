@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"github.com/Nowap83/FrameRate/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewHandler struct {
+	reviewService *service.ReviewService
+}
+
+func NewReviewHandler(reviewService *service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+// lists a movie's reviews, sortable and filterable to followed users
+func (h *ReviewHandler) GetMovieReviews(c *gin.Context) {
+	tmdbID, err := strconv.Atoi(c.Param("tmdb_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid movie ID"})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	page, limit := parsePagination(c, 20, 50)
+
+	filter := repository.MovieReviewFilter{
+		Sort:        c.DefaultQuery("sort", repository.ReviewSortRecent),
+		FriendsOnly: c.Query("friends") == "true",
+	}
+	if !repository.IsValidReviewSort(filter.Sort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort (expected recent, popular, highest or lowest)"})
+		return
+	}
+
+	showSpoilers := c.Query("show_spoilers") == "true"
+
+	response, err := h.reviewService.GetMovieReviews(userID.(uint), tmdbID, filter, showSpoilers, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// likes another user's review of a movie
+func (h *ReviewHandler) LikeReview(c *gin.Context) {
+	h.toggleLike(c, h.reviewService.LikeReview)
+}
+
+// removes the current user's like from a review
+func (h *ReviewHandler) UnlikeReview(c *gin.Context) {
+	h.toggleLike(c, h.reviewService.UnlikeReview)
+}
+
+// most liked reviews of the week, ranked with time decay
+func (h *ReviewHandler) GetPopularReviews(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	_, limit := parsePagination(c, 10, 50)
+
+	reviews, err := h.reviewService.GetPopularReviews(userID.(uint), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reviews": reviews})
+}
+
+// (Author or moderator) lists the previous versions of a review
+func (h *ReviewHandler) GetReviewHistory(c *gin.Context) {
+	tmdbID, reviewUserID, ok := parseReviewParams(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := h.reviewService.GetReviewHistory(userID.(uint), tmdbID, reviewUserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrReviewNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+		case errors.Is(err, service.ErrReviewHistoryDenied):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ReviewHandler) toggleLike(c *gin.Context, action func(userID uint, tmdbID int, reviewUserID uint) (*dto.ReviewLikeResponse, error)) {
+	tmdbID, reviewUserID, ok := parseReviewParams(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	response, err := action(userID.(uint), tmdbID, reviewUserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrReviewNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+		case errors.Is(err, service.ErrCannotLikeOwnReview):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type RoleHandler struct {
+	roleService *service.RoleService
+}
+
+func NewRoleHandler(roleService *service.RoleService) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+	}
+}
+
+// (Admin) lists system and custom roles with their permissions
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.roleService.ListRoles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"roles": roles})
+}
+
+// (Admin) creates a custom role
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var input dto.CreateRoleRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+	response, err := h.roleService.CreateRole(adminID.(uint), input, requestMeta(c))
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// (Admin) updates a custom role's description or permissions
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	roleID, ok := parseIDParam(c, "id", "Invalid role ID")
+	if !ok {
+		return
+	}
+
+	var input dto.UpdateRoleRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+	response, err := h.roleService.UpdateRole(adminID.(uint), roleID, input, requestMeta(c))
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// (Admin) deletes a custom role that is no longer assigned
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	roleID, ok := parseIDParam(c, "id", "Invalid role ID")
+	if !ok {
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+	if err := h.roleService.DeleteRole(adminID.(uint), roleID, requestMeta(c)); err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Role deleted successfully"})
+}
+
+// (Admin) assigns a role to a user
+func (h *RoleHandler) AssignRole(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	var input dto.AssignRoleRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+	response, err := h.roleService.AssignRole(adminID.(uint), userID, input.Role, requestMeta(c))
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func respondRoleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrRoleNotFound), errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrRoleNameTaken), errors.Is(err, service.ErrRoleInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrRoleSystem), errors.Is(err, service.ErrCannotChangeOwnRole):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrRoleNameInvalid), errors.Is(err, service.ErrInvalidPermission):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type StatsHandler struct {
+	statsService *service.StatsService
+}
+
+func NewStatsHandler(statsService *service.StatsService) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+// returns the current user's year-in-review statistics (defaults to the current year)
+func (h *StatsHandler) GetYearStats(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	year := time.Now().Year()
+	if raw := c.Query("year"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+			return
+		}
+		year = parsed
+	}
+
+	response, err := h.statsService.GetYearStats(userID.(uint), year)
+	if err != nil {
+		respondStatsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// returns per-day watch counts between ?from= and ?to= (YYYY-MM-DD, user's time zone) with streaks
+func (h *StatsHandler) GetActivity(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	response, err := h.statsService.GetActivity(userID.(uint), c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondStatsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func respondStatsError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidStatsYear):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+	case errors.Is(err, service.ErrInvalidActivityRange):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date range"})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
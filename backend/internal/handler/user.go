@@ -4,8 +4,11 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
 	"github.com/Nowap83/FrameRate/backend/internal/service"
 	"github.com/Nowap83/FrameRate/backend/internal/utils" // Import utils for logging
 	internalValidator "github.com/Nowap83/FrameRate/backend/internal/validator"
@@ -169,6 +172,9 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 
 	response, err := h.userService.UpdateProfile(userID.(uint), input)
 	if err != nil {
+		if respondContentViolation(c, err) {
+			return
+		}
 		if errors.Is(err, service.ErrUsernameTaken) {
 			c.JSON(http.StatusConflict, gin.H{"error": "Username already taken"})
 			return
@@ -202,7 +208,7 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	if err := h.userService.ChangePassword(userID.(uint), input); err != nil {
+	if err := h.userService.ChangePassword(userID.(uint), input, requestMeta(c)); err != nil {
 		if errors.Is(err, service.ErrPasswordIncorrect) {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
 			return
@@ -224,7 +230,7 @@ func (h *UserHandler) DeleteAccount(c *gin.Context) {
 		return
 	}
 
-	if err := h.userService.DeleteAccount(userID.(uint)); err != nil {
+	if err := h.userService.DeleteAccount(userID.(uint), requestMeta(c)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete account"})
 		return
 	}
@@ -279,7 +285,7 @@ func (h *UserHandler) UploadAvatar(c *gin.Context) {
 		return
 	}
 
-	avatarURL, err := h.userService.UpdateAvatar(userID.(uint), file)
+	avatarURL, err := h.userService.UpdateAvatar(userID.(uint), file, requestMeta(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update avatar: " + err.Error()})
 		return
@@ -311,25 +317,47 @@ func (h *UserHandler) CheckUsername(c *gin.Context) {
 	})
 }
 
-// (Admin) fetches all users with pagination
+// (Admin) fetches users with search, filters, sorting and pagination
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
-	pageStr := c.DefaultQuery("page", "1")
-	limitStr := c.DefaultQuery("limit", "20")
+	page, limit := parsePagination(c, 20, 100)
 
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		page = 1
+	filter := repository.UserFilter{
+		Query: strings.TrimSpace(c.Query("q")),
+		Role:  c.Query("role"),
+		Sort:  c.DefaultQuery("sort", "created_at"),
+		Order: c.DefaultQuery("order", "desc"),
 	}
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 {
-		limit = 20
+	switch filter.Deleted = c.Query("deleted"); filter.Deleted {
+	case repository.DeletedExclude, repository.DeletedInclude, repository.DeletedOnly:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid deleted filter (expected include or only)"})
+		return
+	}
+
+	for param, dest := range map[string]**bool{"verified": &filter.Verified, "suspended": &filter.Suspended} {
+		if value := c.Query(param); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param + " filter"})
+				return
+			}
+			*dest = &parsed
+		}
 	}
-	if limit > 100 {
-		limit = 100 // cap limit to 100 max per page
+
+	for param, dest := range map[string]**time.Time{"created_from": &filter.CreatedFrom, "created_to": &filter.CreatedTo} {
+		if value := c.Query(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param + " date (expected RFC3339)"})
+				return
+			}
+			*dest = &t
+		}
 	}
 
-	response, err := h.userService.GetAllUsers(page, limit)
+	response, err := h.userService.GetAllUsers(filter, page, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
 		return
@@ -338,17 +366,100 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// (Admin) returns a user's account state and stats, including deleted accounts
+func (h *UserHandler) GetUserAdmin(c *gin.Context) {
+	targetID, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	response, err := h.userService.GetUserDetail(targetID)
+	if err != nil {
+		respondAdminUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// (Admin) suspends a user, optionally until a given date
+func (h *UserHandler) SuspendUser(c *gin.Context) {
+	targetID, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	var input dto.SuspendUserRequest
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+	response, err := h.userService.SuspendUser(adminID.(uint), targetID, input, requestMeta(c))
+	if err != nil {
+		respondAdminUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// (Admin) lifts a user's suspension
+func (h *UserHandler) UnsuspendUser(c *gin.Context) {
+	h.adminUserAction(c, h.userService.UnsuspendUser)
+}
+
+// (Admin) marks a user's email as verified
+func (h *UserHandler) ForceVerifyUser(c *gin.Context) {
+	h.adminUserAction(c, h.userService.ForceVerifyUser)
+}
+
+// (Admin) restores a soft-deleted account
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	h.adminUserAction(c, h.userService.RestoreUser)
+}
+
+func (h *UserHandler) adminUserAction(c *gin.Context, action func(adminID, userID uint, meta dto.RequestMeta) (*dto.AdminUserResponse, error)) {
+	targetID, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+	response, err := action(adminID.(uint), targetID, requestMeta(c))
+	if err != nil {
+		respondAdminUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func respondAdminUserError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, service.ErrCannotModerateSelf):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotDeleted), errors.Is(err, service.ErrRestoreConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSuspensionInPast):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
 // (Admin) deletes any user by ID
 func (h *UserHandler) DeleteUserAdmin(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+	targetID, ok := parseIDParam(c, "id", "Invalid user ID")
+	if !ok {
 		return
 	}
 
-	if err := h.userService.DeleteAccount(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
+	adminID, _ := c.Get("userID")
+	if err := h.userService.DeleteUserByAdmin(adminID.(uint), targetID, requestMeta(c)); err != nil {
+		respondAdminUserError(c, err)
 		return
 	}
 
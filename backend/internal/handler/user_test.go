@@ -31,11 +31,13 @@ func setupUserHandlerTest() (*gin.Engine, *gorm.DB) {
 	}
 
 	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	db.AutoMigrate(&model.User{}, &model.Movie{}, &model.Track{}, &model.Rate{}, &model.Review{})
+	db.AutoMigrate(&model.User{}, &model.Movie{}, &model.Track{}, &model.Rate{}, &model.Review{}, &model.ReviewLike{}, &model.AuditEvent{})
 
+	auditService := service.NewAuditService(repository.NewAuditRepository(db))
 	userRepo := repository.NewUserRepository(db)
 	movieRepo := repository.NewMovieRepository(db)
 	userService := service.NewUserService(userRepo, movieRepo)
+	userService.SetAuditService(auditService)
 	userHandler := NewUserHandler(userService)
 
 	r := gin.New()
@@ -59,6 +61,7 @@ func setupUserHandlerTest() (*gin.Engine, *gorm.DB) {
 	r.GET("/check-username", userHandler.CheckUsername)
 
 	admin := r.Group("/admin")
+	admin.Use(mockAuth)
 	{
 		admin.GET("/users", userHandler.GetAllUsers)
 		admin.DELETE("/users/:id", userHandler.DeleteUserAdmin)
@@ -114,6 +117,31 @@ func TestUserHandler_UpdateProfile(t *testing.T) {
 	}
 }
 
+func TestUserHandler_UpdateProfileTimeZone(t *testing.T) {
+	r, db := setupUserHandlerTest()
+
+	user := &model.User{ID: 1, Username: "tzuser", Email: "tz@example.com"}
+	db.Create(user)
+
+	for zone, expected := range map[string]int{"America/New_York": http.StatusOK, "Mars/Olympus": http.StatusBadRequest} {
+		body, _ := json.Marshal(dto.UpdateProfileRequest{TimeZone: &zone})
+		req, _ := http.NewRequest("PUT", "/user/profile", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != expected {
+			t.Errorf("time zone %s: expected %d, got %d", zone, expected, w.Code)
+		}
+	}
+
+	var updatedUser model.User
+	db.First(&updatedUser, 1)
+	if updatedUser.TimeZone != "America/New_York" {
+		t.Errorf("expected time zone 'America/New_York', got %s", updatedUser.TimeZone)
+	}
+}
+
 func TestUserHandler_ChangePassword(t *testing.T) {
 	r, db := setupUserHandlerTest()
 
@@ -210,4 +238,19 @@ func TestUserHandler_AdminFunctions(t *testing.T) {
 	if w2.Code != http.StatusOK {
 		t.Fatalf("expected 200 OK for admin delete, got %d", w2.Code)
 	}
+
+	for path, expected := range map[string]int{"/admin/users/999": http.StatusNotFound, "/admin/users/1": http.StatusForbidden, "/admin/users/abc": http.StatusBadRequest} {
+		req, _ := http.NewRequest("DELETE", path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != expected {
+			t.Errorf("DELETE %s: expected %d, got %d", path, expected, w.Code)
+		}
+	}
+
+	var events int64
+	db.Model(&model.AuditEvent{}).Where("action = ? AND target_id = ?", model.AuditAdminUserDeleted, 10).Count(&events)
+	if events != 1 {
+		t.Errorf("expected admin deletion to be audited, got %d events", events)
+	}
 }
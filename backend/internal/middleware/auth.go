@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"strings"
 
@@ -8,27 +9,41 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// vérifie le JWT
+// vérifie le JWT (header Authorization, ou cookie de session en mode cookie)
 func AuthRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		var tokenString string
+
 		// recup le header Authorization
 		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
-			return
-		}
+		if authHeader != "" {
+			// format => "Bearer <token>"
+			parts := strings.Split(authHeader, " ")
+			if len(parts) != 2 || parts[0] != "Bearer" {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+				c.Abort()
+				return
+			}
+			tokenString = parts[1]
+		} else {
+			// sinon cookie de session
+			cookie, err := c.Cookie(SessionCookieName)
+			if err != nil || cookie == "" {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+				c.Abort()
+				return
+			}
 
-		// format => "Bearer <token>"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
-			c.Abort()
-			return
+			// le navigateur envoie le cookie tout seul => double-submit CSRF
+			// obligatoire sur les méthodes qui modifient des données
+			if !isSafeMethod(c.Request.Method) && !validCSRFToken(c) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Invalid CSRF token"})
+				c.Abort()
+				return
+			}
+			tokenString = cookie
 		}
 
-		tokenString := parts[1]
-
 		// parse et valide le token
 		claims, err := utils.ValidateToken(tokenString)
 		if err != nil {
@@ -41,3 +56,31 @@ func AuthRequired() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// double-submit CSRF pour les routes publiques qui agissent sur la session
+// (logout) : vérifié seulement si le cookie de session est présent
+func CSRFProtected() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cookie, err := c.Cookie(SessionCookieName); err == nil && cookie != "" &&
+			!isSafeMethod(c.Request.Method) && !validCSRFToken(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Invalid CSRF token"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// compare le header CSRF au cookie CSRF (temps constant)
+func validCSRFToken(c *gin.Context) bool {
+	header := c.GetHeader(CSRFHeaderName)
+	cookie, err := c.Cookie(CSRFCookieName)
+	if err != nil || header == "" || cookie == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(header), []byte(cookie)) == 1
+}
@@ -81,3 +81,85 @@ func TestAuthRequired_Success(t *testing.T) {
 	assert.True(t, exists)
 	assert.Equal(t, uint(123), userID)
 }
+
+func TestAuthRequired_Cookie(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test_secret")
+	defer os.Unsetenv("JWT_SECRET")
+
+	token, _ := utils.GenerateToken(42)
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		method     string
+		csrfHeader string
+		wantAbort  bool
+		wantCode   int
+	}{
+		{"Safe method without CSRF", http.MethodGet, "", false, http.StatusOK},
+		{"Unsafe method without CSRF", http.MethodPost, "", true, http.StatusForbidden},
+		{"Unsafe method with wrong CSRF", http.MethodPost, "wrong", true, http.StatusForbidden},
+		{"Unsafe method with CSRF", http.MethodDelete, "csrf123", false, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request, _ = http.NewRequest(tt.method, "/", nil)
+			c.Request.AddCookie(&http.Cookie{Name: SessionCookieName, Value: token})
+			c.Request.AddCookie(&http.Cookie{Name: CSRFCookieName, Value: "csrf123"})
+			if tt.csrfHeader != "" {
+				c.Request.Header.Set(CSRFHeaderName, tt.csrfHeader)
+			}
+
+			AuthRequired()(c)
+
+			assert.Equal(t, tt.wantAbort, c.IsAborted())
+			assert.Equal(t, tt.wantCode, w.Code)
+			if !tt.wantAbort {
+				userID, _ := c.Get("userID")
+				assert.Equal(t, uint(42), userID)
+			}
+		})
+	}
+}
+
+func TestCSRFProtected(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		session    bool
+		csrfHeader string
+		wantAbort  bool
+		wantCode   int
+	}{
+		{"No session cookie", false, "", false, http.StatusOK},
+		{"Session cookie without CSRF", true, "", true, http.StatusForbidden},
+		{"Session cookie with wrong CSRF", true, "wrong", true, http.StatusForbidden},
+		{"Session cookie with CSRF", true, "csrf123", false, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			c.Request, _ = http.NewRequest(http.MethodPost, "/", nil)
+			if tt.session {
+				c.Request.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "session"})
+			}
+			c.Request.AddCookie(&http.Cookie{Name: CSRFCookieName, Value: "csrf123"})
+			if tt.csrfHeader != "" {
+				c.Request.Header.Set(CSRFHeaderName, tt.csrfHeader)
+			}
+
+			CSRFProtected()(c)
+
+			assert.Equal(t, tt.wantAbort, c.IsAborted())
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
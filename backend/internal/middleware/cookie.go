@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	SessionCookieName = "framerate_session"
+	CSRFCookieName    = "framerate_csrf"
+	CSRFHeaderName    = "X-CSRF-Token"
+)
+
+// mode cookie optionnel (AUTH_COOKIE_MODE=true) : le JWT est stocké dans un
+// cookie HttpOnly au lieu d'être renvoyé au front
+func CookieModeEnabled() bool {
+	return os.Getenv("AUTH_COOKIE_MODE") == "true"
+}
+
+func cookieSameSite() http.SameSite {
+	switch strings.ToLower(os.Getenv("AUTH_COOKIE_SAMESITE")) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+// pose le cookie de session (HttpOnly) et le cookie CSRF (lisible en JS)
+// pour le double-submit, et renvoie aussi le token CSRF en header
+func SetSessionCookies(c *gin.Context, token string) error {
+	csrfToken, err := utils.GenerateVerificationToken()
+	if err != nil {
+		return err
+	}
+
+	maxAge := int(utils.TokenTTL.Seconds())
+	domain := os.Getenv("AUTH_COOKIE_DOMAIN")
+
+	c.SetSameSite(cookieSameSite())
+	c.SetCookie(SessionCookieName, token, maxAge, "/", domain, true, true)
+	c.SetCookie(CSRFCookieName, csrfToken, maxAge, "/", domain, true, false)
+	c.Header(CSRFHeaderName, csrfToken)
+	return nil
+}
+
+func ClearSessionCookies(c *gin.Context) {
+	domain := os.Getenv("AUTH_COOKIE_DOMAIN")
+
+	c.SetSameSite(cookieSameSite())
+	c.SetCookie(SessionCookieName, "", -1, "/", domain, true, true)
+	c.SetCookie(CSRFCookieName, "", -1, "/", domain, true, false)
+}
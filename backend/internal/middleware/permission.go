@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// résout les permissions du user authentifié (implémenté par service.AuthorizationService)
+type PermissionChecker interface {
+	HasPermission(userID uint, permission string) (bool, error)
+}
+
+// RequirePermission checks that the authenticated user's role grants the given permission
+func RequirePermission(checker PermissionChecker, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			c.Abort()
+			return
+		}
+
+		allowed, err := checker.HasPermission(userID.(uint), permission)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			c.Abort()
+			return
+		}
+
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// état de suspension du user authentifié (implémenté par service.AuthorizationService)
+type SuspensionChecker interface {
+	IsSuspended(userID uint) (bool, error)
+}
+
+// RejectSuspended blocks suspended accounts even if they still hold a valid token
+func RejectSuspended(checker SuspensionChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			c.Abort()
+			return
+		}
+
+		suspended, err := checker.IsSuspended(userID.(uint))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			c.Abort()
+			return
+		}
+
+		if suspended {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Your account has been suspended"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
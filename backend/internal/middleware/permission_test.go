@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// stub for PermissionChecker
+type stubPermissionChecker struct {
+	permissions map[string]bool
+	err         error
+}
+
+func (s *stubPermissionChecker) HasPermission(userID uint, permission string) (bool, error) {
+	return s.permissions[permission], s.err
+}
+
+func TestRequirePermission_NotSet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+
+	RequirePermission(&stubPermissionChecker{}, "users:read")(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "Unauthorized")
+	assert.True(t, c.IsAborted())
+}
+
+func TestRequirePermission_UserNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	c.Set("userID", uint(1))
+
+	RequirePermission(&stubPermissionChecker{err: errors.New("not found")}, "users:read")(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "User not found")
+	assert.True(t, c.IsAborted())
+}
+
+func TestRequirePermission_Missing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	c.Set("userID", uint(1))
+
+	checker := &stubPermissionChecker{permissions: map[string]bool{"reviews:moderate": true}}
+	RequirePermission(checker, "users:delete")(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Insufficient permissions")
+	assert.True(t, c.IsAborted())
+}
+
+func TestRequirePermission_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	c.Set("userID", uint(1))
+
+	checker := &stubPermissionChecker{permissions: map[string]bool{"reviews:moderate": true}}
+	RequirePermission(checker, "reviews:moderate")(c)
+
+	assert.False(t, c.IsAborted())
+}
+
+type stubSuspensionChecker struct {
+	suspended bool
+	err       error
+}
+
+func (s *stubSuspensionChecker) IsSuspended(userID uint) (bool, error) {
+	return s.suspended, s.err
+}
+
+func TestRejectSuspended(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		checker *stubSuspensionChecker
+		code    int
+		aborted bool
+	}{
+		{"active account", &stubSuspensionChecker{}, http.StatusOK, false},
+		{"suspended account", &stubSuspensionChecker{suspended: true}, http.StatusForbidden, true},
+		{"unknown user", &stubSuspensionChecker{err: errors.New("not found")}, http.StatusUnauthorized, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+			c.Set("userID", uint(1))
+
+			RejectSuspended(tt.checker)(c)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.aborted, c.IsAborted())
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// actions enregistrées dans le journal d'audit
+const (
+	AuditLogin                = "auth.login"
+	AuditLoginFailed          = "auth.login_failed"
+	AuditLoginSuspended       = "auth.login_suspended"
+	AuditMagicLinkRequested   = "auth.magic_link_requested"
+	AuditMagicLinkLogin       = "auth.magic_link_login"
+	AuditPasswordChanged      = "user.password_changed"
+	AuditAvatarUploaded       = "user.avatar_uploaded"
+	AuditAccountDeleted       = "user.account_deleted"
+	AuditAdminUserDeleted     = "admin.user_deleted"
+	AuditAdminUserSuspended   = "admin.user_suspended"
+	AuditAdminUserUnsuspended = "admin.user_unsuspended"
+	AuditAdminUserVerified    = "admin.user_verified"
+	AuditAdminUserRestored    = "admin.user_restored"
+	AuditAdminRoleAssigned    = "admin.role_assigned"
+	AuditAdminRoleCreated     = "admin.role_created"
+	AuditAdminRoleUpdated     = "admin.role_updated"
+	AuditAdminRoleDeleted     = "admin.role_deleted"
+	AuditModerationAction     = "moderation.action"
+
+	AuditAdminChallengeCreated = "admin.challenge_created"
+	AuditAdminChallengeUpdated = "admin.challenge_updated"
+	AuditAdminChallengeDeleted = "admin.challenge_deleted"
+	AuditAdminBadgeCreated     = "admin.badge_created"
+	AuditAdminBadgeUpdated     = "admin.badge_updated"
+	AuditAdminBadgeDeleted     = "admin.badge_deleted"
+)
+
+var ErrAuditEventImmutable = errors.New("audit events are append-only")
+
+type AuditEvent struct {
+	ID        uint                   `gorm:"primaryKey" json:"id"`
+	Action    string                 `gorm:"size:50;not null;index" json:"action"`
+	ActorID   *uint                  `gorm:"index" json:"actor_id,omitempty"`  // nil => anonyme (ex: login raté)
+	TargetID  *uint                  `gorm:"index" json:"target_id,omitempty"` // user concerné
+	IP        string                 `gorm:"size:45" json:"ip"`
+	UserAgent string                 `gorm:"size:500" json:"user_agent"`
+	Metadata  map[string]interface{} `gorm:"serializer:json;type:text" json:"metadata,omitempty"`
+	CreatedAt time.Time              `gorm:"index" json:"created_at"`
+}
+
+// journal en ajout seul : aucune modification ni suppression via GORM
+func (e *AuditEvent) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditEventImmutable
+}
+
+func (e *AuditEvent) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditEventImmutable
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// grandeurs mesurées par une règle de badge
+const (
+	BadgeMetricFilms      = "films"       // films vus (regroupables, voir BadgeRule)
+	BadgeMetricReviews    = "reviews"     // critiques écrites
+	BadgeMetricRatings    = "ratings"     // films notés
+	BadgeMetricRewatches  = "rewatches"   // visionnages au-delà du premier
+	BadgeMetricStreakDays = "streak_days" // plus longue suite de jours avec un film
+)
+
+var BadgeMetrics = []string{
+	BadgeMetricFilms,
+	BadgeMetricReviews,
+	BadgeMetricRatings,
+	BadgeMetricRewatches,
+	BadgeMetricStreakDays,
+}
+
+// dimensions des films utilisables dans Distinct et Per
+const (
+	BadgeDimensionCountries = "countries"
+	BadgeDimensionGenres    = "genres"
+	BadgeDimensionDirectors = "directors"
+	BadgeDimensionDecades   = "decades"
+)
+
+var BadgeDimensions = []string{
+	BadgeDimensionCountries,
+	BadgeDimensionGenres,
+	BadgeDimensionDirectors,
+	BadgeDimensionDecades,
+}
+
+// périodes calendaires (fuseau de l'utilisateur)
+const (
+	BadgePeriodMonth = "month"
+	BadgePeriodYear  = "year"
+)
+
+// règle déclarative : le badge est obtenu quand la valeur mesurée atteint Threshold.
+// pour la métrique films :
+//   - Filter restreint les films pris en compte ;
+//   - Distinct compte des valeurs distinctes au lieu des films (10 décennies) ;
+//   - Per et Period retiennent le meilleur groupe : un réalisateur, un mois… (5 films
+//     d'un même réalisateur dans un mois : Per directors, Period month)
+type BadgeRule struct {
+	Metric    string             `json:"metric"`
+	Threshold int                `json:"threshold"`
+	Filter    *ChallengeCriteria `json:"filter,omitempty"`
+	Distinct  string             `json:"distinct,omitempty"`
+	Per       string             `json:"per,omitempty"`
+	Period    string             `json:"period,omitempty"`
+}
+
+// genres, pays et réalisateurs viennent des métadonnées TMDB des films
+func (r BadgeRule) NeedsMetadata() bool {
+	if r.Metric != BadgeMetricFilms {
+		return false
+	}
+	needs := func(dimension string) bool {
+		return dimension != "" && dimension != BadgeDimensionDecades
+	}
+	return needs(r.Distinct) || needs(r.Per) ||
+		(r.Filter != nil && (len(r.Filter.Genres) > 0 || len(r.Filter.Countries) > 0 || len(r.Filter.Directors) > 0))
+}
+
+// badge défini en base : modifiable par les administrateurs sans redéploiement
+type Badge struct {
+	ID          uint      `gorm:"primaryKey"`
+	Code        string    `gorm:"size:50;uniqueIndex;not null"`
+	Name        string    `gorm:"size:100;not null"`
+	Description string    `gorm:"size:255"`
+	Icon        string    `gorm:"size:100"` // identifiant d'icône côté frontend
+	Rule        BadgeRule `gorm:"serializer:json;type:text"`
+	Position    int       `gorm:"not null;default:0"` // ordre d'affichage
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
+
+// badge obtenu ; jamais retiré, même si la règle change ensuite
+type UserBadge struct {
+	UserID    uint      `gorm:"primaryKey"`
+	BadgeID   uint      `gorm:"primaryKey"`
+	AwardedAt time.Time `gorm:"not null"`
+}
+
+// badges créés au premier démarrage ; ensuite, seuls les administrateurs les modifient
+func DefaultBadges() []Badge {
+	return []Badge{
+		{Code: "first-review", Name: "First review", Description: "Write your first review", Icon: "pen",
+			Rule: BadgeRule{Metric: BadgeMetricReviews, Threshold: 1}, Position: 10},
+		{Code: "films-100", Name: "Centurion", Description: "Watch 100 films", Icon: "film",
+			Rule: BadgeRule{Metric: BadgeMetricFilms, Threshold: 100}, Position: 20},
+		{Code: "decades-10", Name: "Time traveller", Description: "Watch films from 10 different decades", Icon: "hourglass",
+			Rule: BadgeRule{Metric: BadgeMetricFilms, Distinct: BadgeDimensionDecades, Threshold: 10}, Position: 30},
+		{Code: "director-month-5", Name: "Retrospective", Description: "Watch 5 films by the same director in a month", Icon: "clapper",
+			Rule: BadgeRule{Metric: BadgeMetricFilms, Per: BadgeDimensionDirectors, Period: BadgePeriodMonth, Threshold: 5}, Position: 40},
+	}
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// BlockerID bloque BlockedID : chacun disparaît des vues de l'autre et
+// aucune interaction (abonnement, like, commentaire, invitation) n'est possible
+type Block struct {
+	BlockerID uint      `gorm:"primaryKey"`
+	BlockedID uint      `gorm:"primaryKey;index"`
+	CreatedAt time.Time `gorm:"index"`
+
+	Blocker User `gorm:"foreignKey:BlockerID"`
+	Blocked User `gorm:"foreignKey:BlockedID"`
+}
+
+// MuterID masque MutedID : filtre uniquement les vues de MuterID, MutedID n'en sait rien
+type Mute struct {
+	MuterID   uint      `gorm:"primaryKey"`
+	MutedID   uint      `gorm:"primaryKey;index"`
+	CreatedAt time.Time `gorm:"index"`
+
+	Muter User `gorm:"foreignKey:MuterID"`
+	Muted User `gorm:"foreignKey:MutedID"`
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// objectif annuel : nombre de films à voir dans l'année (fuseau de l'utilisateur)
+type WatchGoal struct {
+	UserID    uint `gorm:"primaryKey"`
+	Year      int  `gorm:"primaryKey"`
+	Target    int  `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ce que compte un défi : des films, ou des valeurs distinctes (un film par pays, …)
+const (
+	ChallengeCountFilms     = "films"
+	ChallengeCountCountries = "countries"
+	ChallengeCountGenres    = "genres"
+	ChallengeCountDirectors = "directors"
+	ChallengeCountDecades   = "decades"
+)
+
+var ChallengeCountModes = []string{
+	ChallengeCountFilms,
+	ChallengeCountCountries,
+	ChallengeCountGenres,
+	ChallengeCountDirectors,
+	ChallengeCountDecades,
+}
+
+func IsValidChallengeCountMode(mode string) bool {
+	for _, m := range ChallengeCountModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// critères déclaratifs sur les attributs du film ; tous les critères renseignés
+// doivent être satisfaits (listes : au moins une valeur en commun)
+type ChallengeCriteria struct {
+	ReleaseYearMin *int     `json:"release_year_min,omitempty"`
+	ReleaseYearMax *int     `json:"release_year_max,omitempty"`
+	RuntimeMin     *int     `json:"runtime_min,omitempty"` // minutes
+	RuntimeMax     *int     `json:"runtime_max,omitempty"`
+	Genres         []int    `json:"genres,omitempty"`    // identifiants TMDB
+	Countries      []string `json:"countries,omitempty"` // codes ISO 3166-1
+	Directors      []int    `json:"directors,omitempty"` // identifiants TMDB
+}
+
+// défi défini par un administrateur, rejoint par les membres
+type Challenge struct {
+	ID          uint              `gorm:"primaryKey"`
+	Title       string            `gorm:"size:100;not null"`
+	Description string            `gorm:"size:500"`
+	Criteria    ChallengeCriteria `gorm:"serializer:json;type:text"`
+	CountBy     string            `gorm:"size:20;not null;default:films"` // ChallengeCount*
+	Target      int               `gorm:"not null"`
+	StartsAt    *time.Time        // seuls les visionnages dans [StartsAt, EndsAt[ comptent ; nil : pas de borne
+	EndsAt      *time.Time        `gorm:"index"`
+	CreatedByID *uint
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
+
+// genres, pays et réalisateurs (critères ou décompte) viennent des métadonnées TMDB du film
+func (c *Challenge) NeedsMetadata() bool {
+	switch c.CountBy {
+	case ChallengeCountCountries, ChallengeCountGenres, ChallengeCountDirectors:
+		return true
+	}
+	return len(c.Criteria.Genres) > 0 || len(c.Criteria.Countries) > 0 || len(c.Criteria.Directors) > 0
+}
+
+// le visionnage at entre-t-il dans la fenêtre du défi ?
+func (c *Challenge) Covers(at time.Time) bool {
+	if c.StartsAt != nil && at.Before(*c.StartsAt) {
+		return false
+	}
+	return c.EndsAt == nil || at.Before(*c.EndsAt)
+}
+
+type ChallengeParticipant struct {
+	ChallengeID uint `gorm:"primaryKey"`
+	UserID      uint `gorm:"primaryKey;index"`
+	Progress    int  `gorm:"not null;default:0"` // nombre d'entrées
+	CompletedAt *time.Time
+	CreatedAt   time.Time // date d'inscription
+	UpdatedAt   time.Time
+}
+
+// film compté dans un défi ; Key est ce qu'il couvre (film, code pays, genre…),
+// unique par participant : un film ne compte qu'une fois, un pays aussi
+type ChallengeEntry struct {
+	ChallengeID uint      `gorm:"primaryKey"`
+	UserID      uint      `gorm:"primaryKey"`
+	Key         string    `gorm:"primaryKey;column:entry_key;size:32"`
+	MovieID     uint      `gorm:"not null;index"`
+	WatchedAt   time.Time `gorm:"not null"`
+	CreatedAt   time.Time
+}
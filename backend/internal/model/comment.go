@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// commentaire sur une critique (identifiée par ReviewUserID + MovieID).
+// RootID pointe vers le commentaire de premier niveau du fil (nil pour lui-même),
+// ParentID vers le commentaire auquel on répond
+type ReviewComment struct {
+	ID           uint   `gorm:"primaryKey"`
+	ReviewUserID uint   `gorm:"not null;index:idx_review_comments_review"`
+	MovieID      uint   `gorm:"not null;index:idx_review_comments_review"`
+	AuthorID     uint   `gorm:"not null;index"`
+	ParentID     *uint  `gorm:"index"`
+	RootID       *uint  `gorm:"index"`
+	Content      string `gorm:"type:text;not null"`
+	EditedAt     *time.Time
+	DeletedAt    *time.Time // tombstone : le commentaire reste pour garder le fil lisible
+	CreatedAt    time.Time  `gorm:"index"`
+	UpdatedAt    time.Time
+
+	Author User `gorm:"foreignKey:AuthorID"`
+}
+
+func (c *ReviewComment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
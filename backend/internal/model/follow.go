@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// relation d'abonnement : FollowerID suit FollowedID (non réciproque)
+type Follow struct {
+	FollowerID uint      `gorm:"primaryKey"`
+	FollowedID uint      `gorm:"primaryKey;index"`
+	CreatedAt  time.Time `gorm:"index"`
+
+	Follower User `gorm:"foreignKey:FollowerID"`
+	Followed User `gorm:"foreignKey:FollowedID"`
+}
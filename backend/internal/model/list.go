@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+// liste de films créée par un utilisateur ; classée (IsRanked) ou non
+type UserList struct {
+	ID          uint   `gorm:"primaryKey"`
+	UserID      uint   `gorm:"not null;index"`
+	Title       string `gorm:"size:100;not null"`
+	Description string `gorm:"type:text"`
+	IsRanked    bool   `gorm:"default:false"`
+	IsPublic    bool   `gorm:"default:true;index"`
+	Version     int    `gorm:"not null;default:1"` // incrémentée à chaque modification du contenu (verrou optimiste)
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	User    User            `gorm:"foreignKey:UserID"`
+	Entries []UserListEntry `gorm:"foreignKey:ListID"`
+}
+
+// film d'une liste ; Position donne l'ordre d'affichage (et le rang si la liste est classée)
+type UserListEntry struct {
+	ID        uint   `gorm:"primaryKey"`
+	ListID    uint   `gorm:"not null;uniqueIndex:idx_list_entries_movie;index:idx_list_entries_position"`
+	MovieID   uint   `gorm:"not null;uniqueIndex:idx_list_entries_movie"`
+	Position  int    `gorm:"not null;index:idx_list_entries_position"`
+	Note      string `gorm:"type:text"`
+	AddedByID *uint  `gorm:"index"` // membre qui a ajouté le film
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Movie   Movie `gorm:"foreignKey:MovieID"`
+	AddedBy *User `gorm:"foreignKey:AddedByID"`
+}
+
+// rôles d'un collaborateur sur une liste
+const (
+	ListRoleOwner  = "owner"
+	ListRoleEditor = "editor"
+	ListRoleViewer = "viewer"
+)
+
+const (
+	ListInvitationPending  = "pending"
+	ListInvitationAccepted = "accepted"
+)
+
+// utilisateur invité sur une liste ; une invitation refusée est supprimée
+type ListCollaborator struct {
+	ListID      uint   `gorm:"primaryKey"`
+	UserID      uint   `gorm:"primaryKey;index"`
+	Role        string `gorm:"size:20;not null"`
+	Status      string `gorm:"size:20;not null;default:pending;index"`
+	InvitedByID uint   `gorm:"not null"`
+	CreatedAt   time.Time
+	RespondedAt *time.Time
+
+	List UserList `gorm:"foreignKey:ListID"`
+	User User     `gorm:"foreignKey:UserID"`
+}
+
+func IsValidListRole(role string) bool {
+	return role == ListRoleEditor || role == ListRoleViewer
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// lien de connexion sans mot de passe (seul le hash du token est stocké)
+type MagicLinkToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"size:64;uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"index"`
+
+	User User `gorm:"foreignKey:UserID"`
+}
@@ -27,6 +27,9 @@ type Movie struct {
 	UpdatedAt           time.Time      `json:"updated_at"`
 	DeletedAt           gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// genres, pays et générique importés depuis TMDB ; nil tant que ce n'est pas fait
+	MetadataSyncedAt *time.Time `json:"-"`
+
 	// relations
 	Genres    []Genre     `gorm:"many2many:movie_genres;"`
 	Countries []Country   `gorm:"many2many:movie_countries;"`
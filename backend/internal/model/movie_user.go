@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Track struct {
 	UserID      uint       `gorm:"primaryKey"`
@@ -9,6 +13,7 @@ type Track struct {
 	IsFavorite  bool       `gorm:"default:false;index"`
 	IsWatchlist bool       `gorm:"default:false;index"`
 	WatchedDate *time.Time `gorm:"index"`
+	WatchCount  int        `gorm:"not null;default:0"` // visionnages enregistrés via LogMovie (> 1 : revu)
 	UpdatedAt   time.Time
 	CreatedAt   time.Time
 
@@ -28,13 +33,43 @@ type Rate struct {
 }
 
 type Review struct {
-	UserID    uint   `gorm:"primaryKey"`
-	MovieID   uint   `gorm:"primaryKey"`
-	Content   string `gorm:"type:text;not null"`
-	IsSpoiler bool   `gorm:"default:false"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	UserID         uint       `gorm:"primaryKey"`
+	MovieID        uint       `gorm:"primaryKey"`
+	Content        string     `gorm:"type:text;not null"` // source Markdown
+	ContentHTML    string     `gorm:"type:text"`          // rendu assaini de Content (utils.RenderMarkdown)
+	IsSpoiler      bool       `gorm:"default:false"`
+	CommentsLocked bool       `gorm:"default:false"` // l'auteur a fermé les commentaires
+	SpoilerForced  bool       `gorm:"default:false"` // imposé par la modération : l'auteur ne peut plus retirer le flag
+	HiddenAt       *time.Time `gorm:"index"`         // masquée par la modération, visible de son seul auteur
+	EditedAt       *time.Time // dernière modification du contenu ; nil si jamais modifiée
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 
 	User  User  `gorm:"foreignKey:UserID"`
 	Movie Movie `gorm:"foreignKey:MovieID"`
 }
+
+// version antérieure d'une critique, archivée à chaque modification du contenu
+// ou du flag spoiler ; la version courante reste dans Review. À la suppression
+// de la critique, sa dernière version est archivée et l'historique soft-deleted :
+// il reste consultable par la modération
+type ReviewRevision struct {
+	ID         uint           `gorm:"primaryKey"`
+	UserID     uint           `gorm:"not null;index:idx_review_revisions_review"`
+	MovieID    uint           `gorm:"not null;index:idx_review_revisions_review"`
+	Content    string         `gorm:"type:text;not null"`
+	IsSpoiler  bool           `gorm:"default:false"`
+	WrittenAt  time.Time      // publication de cette version (création ou édition précédente)
+	ReplacedAt time.Time      `gorm:"index"`
+	DeletedAt  gorm.DeletedAt `gorm:"index"` // critique supprimée
+}
+
+// like d'une critique ; une critique est identifiée par (ReviewUserID, MovieID)
+type ReviewLike struct {
+	UserID       uint      `gorm:"primaryKey"`
+	ReviewUserID uint      `gorm:"primaryKey;index:idx_review_likes_review"`
+	MovieID      uint      `gorm:"primaryKey;index:idx_review_likes_review"`
+	CreatedAt    time.Time `gorm:"index"`
+
+	User User `gorm:"foreignKey:UserID"`
+}
@@ -0,0 +1,142 @@
+package model
+
+import "time"
+
+// types de notifications
+const (
+	NotificationEmailVerified  = "auth.email_verified"
+	NotificationFollowedReview = "social.followed_review"
+	NotificationBadgeEarned    = "achievement.badge_earned"
+	// changements sur le compte, par un admin ou par l'utilisateur
+	NotificationAccountSuspended   = "account.suspended"
+	NotificationAccountUnsuspended = "account.unsuspended"
+	NotificationAccountVerified    = "account.verified"
+	NotificationAccountRestored    = "account.restored"
+	NotificationRoleChanged        = "account.role_changed"
+	NotificationProfileUpdated     = "account.profile_updated"
+	// toujours livrée : absente de NotificationTypes, donc des préférences
+	NotificationModerationNotice = "moderation.notice"
+)
+
+// types connus, dans l'ordre d'affichage des préférences
+var NotificationTypes = []string{
+	NotificationEmailVerified,
+	NotificationFollowedReview,
+	NotificationBadgeEarned,
+	NotificationAccountSuspended,
+	NotificationAccountUnsuspended,
+	NotificationAccountVerified,
+	NotificationAccountRestored,
+	NotificationRoleChanged,
+	NotificationProfileUpdated,
+}
+
+func IsValidNotificationType(notificationType string) bool {
+	for _, t := range NotificationTypes {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
+
+// contenu typé d'une notification, stocké en JSON dans Notification.Payload
+type NotificationPayload interface {
+	NotificationType() string
+}
+
+type EmailVerifiedPayload struct {
+	Email string `json:"email"`
+}
+
+func (EmailVerifiedPayload) NotificationType() string { return NotificationEmailVerified }
+
+// un utilisateur suivi a publié une critique
+type FollowedReviewPayload struct {
+	TmdbID     int    `json:"tmdb_id"`
+	MovieTitle string `json:"movie_title"`
+	IsSpoiler  bool   `json:"is_spoiler"`
+}
+
+func (FollowedReviewPayload) NotificationType() string { return NotificationFollowedReview }
+
+// l'utilisateur a obtenu un badge
+type BadgeEarnedPayload struct {
+	BadgeID uint   `json:"badge_id"`
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Icon    string `json:"icon"`
+}
+
+func (BadgeEarnedPayload) NotificationType() string { return NotificationBadgeEarned }
+
+// compte suspendu par un admin ; Until nil => indéfiniment
+type AccountSuspendedPayload struct {
+	Reason string     `json:"reason"`
+	Until  *time.Time `json:"until,omitempty"`
+}
+
+func (AccountSuspendedPayload) NotificationType() string { return NotificationAccountSuspended }
+
+type AccountUnsuspendedPayload struct{}
+
+func (AccountUnsuspendedPayload) NotificationType() string { return NotificationAccountUnsuspended }
+
+// email marqué vérifié par un admin
+type AccountVerifiedPayload struct {
+	Email string `json:"email"`
+}
+
+func (AccountVerifiedPayload) NotificationType() string { return NotificationAccountVerified }
+
+// compte supprimé puis restauré par un admin
+type AccountRestoredPayload struct {
+	Username string `json:"username"`
+}
+
+func (AccountRestoredPayload) NotificationType() string { return NotificationAccountRestored }
+
+type RoleChangedPayload struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func (RoleChangedPayload) NotificationType() string { return NotificationRoleChanged }
+
+// champs du profil modifiés (noms JSON de la requête)
+type ProfileUpdatedPayload struct {
+	Fields []string `json:"fields"`
+}
+
+func (ProfileUpdatedPayload) NotificationType() string { return NotificationProfileUpdated }
+
+// la modération a pris une mesure sur un contenu de l'utilisateur
+type ModerationNoticePayload struct {
+	Action     string `json:"action"`
+	Reason     string `json:"reason"`
+	Note       string `json:"note,omitempty"`
+	TmdbID     int    `json:"tmdb_id,omitempty"`
+	MovieTitle string `json:"movie_title,omitempty"`
+}
+
+func (ModerationNoticePayload) NotificationType() string { return NotificationModerationNotice }
+
+type Notification struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"not null;index:idx_notifications_inbox,priority:1"`
+	Type      string     `gorm:"size:50;not null"`
+	ActorID   *uint      // nil => notification système
+	Payload   string     `gorm:"type:text"` // JSON, structure selon Type
+	ReadAt    *time.Time `gorm:"index"`
+	CreatedAt time.Time  `gorm:"index:idx_notifications_inbox,priority:2"`
+
+	Actor *User `gorm:"foreignKey:ActorID"`
+}
+
+// préférence par type ; sans ligne, le type est activé
+type NotificationPreference struct {
+	UserID    uint   `gorm:"primaryKey"`
+	Type      string `gorm:"primaryKey;size:50"`
+	Enabled   bool   `gorm:"not null"`
+	UpdatedAt time.Time
+}
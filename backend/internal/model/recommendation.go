@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// raisons d'une recommandation
+const (
+	RecommendationReasonRated    = "rated"    // film bien noté, apprécié aussi par les mêmes membres
+	RecommendationReasonDirector = "director" // même réalisateur qu'un film aimé
+	RecommendationReasonWriter   = "writer"
+	RecommendationReasonActor    = "actor"
+	RecommendationReasonGenre    = "genre"
+	RecommendationReasonPopular  = "popular" // pas encore d'historique : films les mieux notés du site
+)
+
+// film de l'historique à l'origine de la recommandation, figé au calcul
+type RecommendationReason struct {
+	Kind   string   `json:"kind"`
+	TmdbID int      `json:"tmdb_id,omitempty"`
+	Title  string   `json:"title,omitempty"`
+	Rating *float32 `json:"rating,omitempty"` // note donnée par l'utilisateur
+	Name   string   `json:"name,omitempty"`   // personne ou genre en commun
+}
+
+// recommandations précalculées en tâche de fond, remplacées à chaque calcul
+type Recommendation struct {
+	UserID     uint                   `gorm:"primaryKey"`
+	MovieID    uint                   `gorm:"primaryKey"`
+	Position   int                    `gorm:"not null;index"` // 1 = meilleure
+	Score      float64                `gorm:"not null"`
+	Reasons    []RecommendationReason `gorm:"serializer:json;type:text"`
+	ComputedAt time.Time
+
+	Movie Movie `gorm:"foreignKey:MovieID"`
+}
+
+// état des recommandations d'un utilisateur : elles sont à recalculer si
+// l'historique a changé depuis le début du dernier calcul
+type RecommendationRun struct {
+	UserID     uint       `gorm:"primaryKey"`
+	ComputedAt *time.Time `gorm:"index"` // début du dernier calcul
+	ChangedAt  *time.Time // dernière modification de l'historique
+}
@@ -0,0 +1,109 @@
+package model
+
+import "time"
+
+// contenus signalables
+const (
+	ReportTargetReview  = "review"
+	ReportTargetProfile = "profile" // bio, nom d'utilisateur
+	ReportTargetAvatar  = "avatar"
+)
+
+// motifs de signalement
+const (
+	ReportReasonSpam          = "spam"
+	ReportReasonHarassment    = "harassment"
+	ReportReasonHateSpeech    = "hate_speech"
+	ReportReasonSexual        = "sexual_content"
+	ReportReasonSpoiler       = "unmarked_spoiler"
+	ReportReasonImpersonation = "impersonation"
+	ReportReasonOther         = "other"
+	// réservé aux signalements automatiques, ne peut pas être choisi par un utilisateur
+	ReportReasonContentPolicy = "content_policy"
+)
+
+// motifs acceptés pour chaque type de contenu
+var ReportReasons = map[string][]string{
+	ReportTargetReview:  {ReportReasonSpam, ReportReasonHarassment, ReportReasonHateSpeech, ReportReasonSexual, ReportReasonSpoiler, ReportReasonOther},
+	ReportTargetProfile: {ReportReasonSpam, ReportReasonHarassment, ReportReasonHateSpeech, ReportReasonSexual, ReportReasonImpersonation, ReportReasonOther},
+	ReportTargetAvatar:  {ReportReasonHarassment, ReportReasonHateSpeech, ReportReasonSexual, ReportReasonImpersonation, ReportReasonOther},
+}
+
+func IsValidReportReason(targetType, reason string) bool {
+	for _, r := range ReportReasons[targetType] {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// états d'un signalement
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusResolved  = "resolved"  // une mesure a été prise
+	ReportStatusDismissed = "dismissed" // classé sans suite
+)
+
+// mesures de modération applicables depuis un signalement
+const (
+	ModerationHideReview   = "hide_review"
+	ModerationUnhideReview = "unhide_review"
+	ModerationForceSpoiler = "force_spoiler"
+	ModerationClearBio     = "clear_bio"
+	ModerationClearAvatar  = "clear_avatar"
+	ModerationWarn         = "warn"
+	ModerationSuspend      = "suspend"
+	ModerationDismiss      = "dismiss"
+	ModerationReopen       = "reopen"
+)
+
+// mesures valables pour chaque type de contenu
+var ModerationActions = map[string][]string{
+	ReportTargetReview:  {ModerationHideReview, ModerationUnhideReview, ModerationForceSpoiler, ModerationWarn, ModerationSuspend, ModerationDismiss, ModerationReopen},
+	ReportTargetProfile: {ModerationClearBio, ModerationClearAvatar, ModerationWarn, ModerationSuspend, ModerationDismiss, ModerationReopen},
+	ReportTargetAvatar:  {ModerationClearAvatar, ModerationWarn, ModerationSuspend, ModerationDismiss, ModerationReopen},
+}
+
+func IsValidModerationAction(targetType, action string) bool {
+	for _, a := range ModerationActions[targetType] {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// signalement d'un contenu par un utilisateur. une critique est identifiée
+// par son auteur (TargetUserID) et MovieID ; un profil ou un avatar par TargetUserID seul
+type Report struct {
+	ID           uint   `gorm:"primaryKey"`
+	ReporterID   *uint  `gorm:"index"` // nil : signalement automatique (politique de contenu)
+	TargetType   string `gorm:"size:20;not null;index:idx_reports_target,priority:1"`
+	TargetUserID uint   `gorm:"not null;index:idx_reports_target,priority:2"`
+	MovieID      *uint  `gorm:"index:idx_reports_target,priority:3"`
+	Reason       string `gorm:"size:30;not null;index"`
+	Details      string `gorm:"size:1000"`
+	Snapshot     string `gorm:"type:text"` // contenu au moment du signalement
+	Status       string `gorm:"size:20;not null;default:open;index"`
+	ResolvedByID *uint
+	ResolvedAt   *time.Time
+	CreatedAt    time.Time `gorm:"index"`
+	UpdatedAt    time.Time
+
+	Reporter   *User  `gorm:"foreignKey:ReporterID"`
+	TargetUser User   `gorm:"foreignKey:TargetUserID"`
+	Movie      *Movie `gorm:"foreignKey:MovieID"`
+}
+
+// historique des mesures prises sur un signalement (ajout seul)
+type ReportAction struct {
+	ID          uint      `gorm:"primaryKey"`
+	ReportID    uint      `gorm:"not null;index"`
+	ModeratorID uint      `gorm:"not null;index"`
+	Action      string    `gorm:"size:30;not null"`
+	Note        string    `gorm:"size:1000"`
+	CreatedAt   time.Time `gorm:"index"`
+
+	Moderator User `gorm:"foreignKey:ModeratorID"`
+}
@@ -0,0 +1,88 @@
+package model
+
+import "time"
+
+// rôles système (toujours présents, non modifiables via l'API)
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// permissions vérifiées par middleware.RequirePermission
+const (
+	PermUsersRead       = "users:read"
+	PermUsersDelete     = "users:delete"
+	PermUsersManage     = "users:manage" // suspension, vérification forcée, restauration
+	PermRolesManage     = "roles:manage"
+	PermAuditRead       = "audit:read"
+	PermReviewsModerate = "reviews:moderate"
+	PermReportsManage   = "reports:manage" // file des signalements et mesures de modération
+
+	PermChallengesManage = "challenges:manage" // défis proposés aux membres
+	PermBadgesManage     = "badges:manage"     // définitions des badges
+)
+
+var AllPermissions = []string{
+	PermUsersRead,
+	PermUsersDelete,
+	PermUsersManage,
+	PermRolesManage,
+	PermAuditRead,
+	PermReviewsModerate,
+	PermReportsManage,
+	PermChallengesManage,
+	PermBadgesManage,
+}
+
+type Role struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"size:50;uniqueIndex;not null" json:"name"`
+	Description string    `gorm:"size:255" json:"description"`
+	Permissions []string  `gorm:"serializer:json;type:text" json:"permissions"`
+	IsSystem    bool      `gorm:"default:false" json:"is_system"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (r *Role) HasPermission(permission string) bool {
+	for _, p := range r.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidPermission(permission string) bool {
+	for _, p := range AllPermissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// rôles créés (et resynchronisés) au démarrage
+func DefaultRoles() []Role {
+	return []Role{
+		{
+			Name:        RoleUser,
+			Description: "Regular member",
+			Permissions: []string{},
+			IsSystem:    true,
+		},
+		{
+			Name:        RoleModerator,
+			Description: "Manages community content, cannot delete accounts",
+			Permissions: []string{PermUsersRead, PermReviewsModerate, PermReportsManage},
+			IsSystem:    true,
+		},
+		{
+			Name:        RoleAdmin,
+			Description: "Full access",
+			Permissions: append([]string{}, AllPermissions...),
+			IsSystem:    true,
+		},
+	}
+}
@@ -20,13 +20,53 @@ type User struct {
 	IsVerified        bool           `gorm:"default:false" json:"is_verified"`
 	VerificationToken *string        `json:"-"`
 	TokenExpiresAt    *time.Time     `json:"-"`
-	IsAdmin           bool           `gorm:"default:false" json:"is_admin"`
+	Role              string         `gorm:"size:50;not null;default:user;index" json:"role"` // Role.Name
+	SuspendedAt       *time.Time     `gorm:"index" json:"-"`
+	SuspendedUntil    *time.Time     `json:"-"` // nil => suspension sans limite
+	SuspensionReason  *string        `gorm:"size:500" json:"-"`
 	FavoriteFilms     []Movie        `gorm:"many2many:user_favorite_films;" json:"favorite_films,omitempty"`
 	CreatedAt         time.Time      `json:"created_at"`
 	UpdatedAt         time.Time      `json:"updated_at"`
 	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
+	DeletedUsername   *string        `gorm:"size:50" json:"-"`  // identifiants d'origine, remis par RestoreUser
+	DeletedEmail      *string        `gorm:"size:255" json:"-"` // (username/email sont suffixés à la suppression)
+
+	// confidentialité ; les booléens valent true par défaut en base, les
+	// passer à false via UpdateFields (GORM ignore false à la création)
+	ProfileVisibility string `gorm:"size:20;not null;default:public" json:"profile_visibility"`
+	ShowWatchlist     bool   `gorm:"not null;default:true" json:"show_watchlist"`
+	ShowRatings       bool   `gorm:"not null;default:true" json:"show_ratings"`
+	ShowDiaryDates    bool   `gorm:"not null;default:true" json:"show_diary_dates"` // dates de visionnage
+	Discoverable      bool   `gorm:"not null;default:true" json:"discoverable"`     // recherche et recommandations
+	Comparable        bool   `gorm:"not null;default:true" json:"comparable"`       // comparaison des goûts par les autres membres
+
+	// fuseau IANA : bornes des jours et des années dans les statistiques
+	TimeZone string `gorm:"size:64;not null;default:Europe/Paris" json:"time_zone"`
+}
+
+// fuseau des comptes qui n'en ont pas choisi, aligné sur la session PostgreSQL
+const DefaultTimeZone = "Europe/Paris"
+
+// fuseau de l'utilisateur ; fuseau par défaut s'il est vide ou inconnu
+func (u *User) Zone() *time.Location {
+	if u.TimeZone != "" {
+		if loc, err := time.LoadLocation(u.TimeZone); err == nil {
+			return loc
+		}
+	}
+	if loc, err := time.LoadLocation(DefaultTimeZone); err == nil {
+		return loc
+	}
+	return time.UTC
 }
 
+// qui peut voir le profil et le contenu d'un utilisateur
+const (
+	ProfileVisibilityPublic    = "public"
+	ProfileVisibilityFollowers = "followers" // abonnés uniquement
+	ProfileVisibilityPrivate   = "private"   // l'utilisateur seul
+)
+
 // hook GORM juste avant insert
 func (u *User) BeforeCreate(tx *gorm.DB) error {
 	// Validation supplémentaire si besoin
@@ -35,3 +75,11 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// une suspension avec date de fin expire d'elle-même
+func (u *User) IsSuspended(now time.Time) bool {
+	if u.SuspendedAt == nil {
+		return false
+	}
+	return u.SuspendedUntil == nil || now.Before(*u.SuspendedUntil)
+}
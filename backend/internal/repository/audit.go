@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// filtres de la liste admin (champs vides => ignorés)
+type AuditFilter struct {
+	Action   string
+	ActorID  *uint
+	TargetID *uint
+	From     *time.Time
+	To       *time.Time
+}
+
+func (r *AuditRepository) Create(event *model.AuditEvent) error {
+	return r.db.Create(event).Error
+}
+
+func (r *AuditRepository) List(filter AuditFilter, page, limit int) ([]model.AuditEvent, int64, error) {
+	query := r.db.Model(&model.AuditEvent{})
+
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
+	}
+
+	return r.paginate(query, page, limit)
+}
+
+// événements visibles par le user lui-même : ses propres actions et les
+// tentatives anonymes sur son compte (pas les actions des admins)
+func (r *AuditRepository) ListForUser(userID uint, page, limit int) ([]model.AuditEvent, int64, error) {
+	query := r.db.Model(&model.AuditEvent{}).
+		Where("actor_id = ? OR (target_id = ? AND actor_id IS NULL)", userID, userID)
+
+	return r.paginate(query, page, limit)
+}
+
+// date du dernier événement d'un des types donnés déclenché par le user
+func (r *AuditRepository) LastByActor(actorID uint, actions ...string) (*time.Time, error) {
+	var event model.AuditEvent
+	err := r.db.Where("actor_id = ? AND action IN ?", actorID, actions).
+		Order("created_at DESC").
+		First(&event).Error
+	if err != nil {
+		return nil, err
+	}
+	return &event.CreatedAt, nil
+}
+
+func (r *AuditRepository) paginate(query *gorm.DB, page, limit int) ([]model.AuditEvent, int64, error) {
+	var events []model.AuditEvent
+	var total int64
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+
+	if err := query.
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupAuditTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	if err := db.AutoMigrate(&model.AuditEvent{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	return db
+}
+
+func TestAuditRepository_List(t *testing.T) {
+	db := setupAuditTestDB(t)
+	repo := NewAuditRepository(db)
+
+	alice, bob, admin := uint(1), uint(2), uint(3)
+	repo.Create(&model.AuditEvent{Action: model.AuditLogin, ActorID: &alice, TargetID: &alice})
+	repo.Create(&model.AuditEvent{Action: model.AuditLoginFailed, TargetID: &alice})
+	repo.Create(&model.AuditEvent{Action: model.AuditLogin, ActorID: &bob, TargetID: &bob})
+	repo.Create(&model.AuditEvent{Action: model.AuditAdminUserDeleted, ActorID: &admin, TargetID: &alice})
+
+	tests := []struct {
+		name   string
+		filter AuditFilter
+		want   int64
+	}{
+		{"No filter", AuditFilter{}, 4},
+		{"By action", AuditFilter{Action: model.AuditLogin}, 2},
+		{"By actor", AuditFilter{ActorID: &admin}, 1},
+		{"By target", AuditFilter{TargetID: &alice}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, total, err := repo.List(tt.filter, 1, 10)
+			if err != nil || total != tt.want {
+				t.Errorf("expected %d events, got %d (%v)", tt.want, total, err)
+			}
+		})
+	}
+
+	future := time.Now().Add(time.Hour)
+	if _, total, _ := repo.List(AuditFilter{From: &future}, 1, 10); total != 0 {
+		t.Errorf("expected no events after %v, got %d", future, total)
+	}
+
+	// user view: own actions and anonymous attempts, not admin actions
+	events, total, err := repo.ListForUser(alice, 1, 10)
+	if err != nil || total != 2 {
+		t.Fatalf("expected 2 events for alice, got %d (%v)", total, err)
+	}
+	for _, e := range events {
+		if e.Action == model.AuditAdminUserDeleted {
+			t.Errorf("expected admin actions to be hidden from the user")
+		}
+	}
+}
+
+func TestAuditRepository_AppendOnly(t *testing.T) {
+	db := setupAuditTestDB(t)
+	repo := NewAuditRepository(db)
+
+	event := &model.AuditEvent{Action: model.AuditLogin}
+	repo.Create(event)
+
+	event.Action = model.AuditLoginFailed
+	if err := db.Save(event).Error; err == nil {
+		t.Errorf("expected update to be rejected")
+	}
+	if err := db.Delete(event).Error; err == nil {
+		t.Errorf("expected delete to be rejected")
+	}
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BadgeRepository struct {
+	db *gorm.DB
+}
+
+func NewBadgeRepository(db *gorm.DB) *BadgeRepository {
+	return &BadgeRepository{db: db}
+}
+
+func (r *BadgeRepository) List() ([]model.Badge, error) {
+	var badges []model.Badge
+	err := r.db.Order("position ASC, id ASC").Find(&badges).Error
+	return badges, err
+}
+
+func (r *BadgeRepository) GetByID(id uint) (*model.Badge, error) {
+	var badge model.Badge
+	if err := r.db.First(&badge, id).Error; err != nil {
+		return nil, err
+	}
+	return &badge, nil
+}
+
+// badges supprimés compris : un code n'est jamais réutilisé
+func (r *BadgeRepository) CodeExists(code string) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&model.Badge{}).Where("code = ?", code).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *BadgeRepository) Create(badge *model.Badge) error {
+	return r.db.Create(badge).Error
+}
+
+func (r *BadgeRepository) Update(badge *model.Badge) error {
+	return r.db.Save(badge).Error
+}
+
+func (r *BadgeRepository) Delete(id uint) error {
+	return checkAffected(r.db.Delete(&model.Badge{}, id))
+}
+
+// badges obtenus par l'utilisateur, indexés par badge
+func (r *BadgeRepository) Earned(userID uint) (map[uint]model.UserBadge, error) {
+	var earned []model.UserBadge
+	if err := r.db.Where("user_id = ?", userID).Find(&earned).Error; err != nil {
+		return nil, err
+	}
+	byBadge := make(map[uint]model.UserBadge, len(earned))
+	for _, e := range earned {
+		byBadge[e.BadgeID] = e
+	}
+	return byBadge, nil
+}
+
+// false si le badge était déjà obtenu
+func (r *BadgeRepository) Award(award *model.UserBadge) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(award)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *BadgeRepository) CountReviews(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Review{}).Where("user_id = ? AND content <> ''", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *BadgeRepository) CountRatings(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Rate{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// visionnages au-delà du premier, tous films confondus
+func (r *BadgeRepository) CountRewatches(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Track{}).
+		Select("COALESCE(SUM(watch_count - 1), 0)").
+		Where("user_id = ? AND watch_count > 1", userID).
+		Scan(&count).Error
+	return count, err
+}
+
+// utilisateurs ayant au moins un film suivi ou noté, par lots (après afterID)
+func (r *BadgeRepository) ActiveUserIDs(afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.User{}).
+		Where("id > ?", afterID).
+		Where("(EXISTS (SELECT 1 FROM tracks WHERE tracks.user_id = users.id) OR EXISTS (SELECT 1 FROM reviews WHERE reviews.user_id = users.id))").
+		Order("id").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
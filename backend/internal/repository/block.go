@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// le viewer a bloqué l'auteur ou a été bloqué par lui (%[1]s : colonne de l'auteur)
+const blockedBetweenSQL = "EXISTS (SELECT 1 FROM blocks WHERE (blocks.blocker_id = ? AND blocks.blocked_id = %[1]s) " +
+	"OR (blocks.blocker_id = %[1]s AND blocks.blocked_id = ?))"
+
+const mutedBySQL = "EXISTS (SELECT 1 FROM mutes WHERE mutes.muter_id = ? AND mutes.muted_id = %[1]s)"
+
+// exclut le contenu dont l'auteur (column) et le viewer se bloquent ;
+// à appliquer à toute requête qui renvoie le contenu d'autres utilisateurs
+func notBlockedFor(viewerID uint, column string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("NOT "+sqlColumn(blockedBetweenSQL, column), viewerID, viewerID)
+	}
+}
+
+// comme notBlockedFor, et retire aussi les utilisateurs masqués par le viewer
+// (fils, listes de critiques, boîte de notifications)
+func hiddenFilterFor(viewerID uint, column string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Scopes(notBlockedFor(viewerID, column)).
+			Where("NOT "+sqlColumn(mutedBySQL, column), viewerID)
+	}
+}
+
+type BlockRepository struct {
+	db *gorm.DB
+}
+
+func NewBlockRepository(db *gorm.DB) *BlockRepository {
+	return &BlockRepository{db: db}
+}
+
+// idempotent ; supprime les abonnements et les collaborations de listes
+// entre les deux utilisateurs, dans les deux sens
+func (r *BlockRepository) Block(blockerID, blockedID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.Block{BlockerID: blockerID, BlockedID: blockedID}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("(follower_id = ? AND followed_id = ?) OR (follower_id = ? AND followed_id = ?)",
+			blockerID, blockedID, blockedID, blockerID).
+			Delete(&model.Follow{}).Error; err != nil {
+			return err
+		}
+
+		ownedBy := "list_id IN (SELECT id FROM user_lists WHERE user_id = ?)"
+		return tx.Where("(user_id = ? AND "+ownedBy+") OR (user_id = ? AND "+ownedBy+")",
+			blockedID, blockerID, blockerID, blockedID).
+			Delete(&model.ListCollaborator{}).Error
+	})
+}
+
+func (r *BlockRepository) Unblock(blockerID, blockedID uint) error {
+	return r.db.Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Delete(&model.Block{}).Error
+}
+
+// vrai si l'un des deux a bloqué l'autre
+func (r *BlockRepository) IsBlockedBetween(userID, otherID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Block{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", userID, otherID, otherID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// utilisateurs bloqués par userID, les plus récents d'abord
+func (r *BlockRepository) ListBlocked(userID uint, page, limit int) ([]model.Block, int64, error) {
+	var blocks []model.Block
+	var total int64
+
+	query := r.db.Model(&model.Block{}).
+		Joins("JOIN users ON users.id = blocks.blocked_id AND users.deleted_at IS NULL").
+		Where("blocks.blocker_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.
+		Preload("Blocked").
+		Order("blocks.created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&blocks).Error
+	return blocks, total, err
+}
+
+// idempotent
+func (r *BlockRepository) Mute(muterID, mutedID uint) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.Mute{MuterID: muterID, MutedID: mutedID}).Error
+}
+
+func (r *BlockRepository) Unmute(muterID, mutedID uint) error {
+	return r.db.Where("muter_id = ? AND muted_id = ?", muterID, mutedID).
+		Delete(&model.Mute{}).Error
+}
+
+func (r *BlockRepository) ListMuted(userID uint, page, limit int) ([]model.Mute, int64, error) {
+	var mutes []model.Mute
+	var total int64
+
+	query := r.db.Model(&model.Mute{}).
+		Joins("JOIN users ON users.id = mutes.muted_id AND users.deleted_at IS NULL").
+		Where("mutes.muter_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.
+		Preload("Muted").
+		Order("mutes.created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&mutes).Error
+	return mutes, total, err
+}
+
+// utilisateurs que viewerID ne doit pas voir : bloqués dans un sens ou
+// dans l'autre, ou masqués par lui (filtrage de résultats mis en cache)
+func (r *BlockRepository) HiddenUserIDs(viewerID uint) (map[uint]bool, error) {
+	var blocked, blockedBy, muted []uint
+	if err := r.db.Model(&model.Block{}).Where("blocker_id = ?", viewerID).Pluck("blocked_id", &blocked).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Model(&model.Block{}).Where("blocked_id = ?", viewerID).Pluck("blocker_id", &blockedBy).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Model(&model.Mute{}).Where("muter_id = ?", viewerID).Pluck("muted_id", &muted).Error; err != nil {
+		return nil, err
+	}
+
+	hidden := make(map[uint]bool, len(blocked)+len(blockedBy)+len(muted))
+	for _, ids := range [][]uint{blocked, blockedBy, muted} {
+		for _, id := range ids {
+			hidden[id] = true
+		}
+	}
+	return hidden, nil
+}
+
+// les colonnes sont des constantes du package, jamais une saisie utilisateur
+func sqlColumn(template, column string) string {
+	return fmt.Sprintf(template, column)
+}
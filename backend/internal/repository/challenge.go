@@ -0,0 +1,304 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChallengeRepository struct {
+	db *gorm.DB
+}
+
+func NewChallengeRepository(db *gorm.DB) *ChallengeRepository {
+	return &ChallengeRepository{db: db}
+}
+
+// OBJECTIFS
+
+func (r *ChallengeRepository) GetGoal(userID uint, year int) (*model.WatchGoal, error) {
+	var goal model.WatchGoal
+	if err := r.db.Where("user_id = ? AND year = ?", userID, year).First(&goal).Error; err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+func (r *ChallengeRepository) UpsertGoal(goal *model.WatchGoal) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "year"}},
+		DoUpdates: clause.AssignmentColumns([]string{"target", "updated_at"}),
+	}).Create(goal).Error
+}
+
+func (r *ChallengeRepository) DeleteGoal(userID uint, year int) error {
+	return checkAffected(r.db.Where("user_id = ? AND year = ?", userID, year).Delete(&model.WatchGoal{}))
+}
+
+// DÉFIS
+
+func (r *ChallengeRepository) Create(challenge *model.Challenge) error {
+	return r.db.Create(challenge).Error
+}
+
+func (r *ChallengeRepository) Update(challenge *model.Challenge) error {
+	return r.db.Save(challenge).Error
+}
+
+func (r *ChallengeRepository) Delete(id uint) error {
+	return checkAffected(r.db.Delete(&model.Challenge{}, id))
+}
+
+func (r *ChallengeRepository) GetByID(id uint) (*model.Challenge, error) {
+	var challenge model.Challenge
+	if err := r.db.First(&challenge, id).Error; err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// openAt non nil : seulement les défis pas encore terminés à cette date
+func (r *ChallengeRepository) List(openAt *time.Time, page, limit int) ([]model.Challenge, int64, error) {
+	var challenges []model.Challenge
+	var total int64
+
+	query := r.db.Model(&model.Challenge{})
+	if openAt != nil {
+		query = query.Where("ends_at IS NULL OR ends_at > ?", *openAt)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&challenges).Error
+	return challenges, total, err
+}
+
+// défis (non supprimés) rejoints par l'utilisateur
+func (r *ChallengeRepository) Joined(userID uint) ([]model.Challenge, error) {
+	var challenges []model.Challenge
+	err := r.db.
+		Joins("JOIN challenge_participants ON challenge_participants.challenge_id = challenges.id").
+		Where("challenge_participants.user_id = ?", userID).
+		Order("challenge_participants.created_at DESC").
+		Find(&challenges).Error
+	return challenges, err
+}
+
+// PARTICIPATIONS
+
+func (r *ChallengeRepository) Join(participant *model.ChallengeParticipant) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(participant).Error
+}
+
+// quitter un défi efface aussi la progression
+func (r *ChallengeRepository) Leave(challengeID, userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkAffected(tx.Where("challenge_id = ? AND user_id = ?", challengeID, userID).
+			Delete(&model.ChallengeParticipant{})); err != nil {
+			return err
+		}
+		return tx.Where("challenge_id = ? AND user_id = ?", challengeID, userID).
+			Delete(&model.ChallengeEntry{}).Error
+	})
+}
+
+func (r *ChallengeRepository) GetParticipant(challengeID, userID uint) (*model.ChallengeParticipant, error) {
+	var participant model.ChallengeParticipant
+	err := r.db.Where("challenge_id = ? AND user_id = ?", challengeID, userID).First(&participant).Error
+	if err != nil {
+		return nil, err
+	}
+	return &participant, nil
+}
+
+// participations de l'utilisateur, indexées par défi
+func (r *ChallengeRepository) Participations(userID uint, challengeIDs []uint) (map[uint]model.ChallengeParticipant, error) {
+	var participants []model.ChallengeParticipant
+	if len(challengeIDs) > 0 {
+		err := r.db.Where("user_id = ? AND challenge_id IN ?", userID, challengeIDs).Find(&participants).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	byChallenge := make(map[uint]model.ChallengeParticipant, len(participants))
+	for _, p := range participants {
+		byChallenge[p.ChallengeID] = p
+	}
+	return byChallenge, nil
+}
+
+func (r *ChallengeRepository) ParticipantIDs(challengeID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.ChallengeParticipant{}).Where("challenge_id = ?", challengeID).Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+func (r *ChallengeRepository) CountParticipants(challengeIDs []uint) (map[uint]int64, error) {
+	var rows []struct {
+		ChallengeID uint
+		Count       int64
+	}
+	counts := make(map[uint]int64)
+	if len(challengeIDs) == 0 {
+		return counts, nil
+	}
+	err := r.db.Model(&model.ChallengeParticipant{}).
+		Select("challenge_id, COUNT(*) AS count").
+		Where("challenge_id IN ?", challengeIDs).
+		Group("challenge_id").
+		Scan(&rows).Error
+	for _, row := range rows {
+		counts[row.ChallengeID] = row.Count
+	}
+	return counts, err
+}
+
+// ENTRÉES
+
+func (r *ChallengeRepository) EntryKeys(challengeID, userID uint) ([]string, error) {
+	var keys []string
+	err := r.db.Model(&model.ChallengeEntry{}).
+		Where("challenge_id = ? AND user_id = ?", challengeID, userID).
+		Pluck("entry_key", &keys).Error
+	return keys, err
+}
+
+// défis dans lesquels le film compte déjà pour l'utilisateur
+func (r *ChallengeRepository) ChallengesWithMovie(userID, movieID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.ChallengeEntry{}).
+		Where("user_id = ? AND movie_id = ?", userID, movieID).
+		Distinct().
+		Pluck("challenge_id", &ids).Error
+	return ids, err
+}
+
+// ajoute une entrée puis recalcule la progression ; false si la clé était déjà couverte
+func (r *ChallengeRepository) AddEntry(entry *model.ChallengeEntry, target int) (bool, error) {
+	added := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(entry)
+		if result.Error != nil {
+			return result.Error
+		}
+		added = result.RowsAffected > 0
+		return updateProgressTx(tx, entry.ChallengeID, entry.UserID, target)
+	})
+	return added, err
+}
+
+// efface la progression avant un recalcul complet
+func (r *ChallengeRepository) ResetEntries(challengeID, userID uint, target int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("challenge_id = ? AND user_id = ?", challengeID, userID).
+			Delete(&model.ChallengeEntry{}).Error; err != nil {
+			return err
+		}
+		return updateProgressTx(tx, challengeID, userID, target)
+	})
+}
+
+// progression = nombre d'entrées ; la date de réussite est conservée tant que
+// l'objectif reste atteint
+func updateProgressTx(tx *gorm.DB, challengeID, userID uint, target int) error {
+	var progress int64
+	if err := tx.Model(&model.ChallengeEntry{}).
+		Where("challenge_id = ? AND user_id = ?", challengeID, userID).
+		Count(&progress).Error; err != nil {
+		return err
+	}
+
+	completedAt := gorm.Expr("NULL")
+	if int(progress) >= target {
+		completedAt = gorm.Expr("COALESCE(completed_at, ?)", time.Now())
+	}
+	return tx.Model(&model.ChallengeParticipant{}).
+		Where("challenge_id = ? AND user_id = ?", challengeID, userID).
+		Updates(map[string]interface{}{
+			"progress":     progress,
+			"completed_at": completedAt,
+			"updated_at":   time.Now(),
+		}).Error
+}
+
+// FILMS VUS
+
+// un film vu, avec les attributs sur lesquels portent les critères
+type ChallengeMovie struct {
+	MovieID        uint       `gorm:"column:movie_id"`
+	ReleaseYear    int        `gorm:"column:release_year"`
+	Runtime        int        `gorm:"column:duration_minutes"`
+	WatchedAt      time.Time  `gorm:"column:watched_date"`
+	MetadataSynced *time.Time `gorm:"column:metadata_synced_at"`
+	Genres         []int      `gorm:"-"`
+	Countries      []string   `gorm:"-"`
+	Directors      []int      `gorm:"-"` // identifiants TMDB
+}
+
+// films vus par l'utilisateur (tous, ou seulement movieID), dans l'ordre de visionnage
+func (r *ChallengeRepository) WatchedMovies(userID uint, movieID *uint) ([]ChallengeMovie, error) {
+	var movies []ChallengeMovie
+	query := r.db.Table("tracks").
+		Select("tracks.movie_id, movies.release_year, movies.duration_minutes, tracks.watched_date, movies.metadata_synced_at").
+		Joins("JOIN movies ON movies.id = tracks.movie_id AND movies.deleted_at IS NULL").
+		Where("tracks.user_id = ? AND tracks.is_watched = ? AND tracks.watched_date IS NOT NULL", userID, true)
+	if movieID != nil {
+		query = query.Where("tracks.movie_id = ?", *movieID)
+	}
+	if err := query.Order("tracks.watched_date, tracks.movie_id").Scan(&movies).Error; err != nil {
+		return nil, err
+	}
+	if len(movies) == 0 {
+		return movies, nil
+	}
+
+	ids := make([]uint, len(movies))
+	byID := make(map[uint]*ChallengeMovie, len(movies))
+	for i := range movies {
+		ids[i] = movies[i].MovieID
+		byID[movies[i].MovieID] = &movies[i]
+	}
+
+	var genres []struct {
+		MovieID uint
+		GenreID int
+	}
+	if err := r.db.Table("movie_genres").Select("movie_id, genre_id").Where("movie_id IN ?", ids).
+		Order("genre_id").Scan(&genres).Error; err != nil {
+		return nil, err
+	}
+	for _, g := range genres {
+		byID[g.MovieID].Genres = append(byID[g.MovieID].Genres, g.GenreID)
+	}
+
+	var countries []struct {
+		MovieID     uint
+		CountryCode string
+	}
+	if err := r.db.Table("movie_countries").Select("movie_id, country_code").Where("movie_id IN ?", ids).
+		Order("country_code").Scan(&countries).Error; err != nil {
+		return nil, err
+	}
+	for _, c := range countries {
+		byID[c.MovieID].Countries = append(byID[c.MovieID].Countries, c.CountryCode)
+	}
+
+	var directors []struct {
+		MovieID uint
+		TmdbID  int
+	}
+	if err := r.db.Table("movie_crews").Select("movie_crews.movie_id, people.tmdb_id").
+		Joins("JOIN people ON people.id = movie_crews.person_id").
+		Where("movie_crews.movie_id IN ? AND movie_crews.job = ?", ids, "Director").
+		Order("people.tmdb_id").Scan(&directors).Error; err != nil {
+		return nil, err
+	}
+	for _, d := range directors {
+		byID[d.MovieID].Directors = append(byID[d.MovieID].Directors, d.TmdbID)
+	}
+	return movies, nil
+}
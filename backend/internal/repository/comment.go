@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+func (r *CommentRepository) Create(comment *model.ReviewComment) error {
+	return r.db.Create(comment).Error
+}
+
+func (r *CommentRepository) GetByID(id uint) (*model.ReviewComment, error) {
+	var comment model.ReviewComment
+	if err := r.db.First(&comment, id).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *CommentRepository) GetByIDWithAuthor(id uint) (*model.ReviewComment, error) {
+	var comment model.ReviewComment
+	if err := r.db.Preload("Author").First(&comment, id).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *CommentRepository) Update(comment *model.ReviewComment) error {
+	return r.db.Omit("Author").Save(comment).Error
+}
+
+// commentaires de premier niveau d'une critique, du plus ancien au plus récent
+// (sans ceux des auteurs bloqués ou masqués par le viewer)
+func (r *CommentRepository) ListRoots(reviewUserID, movieID, viewerID uint, page, limit int) ([]model.ReviewComment, int64, error) {
+	var comments []model.ReviewComment
+	var total int64
+
+	query := r.db.Model(&model.ReviewComment{}).
+		Where("review_user_id = ? AND movie_id = ? AND parent_id IS NULL", reviewUserID, movieID).
+		Scopes(hiddenFilterFor(viewerID, "review_comments.author_id"))
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+
+	if err := query.
+		Preload("Author").
+		Order("created_at ASC, id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+// toutes les réponses des fils donnés, dans l'ordre chronologique
+func (r *CommentRepository) ListReplies(rootIDs []uint, viewerID uint) ([]model.ReviewComment, error) {
+	var replies []model.ReviewComment
+	if len(rootIDs) == 0 {
+		return replies, nil
+	}
+
+	err := r.db.
+		Preload("Author").
+		Where("root_id IN ?", rootIDs).
+		Scopes(hiddenFilterFor(viewerID, "review_comments.author_id")).
+		Order("created_at ASC, id ASC").
+		Find(&replies).Error
+	return replies, err
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FollowRepository struct {
+	db *gorm.DB
+}
+
+func NewFollowRepository(db *gorm.DB) *FollowRepository {
+	return &FollowRepository{db: db}
+}
+
+// idempotent : suivre deux fois ne crée pas de doublon
+func (r *FollowRepository) Create(followerID, followedID uint) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.Follow{FollowerID: followerID, FollowedID: followedID}).Error
+}
+
+func (r *FollowRepository) Delete(followerID, followedID uint) error {
+	return r.db.Where("follower_id = ? AND followed_id = ?", followerID, followedID).
+		Delete(&model.Follow{}).Error
+}
+
+func (r *FollowRepository) IsFollowing(followerID, followedID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Follow{}).
+		Where("follower_id = ? AND followed_id = ?", followerID, followedID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// les comptes supprimés ne sont pas comptés
+func (r *FollowRepository) CountFollowing(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Follow{}).
+		Joins("JOIN users ON users.id = follows.followed_id AND users.deleted_at IS NULL").
+		Where("follows.follower_id = ?", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *FollowRepository) CountFollowers(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Follow{}).
+		Joins("JOIN users ON users.id = follows.follower_id AND users.deleted_at IS NULL").
+		Where("follows.followed_id = ?", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// abonnés actifs de userID (destinataires des notifications de ses activités)
+func (r *FollowRepository) FollowerIDs(userID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.Follow{}).
+		Joins("JOIN users ON users.id = follows.follower_id AND users.deleted_at IS NULL").
+		Where("follows.followed_id = ?", userID).
+		Pluck("follows.follower_id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// la liste a été modifiée depuis la version lue par le client
+var ErrVersionConflict = errors.New("list version conflict")
+
+type ListRepository struct {
+	db *gorm.DB
+}
+
+func NewListRepository(db *gorm.DB) *ListRepository {
+	return &ListRepository{db: db}
+}
+
+// liste avec son nombre de films
+type ListSummary struct {
+	model.UserList
+	EntryCount int64 `gorm:"column:entry_count"`
+}
+
+// film d'une liste, avec le statut du propriétaire de la liste sur ce film
+// (même jointure que GetWatchedFilmsWithRatings)
+type ListEntryResult struct {
+	EntryID           uint      `gorm:"column:entry_id"`
+	Position          int       `gorm:"column:position"`
+	Note              string    `gorm:"column:note"`
+	AddedAt           time.Time `gorm:"column:added_at"`
+	TmdbID            int       `gorm:"column:tmdb_id"`
+	Title             string    `gorm:"column:title"`
+	ReleaseYear       int       `gorm:"column:release_year"`
+	PosterURL         string    `gorm:"column:poster_url"`
+	AddedByID         *uint     `gorm:"column:added_by_id"`
+	AddedByUsername   *string   `gorm:"column:added_by_username"`
+	AddedByPictureURL *string   `gorm:"column:added_by_picture_url"`
+	IsWatched         bool      `gorm:"column:is_watched"`
+	IsWatchlist       bool      `gorm:"column:is_watchlist"`
+	UserRating        *float32  `gorm:"column:user_rating"`
+	HasReview         bool      `gorm:"column:has_review"`
+}
+
+const (
+	listEntryCountSQL   = "(SELECT COUNT(*) FROM user_list_entries WHERE user_list_entries.list_id = user_lists.id)"
+	listCollaboratorSQL = "EXISTS (SELECT 1 FROM list_collaborators WHERE list_collaborators.list_id = user_lists.id " +
+		"AND list_collaborators.user_id = ? AND list_collaborators.status = ?)"
+)
+
+// les listes d'un profil non visible restent accessibles à leurs
+// collaborateurs, y compris invités (pour répondre à l'invitation)
+func listOwnerVisibleTo(viewerID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(sqlColumn(profileVisibleSQL, "user_lists.user_id")+
+			" OR EXISTS (SELECT 1 FROM list_collaborators WHERE list_collaborators.list_id = user_lists.id AND list_collaborators.user_id = ?)",
+			viewerID, viewerID, viewerID)
+	}
+}
+
+func (r *ListRepository) Create(list *model.UserList) error {
+	return r.db.Create(list).Error
+}
+
+func (r *ListRepository) GetByID(id uint) (*model.UserList, error) {
+	var list model.UserList
+	if err := r.db.Preload("User").First(&list, id).Error; err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// comme GetByID, mais introuvable si le propriétaire et le viewer se bloquent
+func (r *ListRepository) GetVisible(id, viewerID uint) (*model.UserList, error) {
+	var list model.UserList
+	if err := r.db.Preload("User").Scopes(notBlockedFor(viewerID, "user_lists.user_id"), listOwnerVisibleTo(viewerID)).First(&list, id).Error; err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// expectedVersion nil : pas de contrôle de version
+func (r *ListRepository) Update(list *model.UserList, expectedVersion *int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, list.ID, expectedVersion); err != nil {
+			return err
+		}
+		return tx.Model(list).Select("title", "description", "is_ranked", "is_public").Updates(list).Error
+	})
+}
+
+func (r *ListRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("list_id = ?", id).Delete(&model.UserListEntry{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("list_id = ?", id).Delete(&model.ListCollaborator{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.UserList{}, id).Error
+	})
+}
+
+// listes d'un utilisateur, les plus récemment modifiées d'abord ; les listes
+// privées ne sont incluses que pour leur propriétaire et leurs collaborateurs
+func (r *ListRepository) ListByUser(userID, viewerID uint, page, limit int) ([]ListSummary, int64, error) {
+	query := r.db.Model(&model.UserList{}).Where("user_lists.user_id = ?", userID)
+	if viewerID != userID {
+		query = query.Where("user_lists.is_public = ? OR "+listCollaboratorSQL, true, viewerID, model.ListInvitationAccepted).
+			Scopes(notBlockedFor(viewerID, "user_lists.user_id"), listOwnerVisibleTo(viewerID))
+	}
+	return r.paginateLists(query, page, limit)
+}
+
+// listes d'autres utilisateurs sur lesquelles userID collabore
+func (r *ListRepository) ListSharedWith(userID uint, page, limit int) ([]ListSummary, int64, error) {
+	query := r.db.Model(&model.UserList{}).
+		Joins("JOIN list_collaborators ON list_collaborators.list_id = user_lists.id").
+		Where("list_collaborators.user_id = ? AND list_collaborators.status = ?", userID, model.ListInvitationAccepted)
+	return r.paginateLists(query, page, limit)
+}
+
+func (r *ListRepository) paginateLists(query *gorm.DB, page, limit int) ([]ListSummary, int64, error) {
+	var lists []ListSummary
+	var total int64
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.
+		Preload("User").
+		Select("user_lists.*, " + listEntryCountSQL + " AS entry_count").
+		Order("user_lists.updated_at DESC, user_lists.id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&lists).Error
+	return lists, total, err
+}
+
+func (r *ListRepository) CountEntries(listID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.UserListEntry{}).Where("list_id = ?", listID).Count(&count).Error
+	return count, err
+}
+
+// films de la liste dans l'ordre, avec le statut vu/noté de ownerID
+func (r *ListRepository) ListEntries(listID, ownerID uint, page, limit int) ([]ListEntryResult, int64, error) {
+	var results []ListEntryResult
+	var total int64
+
+	query := r.db.Table("user_list_entries").
+		Joins("JOIN movies ON movies.id = user_list_entries.movie_id").
+		Joins("LEFT JOIN tracks ON tracks.movie_id = movies.id AND tracks.user_id = ?", ownerID).
+		Joins("LEFT JOIN rates ON rates.movie_id = movies.id AND rates.user_id = ?", ownerID).
+		Joins("LEFT JOIN reviews ON reviews.movie_id = movies.id AND reviews.user_id = ?", ownerID).
+		Joins("LEFT JOIN users AS adders ON adders.id = user_list_entries.added_by_id AND adders.deleted_at IS NULL").
+		Where("user_list_entries.list_id = ?", listID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.
+		Select("user_list_entries.id AS entry_id, user_list_entries.position, user_list_entries.note, user_list_entries.created_at AS added_at, " +
+			"movies.tmdb_id, movies.title, movies.release_year, movies.poster_url, " +
+			"adders.id AS added_by_id, adders.username AS added_by_username, adders.profile_picture_url AS added_by_picture_url, " +
+			"COALESCE(tracks.is_watched, false) AS is_watched, COALESCE(tracks.is_watchlist, false) AS is_watchlist, rates.rating AS user_rating, " +
+			"CASE WHEN reviews.content IS NOT NULL AND reviews.content != '' THEN true ELSE false END AS has_review").
+		Order("user_list_entries.position ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&results).Error
+	return results, total, err
+}
+
+func (r *ListRepository) GetEntry(listID, entryID uint) (*model.UserListEntry, error) {
+	var entry model.UserListEntry
+	if err := r.db.Where("id = ? AND list_id = ?", entryID, listID).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *ListRepository) HasMovie(listID, movieID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.UserListEntry{}).Where("list_id = ? AND movie_id = ?", listID, movieID).Count(&count).Error
+	return count > 0, err
+}
+
+// ajoute le film en dernière position ; la montée de version verrouille la
+// liste, deux ajouts simultanés ne peuvent donc pas prendre la même position
+func (r *ListRepository) AddEntry(entry *model.UserListEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, entry.ListID, nil); err != nil {
+			return err
+		}
+
+		var maxPosition int
+		if err := tx.Model(&model.UserListEntry{}).
+			Where("list_id = ?", entry.ListID).
+			Select("COALESCE(MAX(position), 0)").
+			Scan(&maxPosition).Error; err != nil {
+			return err
+		}
+
+		entry.Position = maxPosition + 1
+		return tx.Create(entry).Error
+	})
+}
+
+func (r *ListRepository) UpdateEntryNote(entry *model.UserListEntry, expectedVersion *int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, entry.ListID, expectedVersion); err != nil {
+			return err
+		}
+		return tx.Model(entry).Update("note", entry.Note).Error
+	})
+}
+
+// retire le film et referme le trou dans les positions
+func (r *ListRepository) RemoveEntry(entry *model.UserListEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, entry.ListID, nil); err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.UserListEntry{}, entry.ID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.UserListEntry{}).
+			Where("list_id = ? AND position > ?", entry.ListID, entry.Position).
+			UpdateColumn("position", gorm.Expr("position - 1")).Error
+	})
+}
+
+func (r *ListRepository) EntryIDs(listID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.UserListEntry{}).Where("list_id = ?", listID).Order("position ASC").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// réécrit les positions dans l'ordre donné (qui doit couvrir toute la liste),
+// uniquement si personne n'a modifié la liste depuis expectedVersion
+func (r *ListRepository) Reorder(listID uint, entryIDs []uint, expectedVersion int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, listID, &expectedVersion); err != nil {
+			return err
+		}
+		for i, id := range entryIDs {
+			if err := tx.Model(&model.UserListEntry{}).
+				Where("id = ? AND list_id = ?", id, listID).
+				UpdateColumn("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// COLLABORATEURS
+
+func (r *ListRepository) CreateCollaborator(collaborator *model.ListCollaborator) error {
+	return r.db.Create(collaborator).Error
+}
+
+func (r *ListRepository) GetCollaborator(listID, userID uint) (*model.ListCollaborator, error) {
+	var collaborator model.ListCollaborator
+	if err := r.db.Where("list_id = ? AND user_id = ?", listID, userID).First(&collaborator).Error; err != nil {
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+// collaborateurs d'une liste, invitations en attente comprises si includePending
+func (r *ListRepository) ListCollaborators(listID uint, includePending bool) ([]model.ListCollaborator, error) {
+	var collaborators []model.ListCollaborator
+	query := r.db.Preload("User").Where("list_id = ?", listID)
+	if !includePending {
+		query = query.Where("status = ?", model.ListInvitationAccepted)
+	}
+	err := query.Order("created_at ASC").Find(&collaborators).Error
+	return collaborators, err
+}
+
+// invitations en attente reçues par userID, les plus récentes d'abord
+func (r *ListRepository) ListInvitations(userID uint) ([]model.ListCollaborator, error) {
+	var invitations []model.ListCollaborator
+	err := r.db.Preload("List.User").
+		Where("user_id = ? AND status = ?", userID, model.ListInvitationPending).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *ListRepository) UpdateCollaborator(collaborator *model.ListCollaborator) error {
+	return r.db.Model(collaborator).
+		Where("list_id = ? AND user_id = ?", collaborator.ListID, collaborator.UserID).
+		Select("role", "status", "responded_at").
+		Updates(collaborator).Error
+}
+
+func (r *ListRepository) DeleteCollaborator(listID, userID uint) error {
+	return checkAffected(r.db.Where("list_id = ? AND user_id = ?", listID, userID).Delete(&model.ListCollaborator{}))
+}
+
+// toute modification du contenu fait remonter la liste et incrémente sa
+// version ; avec expectedVersion, échoue si la liste a changé entre-temps
+func bumpVersion(tx *gorm.DB, listID uint, expectedVersion *int) error {
+	query := tx.Model(&model.UserList{}).Where("id = ?", listID)
+	if expectedVersion != nil {
+		query = query.Where("version = ?", *expectedVersion)
+	}
+
+	result := query.UpdateColumns(map[string]interface{}{
+		"version":    gorm.Expr("version + 1"),
+		"updated_at": time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if expectedVersion != nil {
+			return ErrVersionConflict
+		}
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -33,6 +33,95 @@ func (r *MovieRepository) UpsertMovie(movie *model.Movie) error {
 	}).Create(movie).Error
 }
 
+// remplace genres, pays et générique du film ; les personnes sont reprises
+// par leur identifiant TMDB (Person.ID des cast/crew renseigné ici)
+func (r *MovieRepository) SaveMetadata(movie *model.Movie, genres []model.Genre, countries []model.Country, cast []model.MovieCast, crew []model.MovieCrew) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(genres) > 0 {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"name"}),
+			}).Create(&genres).Error; err != nil {
+				return err
+			}
+		}
+		if len(countries) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&countries).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(movie).Association("Genres").Replace(genres); err != nil {
+			return err
+		}
+		if err := tx.Model(movie).Association("Countries").Replace(countries); err != nil {
+			return err
+		}
+
+		people := make(map[int]uint)
+		resolve := func(person *model.Person) (uint, error) {
+			if id, ok := people[person.TmdbID]; ok {
+				return id, nil
+			}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "tmdb_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"name", "profile_picture_url", "gender", "updated_at"}),
+			}).Create(person).Error; err != nil {
+				return 0, err
+			}
+			// ID non renvoyé par certains pilotes en cas de conflit
+			if person.ID == 0 {
+				if err := tx.Where("tmdb_id = ?", person.TmdbID).First(person).Error; err != nil {
+					return 0, err
+				}
+			}
+			people[person.TmdbID] = person.ID
+			return person.ID, nil
+		}
+
+		if err := tx.Where("movie_id = ?", movie.ID).Delete(&model.MovieCast{}).Error; err != nil {
+			return err
+		}
+		seen := make(map[uint]bool)
+		for _, member := range cast {
+			personID, err := resolve(&member.Person)
+			if err != nil {
+				return err
+			}
+			if seen[personID] {
+				continue // même acteur dans deux rôles
+			}
+			seen[personID] = true
+			if err := tx.Omit("Movie", "Person").Create(&model.MovieCast{MovieID: movie.ID, PersonID: personID, CharacterName: member.CharacterName, CastOrder: member.CastOrder}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("movie_id = ?", movie.ID).Delete(&model.MovieCrew{}).Error; err != nil {
+			return err
+		}
+		for _, member := range crew {
+			personID, err := resolve(&member.Person)
+			if err != nil {
+				return err
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Omit("Movie", "Person").
+				Create(&model.MovieCrew{MovieID: movie.ID, PersonID: personID, Job: member.Job, Department: member.Department}).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		movie.MetadataSyncedAt = &now
+		return tx.Model(&model.Movie{}).Where("id = ?", movie.ID).Update("metadata_synced_at", now).Error
+	})
+}
+
+func (r *MovieRepository) GetMoviesWithoutMetadata(movieIDs []uint) ([]model.Movie, error) {
+	var movies []model.Movie
+	err := r.db.Where("id IN ? AND metadata_synced_at IS NULL", movieIDs).Find(&movies).Error
+	return movies, err
+}
+
 func (r *MovieRepository) UpsertTrack(track *model.Track) error {
 	var existing model.Track
 	err := r.db.Where("user_id = ? AND movie_id = ?", track.UserID, track.MovieID).First(&existing).Error
@@ -53,11 +142,153 @@ func (r *MovieRepository) UpsertRate(rate *model.Rate) error {
 	}).Create(rate).Error
 }
 
+// crée la critique, ou la modifie en archivant la version précédente
 func (r *MovieRepository) UpsertReview(review *model.Review) error {
-	return r.db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "user_id"}, {Name: "movie_id"}},
-		DoUpdates: clause.AssignmentColumns([]string{"content", "is_spoiler", "updated_at"}),
-	}).Create(review).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing model.Review
+		err := tx.Where("user_id = ? AND movie_id = ?", review.UserID, review.MovieID).First(&existing).Error
+		if err == gorm.ErrRecordNotFound {
+			return tx.Create(review).Error
+		}
+		if err != nil {
+			return err
+		}
+		return reviseReviewTx(tx, &existing, review.Content, review.ContentHTML, review.IsSpoiler)
+	})
+}
+
+// les Update*/Delete* ci-dessous renvoient gorm.ErrRecordNotFound si l'entrée n'existe pas
+
+func (r *MovieRepository) UpdateRate(userID, movieID uint, rating float32) error {
+	return checkAffected(r.db.Model(&model.Rate{}).
+		Where("user_id = ? AND movie_id = ?", userID, movieID).
+		Update("rating", rating))
+}
+
+func (r *MovieRepository) DeleteRate(userID, movieID uint) error {
+	return checkAffected(r.db.Where("user_id = ? AND movie_id = ?", userID, movieID).Delete(&model.Rate{}))
+}
+
+// isSpoiler nil => flag inchangé
+func (r *MovieRepository) UpdateReview(userID, movieID uint, content, contentHTML string, isSpoiler *bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing model.Review
+		if err := tx.Where("user_id = ? AND movie_id = ?", userID, movieID).First(&existing).Error; err != nil {
+			return err
+		}
+
+		spoiler := existing.IsSpoiler
+		if isSpoiler != nil {
+			spoiler = *isSpoiler
+		}
+		return reviseReviewTx(tx, &existing, content, contentHTML, spoiler)
+	})
+}
+
+// supprime la critique avec ses likes et ses commentaires
+func (r *MovieRepository) DeleteReview(userID, movieID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return deleteReviewTx(tx, userID, movieID)
+	})
+}
+
+// un visionnage de plus, enregistré dans le journal
+func (r *MovieRepository) IncrementWatchCount(userID, movieID uint) error {
+	return r.db.Model(&model.Track{}).
+		Where("user_id = ? AND movie_id = ?", userID, movieID).
+		Update("watch_count", gorm.Expr("watch_count + 1")).Error
+}
+
+// mise à jour partielle : contrairement à UpsertTrack, les champs à false sont bien écrits
+func (r *MovieRepository) UpdateTrack(userID, movieID uint, updates map[string]interface{}) error {
+	return checkAffected(r.db.Model(&model.Track{}).
+		Where("user_id = ? AND movie_id = ?", userID, movieID).
+		Updates(updates))
+}
+
+// retire le film du journal de l'utilisateur : suivi, note et critique
+func (r *MovieRepository) DeleteTrack(userID, movieID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkAffected(tx.Where("user_id = ? AND movie_id = ?", userID, movieID).Delete(&model.Track{})); err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND movie_id = ?", userID, movieID).Delete(&model.Rate{}).Error; err != nil {
+			return err
+		}
+		if err := deleteReviewTx(tx, userID, movieID); err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		return nil
+	})
+}
+
+// archive la version courante puis applique la nouvelle ; sans changement
+// réel, rien n'est écrit pour ne pas polluer l'historique
+func reviseReviewTx(tx *gorm.DB, existing *model.Review, content, contentHTML string, isSpoiler bool) error {
+	if existing.SpoilerForced {
+		isSpoiler = true
+	}
+	if existing.Content == content && existing.IsSpoiler == isSpoiler {
+		return nil
+	}
+
+	now := time.Now()
+	if err := archiveReviewTx(tx, existing, now); err != nil {
+		return err
+	}
+
+	return tx.Model(&model.Review{}).
+		Where("user_id = ? AND movie_id = ?", existing.UserID, existing.MovieID).
+		Updates(map[string]interface{}{"content": content, "content_html": contentHTML, "is_spoiler": isSpoiler, "edited_at": now}).Error
+}
+
+// copie la version courante dans l'historique
+func archiveReviewTx(tx *gorm.DB, existing *model.Review, replacedAt time.Time) error {
+	writtenAt := existing.CreatedAt
+	if existing.EditedAt != nil {
+		writtenAt = *existing.EditedAt
+	}
+
+	return tx.Create(&model.ReviewRevision{
+		UserID:     existing.UserID,
+		MovieID:    existing.MovieID,
+		Content:    existing.Content,
+		IsSpoiler:  existing.IsSpoiler,
+		WrittenAt:  writtenAt,
+		ReplacedAt: replacedAt,
+	}).Error
+}
+
+// l'historique n'est pas effacé avec la critique (soft-delete) : la
+// modération en a besoin en cas de litige sur un signalement
+func deleteReviewTx(tx *gorm.DB, userID, movieID uint) error {
+	var existing model.Review
+	if err := tx.Where("user_id = ? AND movie_id = ?", userID, movieID).First(&existing).Error; err != nil {
+		return err
+	}
+	if err := archiveReviewTx(tx, &existing, time.Now()); err != nil {
+		return err
+	}
+	if err := tx.Where("user_id = ? AND movie_id = ?", userID, movieID).Delete(&model.ReviewRevision{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("review_user_id = ? AND movie_id = ?", userID, movieID).Delete(&model.ReviewLike{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("review_user_id = ? AND movie_id = ?", userID, movieID).Delete(&model.ReviewComment{}).Error; err != nil {
+		return err
+	}
+	return checkAffected(tx.Where("user_id = ? AND movie_id = ?", userID, movieID).Delete(&model.Review{}))
+}
+
+func checkAffected(result *gorm.DB) error {
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
 func (r *MovieRepository) GetUserInteraction(userID uint, movieID uint) (*model.Track, *model.Rate, *model.Review, error) {
@@ -72,18 +303,39 @@ func (r *MovieRepository) GetUserInteraction(userID uint, movieID uint) (*model.
 	return &track, &rate, &review, nil
 }
 
+func (r *MovieRepository) CountReviewLikes(userID uint, movieID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.ReviewLike{}).
+		Where("review_user_id = ? AND movie_id = ?", userID, movieID).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *MovieRepository) CountWatched(userID uint) (int64, error) {
 	var count int64
 	err := r.db.Model(&model.Track{}).Where("user_id = ? AND is_watched = ?", userID, true).Count(&count).Error
 	return count, err
 }
 
-func (r *MovieRepository) CountWatchedThisYear(userID uint) (int64, error) {
+// l'année courante dans loc (fuseau de l'utilisateur)
+func (r *MovieRepository) CountWatchedThisYear(userID uint, loc *time.Location) (int64, error) {
+	currentYear := time.Now().In(loc).Year()
+	startOfYear := time.Date(currentYear, 1, 1, 0, 0, 0, 0, loc)
+	return r.CountWatchedBetween(userID, startOfYear, startOfYear.AddDate(1, 0, 0))
+}
+
+// fuseau choisi par l'utilisateur (vide => fuseau par défaut)
+func (r *MovieRepository) UserTimeZone(userID uint) (string, error) {
+	var user model.User
+	err := r.db.Select("time_zone").First(&user, userID).Error
+	return user.TimeZone, err
+}
+
+// films vus dans [from, to[
+func (r *MovieRepository) CountWatchedBetween(userID uint, from, to time.Time) (int64, error) {
 	var count int64
-	currentYear := time.Now().Year()
-	startOfYear := time.Date(currentYear, 1, 1, 0, 0, 0, 0, time.UTC)
 	err := r.db.Model(&model.Track{}).
-		Where("user_id = ? AND is_watched = ? AND watched_date >= ?", userID, true, startOfYear).
+		Where("user_id = ? AND is_watched = ? AND watched_date >= ? AND watched_date < ?", userID, true, from, to).
 		Count(&count).Error
 	return count, err
 }
@@ -193,6 +445,51 @@ func (r *MovieRepository) GetWatchedFilmsWithRatings(userID uint, page, limit in
 	return results, total, nil
 }
 
+// film noté par deux utilisateurs ; OtherRating vide pour GetRatedOnWatchlist
+type SharedRating struct {
+	MovieID     uint    `gorm:"column:movie_id"`
+	TmdbID      int     `gorm:"column:tmdb_id"`
+	Title       string  `gorm:"column:title"`
+	ReleaseYear int     `gorm:"column:release_year"`
+	PosterURL   string  `gorm:"column:poster_url"`
+	Rating      float32 `gorm:"column:rating"`
+	OtherRating float32 `gorm:"column:other_rating"`
+}
+
+const sharedRatingColumns = "movies.id as movie_id, movies.tmdb_id, movies.title, movies.release_year, movies.poster_url, rates.rating"
+
+// films notés à la fois par userID (Rating) et par otherID (OtherRating)
+func (r *MovieRepository) GetSharedRatings(userID, otherID uint) ([]SharedRating, error) {
+	var results []SharedRating
+	err := r.db.Table("rates").
+		Select(sharedRatingColumns+", others.rating as other_rating").
+		Joins("JOIN rates others ON others.movie_id = rates.movie_id AND others.user_id = ?", otherID).
+		Joins("JOIN movies ON movies.id = rates.movie_id AND movies.deleted_at IS NULL").
+		Where("rates.user_id = ?", userID).
+		Order("movies.id").
+		Scan(&results).Error
+	return results, err
+}
+
+// films notés au moins minRating par raterID, dans la watchlist de ownerID et pas encore vus
+func (r *MovieRepository) GetRatedOnWatchlist(raterID, ownerID uint, minRating float32, limit int) ([]SharedRating, error) {
+	var results []SharedRating
+	err := r.db.Table("rates").
+		Select(sharedRatingColumns).
+		Joins("JOIN tracks ON tracks.movie_id = rates.movie_id AND tracks.user_id = ? AND tracks.is_watchlist = ? AND tracks.is_watched = ?", ownerID, true, false).
+		Joins("JOIN movies ON movies.id = rates.movie_id AND movies.deleted_at IS NULL").
+		Where("rates.user_id = ? AND rates.rating >= ?", raterID, minRating).
+		Order("rates.rating DESC, movies.title").
+		Limit(limit).
+		Scan(&results).Error
+	return results, err
+}
+
+// films parmi movieIDs que le user a déjà vus
+func (r *MovieRepository) WatchedMovieIDs(userID uint, movieIDs []uint) (map[uint]bool, error) {
+	return watchedMovieIDs(r.db, userID, movieIDs)
+}
+
 // mapping struct for returning reviews
 type UserReviewResult struct {
 	MovieID     uint       `gorm:"column:movie_id"`
@@ -202,23 +499,30 @@ type UserReviewResult struct {
 	PosterURL   string     `gorm:"column:poster_url"`
 	Rating      *float32   `gorm:"column:rating"`
 	Content     string     `gorm:"column:content"`
+	ContentHTML string     `gorm:"column:content_html"`
 	IsSpoiler   bool       `gorm:"column:is_spoiler"`
+	LikeCount   int64      `gorm:"column:like_count"`
+	EditedAt    *time.Time `gorm:"column:edited_at"`
 	WatchedDate *time.Time `gorm:"column:watched_date"`
 	ReviewedAt  time.Time  `gorm:"column:reviewed_at"`
 }
 
-func (r *MovieRepository) GetReviews(userID uint, page, limit int) ([]UserReviewResult, int64, error) {
+// includeHidden : inclut les critiques masquées par la modération (pour leur auteur)
+func (r *MovieRepository) GetReviews(userID uint, includeHidden bool, page, limit int) ([]UserReviewResult, int64, error) {
 	var results []UserReviewResult
 	var total int64
 
 	offset := (page - 1) * limit
 
 	query := r.db.Table("reviews").
-		Select("movies.id as movie_id, movies.tmdb_id, movies.title, movies.release_year, movies.poster_url, rates.rating, reviews.content, reviews.is_spoiler, tracks.watched_date, reviews.created_at as reviewed_at").
+		Select("movies.id as movie_id, movies.tmdb_id, movies.title, movies.release_year, movies.poster_url, rates.rating, reviews.content, reviews.content_html, reviews.is_spoiler, reviews.edited_at, "+reviewLikeCountSQL+" as like_count, tracks.watched_date, reviews.created_at as reviewed_at").
 		Joins("JOIN movies ON movies.id = reviews.movie_id").
 		Joins("LEFT JOIN tracks ON tracks.movie_id = reviews.movie_id AND tracks.user_id = reviews.user_id").
 		Joins("LEFT JOIN rates ON rates.movie_id = reviews.movie_id AND rates.user_id = reviews.user_id").
 		Where("reviews.user_id = ?", userID)
+	if !includeHidden {
+		query = query.Where("reviews.hidden_at IS NULL")
+	}
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -15,7 +15,7 @@ func setupMovieTestDB(t *testing.T) *gorm.DB {
 		t.Fatalf("Failed to open test database: %v", err)
 	}
 
-	err = db.AutoMigrate(&model.User{}, &model.Movie{}, &model.Track{}, &model.Rate{}, &model.Review{})
+	err = db.AutoMigrate(&model.User{}, &model.Movie{}, &model.Track{}, &model.Rate{}, &model.Review{}, &model.ReviewLike{}, &model.ReviewComment{}, &model.ReviewRevision{}, &model.Block{}, &model.Mute{})
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
@@ -155,7 +155,7 @@ func TestMovieRepository_Counts(t *testing.T) {
 		t.Errorf("expected 2 watched movies, got %d", watched)
 	}
 
-	watchedThisYear, _ := repo.CountWatchedThisYear(user.ID)
+	watchedThisYear, _ := repo.CountWatchedThisYear(user.ID, time.UTC)
 	if watchedThisYear != 2 {
 		t.Errorf("expected 2 watched movies this year, got %d", watchedThisYear)
 	}
@@ -219,3 +219,60 @@ func TestMovieRepository_GetRatingDistribution(t *testing.T) {
 		t.Errorf("rating distribution incorrect, got %v", dist)
 	}
 }
+
+func TestMovieRepository_ReviewRevisions(t *testing.T) {
+	db := setupMovieTestDB(t)
+	repo := NewMovieRepository(db)
+
+	user := &model.User{Username: "critic", Email: "critic@example.com"}
+	db.Create(user)
+	movie := &model.Movie{TmdbID: 102, Title: "Revised Movie"}
+	repo.UpsertMovie(movie)
+
+	repo.UpsertReview(&model.Review{UserID: user.ID, MovieID: movie.ID, Content: "v1"})
+
+	// même contenu : pas de révision, pas de marqueur "modifiée"
+	repo.UpsertReview(&model.Review{UserID: user.ID, MovieID: movie.ID, Content: "v1"})
+	var count int64
+	db.Model(&model.ReviewRevision{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("expected no revision for an identical save, got %d", count)
+	}
+
+	spoiler := true
+	if err := repo.UpdateReview(user.ID, movie.ID, "v2", "<p>v2</p>", &spoiler); err != nil {
+		t.Fatalf("expected no error on UpdateReview, got %v", err)
+	}
+
+	var review model.Review
+	db.Where("user_id = ? AND movie_id = ?", user.ID, movie.ID).First(&review)
+	if review.Content != "v2" || !review.IsSpoiler || review.EditedAt == nil {
+		t.Errorf("expected edited review, got %+v", review)
+	}
+
+	var revisions []model.ReviewRevision
+	db.Find(&revisions)
+	if len(revisions) != 1 || revisions[0].Content != "v1" || revisions[0].IsSpoiler {
+		t.Fatalf("expected previous version to be archived, got %+v", revisions)
+	}
+
+	// la suppression archive la dernière version et garde l'historique,
+	// soft-deleted, pour la modération
+	if err := repo.DeleteReview(user.ID, movie.ID); err != nil {
+		t.Fatalf("expected no error on DeleteReview, got %v", err)
+	}
+	db.Model(&model.ReviewRevision{}).Count(&count)
+	if count != 0 {
+		t.Errorf("expected revisions to be hidden with the review, got %d", count)
+	}
+	revisions = nil
+	db.Unscoped().Order("id").Find(&revisions)
+	if len(revisions) != 2 || revisions[0].Content != "v1" || revisions[1].Content != "v2" || !revisions[1].IsSpoiler {
+		t.Fatalf("expected the previous and final versions to be kept, got %+v", revisions)
+	}
+	for _, revision := range revisions {
+		if !revision.DeletedAt.Valid {
+			t.Errorf("expected revision %d to be soft-deleted, got %+v", revision.ID, revision)
+		}
+	}
+}
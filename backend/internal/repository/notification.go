@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// insertion par lots (fan-out vers de nombreux abonnés)
+func (r *NotificationRepository) CreateBatch(notifications []model.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(notifications, 500).Error
+}
+
+// boîte de réception, les plus récentes d'abord
+func (r *NotificationRepository) List(userID uint, unreadOnly bool, page, limit int) ([]model.Notification, int64, error) {
+	var notifications []model.Notification
+	var total int64
+
+	query := r.db.Model(&model.Notification{}).
+		Where("user_id = ?", userID).
+		Scopes(hiddenFilterFor(userID, "notifications.actor_id"), actorVisibleTo(userID))
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.
+		Preload("Actor").
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, total, err
+}
+
+func (r *NotificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Scopes(hiddenFilterFor(userID, "notifications.actor_id"), actorVisibleTo(userID)).
+		Count(&count).Error
+	return count, err
+}
+
+// marque comme lues les notifications de userID parmi ids (toutes si ids est nil)
+func (r *NotificationRepository) MarkRead(userID uint, ids []uint) (int64, error) {
+	query := r.db.Model(&model.Notification{}).Where("user_id = ? AND read_at IS NULL", userID)
+	if ids != nil {
+		query = query.Where("id IN ?", ids)
+	}
+	result := query.UpdateColumn("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+func (r *NotificationRepository) GetPreferences(userID uint) ([]model.NotificationPreference, error) {
+	var preferences []model.NotificationPreference
+	err := r.db.Where("user_id = ?", userID).Find(&preferences).Error
+	return preferences, err
+}
+
+func (r *NotificationRepository) SavePreferences(preferences []model.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(&preferences).Error
+}
+
+// parmi userIDs, ceux qui ont désactivé ce type de notification
+func (r *NotificationRepository) OptedOut(notificationType string, userIDs []uint) (map[uint]bool, error) {
+	var ids []uint
+	err := r.db.Model(&model.NotificationPreference{}).
+		Where("type = ? AND enabled = ? AND user_id IN ?", notificationType, false, userIDs).
+		Pluck("user_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	optedOut := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		optedOut[id] = true
+	}
+	return optedOut, nil
+}
+
+// une notification sur l'activité d'un profil devenu privé disparaît
+// (notifications système : sans acteur)
+func actorVisibleTo(userID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("notifications.actor_id IS NULL OR "+sqlColumn(profileVisibleSQL, "notifications.actor_id"), userID, userID)
+	}
+}
@@ -0,0 +1,16 @@
+package repository
+
+import "gorm.io/gorm"
+
+// le profil de l'utilisateur %[1]s est visible du viewer : le sien, public,
+// ou réservé aux abonnés et le viewer le suit
+const profileVisibleSQL = "EXISTS (SELECT 1 FROM users owners WHERE owners.id = %[1]s AND (owners.id = ? " +
+	"OR owners.profile_visibility = 'public' " +
+	"OR (owners.profile_visibility = 'followers' AND EXISTS (SELECT 1 FROM follows WHERE follows.follower_id = ? AND follows.followed_id = owners.id))))"
+
+// exclut le contenu des utilisateurs dont le profil n'est pas visible du viewer
+func profileVisibleTo(viewerID uint, column string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(sqlColumn(profileVisibleSQL, column), viewerID, viewerID)
+	}
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// données des recommandations : historique de l'utilisateur, notes des autres
+// membres (filtrage collaboratif) et générique des films (similarité de contenu)
+type RecommendationRepository struct {
+	db *gorm.DB
+}
+
+func NewRecommendationRepository(db *gorm.DB) *RecommendationRepository {
+	return &RecommendationRepository{db: db}
+}
+
+// film noté ou favori de l'utilisateur
+type RecommendationSeed struct {
+	MovieID        uint       `gorm:"column:movie_id"`
+	TmdbID         int        `gorm:"column:tmdb_id"`
+	Title          string     `gorm:"column:title"`
+	Rating         *float32   `gorm:"column:rating"`
+	Favorite       bool       `gorm:"column:is_favorite"`
+	MetadataSynced *time.Time `gorm:"column:metadata_synced_at"`
+}
+
+type RatingRow struct {
+	UserID  uint    `gorm:"column:user_id"`
+	MovieID uint    `gorm:"column:movie_id"`
+	Rating  float64 `gorm:"column:rating"`
+}
+
+// genre ou personne du générique d'un film
+type MovieFeature struct {
+	MovieID uint   `gorm:"column:movie_id"`
+	Kind    string `gorm:"column:kind"` // model.RecommendationReason*
+	ID      uint   `gorm:"column:feature_id"`
+	Name    string `gorm:"column:name"`
+}
+
+// postes du générique pris en compte dans la similarité
+var (
+	recommendationDirectorJobs = []string{"Director"}
+	recommendationWriterJobs   = []string{"Screenplay", "Writer"}
+)
+
+// têtes d'affiche : les premiers rôles seulement
+const recommendationCastOrder = 5
+
+func (r *RecommendationRepository) Seeds(userID uint) ([]RecommendationSeed, error) {
+	var seeds []RecommendationSeed
+	err := r.db.Table("movies").
+		Select("movies.id AS movie_id, movies.tmdb_id, movies.title, rates.rating, COALESCE(tracks.is_favorite, ?) AS is_favorite, movies.metadata_synced_at", false).
+		Joins("LEFT JOIN rates ON rates.movie_id = movies.id AND rates.user_id = ?", userID).
+		Joins("LEFT JOIN tracks ON tracks.movie_id = movies.id AND tracks.user_id = ?", userID).
+		Where("movies.deleted_at IS NULL").
+		Where("rates.user_id IS NOT NULL OR tracks.is_favorite = ?", true).
+		Order("movies.id").
+		Scan(&seeds).Error
+	return seeds, err
+}
+
+// films déjà vus, notés ou critiqués : jamais recommandés
+func (r *RecommendationRepository) SeenMovieIDs(userID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Raw("SELECT movie_id FROM tracks WHERE user_id = ? AND is_watched = ? "+
+		"UNION SELECT movie_id FROM rates WHERE user_id = ? "+
+		"UNION SELECT movie_id FROM reviews WHERE user_id = ?", userID, true, userID, userID).
+		Scan(&ids).Error
+	return ids, err
+}
+
+// membres qui contribuent aux recommandations des autres : ni supprimés, ni
+// retirés des recommandations (Discoverable)
+func (r *RecommendationRepository) contributors() *gorm.DB {
+	return r.db.Table("rates").
+		Joins("JOIN users ON users.id = rates.user_id AND users.deleted_at IS NULL AND users.discoverable = ?", true)
+}
+
+// toutes les notes des membres ayant noté au moins un film noté par userID
+func (r *RecommendationRepository) CoRatings(userID uint) ([]RatingRow, error) {
+	var rows []RatingRow
+	err := r.contributors().
+		Select("rates.user_id, rates.movie_id, rates.rating").
+		Where("rates.user_id <> ?", userID).
+		Where("rates.user_id IN (SELECT co.user_id FROM rates co JOIN rates mine ON mine.movie_id = co.movie_id WHERE mine.user_id = ?)", userID).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// genres, réalisateurs, scénaristes et têtes d'affiche des films
+func (r *RecommendationRepository) Features(movieIDs []uint) ([]MovieFeature, error) {
+	var features []MovieFeature
+	if len(movieIDs) == 0 {
+		return features, nil
+	}
+
+	var genres []MovieFeature
+	if err := r.db.Table("movie_genres").
+		Select(fmt.Sprintf("movie_genres.movie_id, '%s' AS kind, genres.id AS feature_id, genres.name", model.RecommendationReasonGenre)).
+		Joins("JOIN genres ON genres.id = movie_genres.genre_id").
+		Where("movie_genres.movie_id IN ?", movieIDs).
+		Scan(&genres).Error; err != nil {
+		return nil, err
+	}
+	features = append(features, genres...)
+
+	var crew []MovieFeature
+	if err := r.db.Table("movie_crews").
+		Select(fmt.Sprintf("DISTINCT movie_crews.movie_id, CASE WHEN movie_crews.job IN ? THEN '%s' ELSE '%s' END AS kind, people.id AS feature_id, people.name",
+			model.RecommendationReasonDirector, model.RecommendationReasonWriter), recommendationDirectorJobs).
+		Joins("JOIN people ON people.id = movie_crews.person_id").
+		Where("movie_crews.movie_id IN ?", movieIDs).
+		Where("movie_crews.job IN ? OR movie_crews.job IN ?", recommendationDirectorJobs, recommendationWriterJobs).
+		Scan(&crew).Error; err != nil {
+		return nil, err
+	}
+	features = append(features, crew...)
+
+	var cast []MovieFeature
+	if err := r.db.Table("movie_casts").
+		Select(fmt.Sprintf("movie_casts.movie_id, '%s' AS kind, people.id AS feature_id, people.name", model.RecommendationReasonActor)).
+		Joins("JOIN people ON people.id = movie_casts.person_id").
+		Where("movie_casts.movie_id IN ? AND movie_casts.cast_order < ?", movieIDs, recommendationCastOrder).
+		Scan(&cast).Error; err != nil {
+		return nil, err
+	}
+	return append(features, cast...), nil
+}
+
+// films partageant un réalisateur, un scénariste ou une tête d'affiche avec personIDs
+func (r *RecommendationRepository) MoviesWithPeople(personIDs []uint, limit int) ([]uint, error) {
+	var ids []uint
+	if len(personIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Raw("SELECT movie_id FROM movie_crews WHERE person_id IN ? AND (job IN ? OR job IN ?) "+
+		"UNION SELECT movie_id FROM movie_casts WHERE person_id IN ? AND cast_order < ? LIMIT ?",
+		personIDs, recommendationDirectorJobs, recommendationWriterJobs, personIDs, recommendationCastOrder, limit).
+		Scan(&ids).Error
+	return ids, err
+}
+
+// films les mieux notés par les membres, pour les comptes sans historique
+func (r *RecommendationRepository) TopRated(minRatings, limit int) ([]RatingRow, error) {
+	var rows []RatingRow
+	err := r.contributors().
+		Select("rates.movie_id, AVG(rates.rating) AS rating").
+		Joins("JOIN movies ON movies.id = rates.movie_id AND movies.deleted_at IS NULL").
+		Group("rates.movie_id").
+		Having("COUNT(*) >= ?", minRatings).
+		Order("rating DESC, rates.movie_id").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// films existants parmi ids (les films supprimés ne sont pas recommandés)
+func (r *RecommendationRepository) Movies(ids []uint) (map[uint]model.Movie, error) {
+	movies := make(map[uint]model.Movie, len(ids))
+	if len(ids) == 0 {
+		return movies, nil
+	}
+	var found []model.Movie
+	if err := r.db.Where("id IN ?", ids).Find(&found).Error; err != nil {
+		return nil, err
+	}
+	for _, movie := range found {
+		movies[movie.ID] = movie
+	}
+	return movies, nil
+}
+
+// RÉSULTATS
+
+// remplace les recommandations de l'utilisateur ; computedAt : début du calcul,
+// pour qu'une modification de l'historique pendant le calcul en relance un autre
+func (r *RecommendationRepository) Replace(userID uint, recommendations []model.Recommendation, computedAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.Recommendation{}).Error; err != nil {
+			return err
+		}
+		if len(recommendations) > 0 {
+			if err := tx.Create(&recommendations).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"computed_at"}),
+		}).Create(&model.RecommendationRun{UserID: userID, ComputedAt: &computedAt}).Error
+	})
+}
+
+func (r *RecommendationRepository) List(userID uint, page, limit int) ([]model.Recommendation, int64, error) {
+	var recommendations []model.Recommendation
+	var total int64
+
+	query := r.db.Model(&model.Recommendation{}).
+		Joins("JOIN movies ON movies.id = recommendations.movie_id AND movies.deleted_at IS NULL").
+		Where("recommendations.user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Preload("Movie").
+		Order("recommendations.position").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&recommendations).Error
+	return recommendations, total, err
+}
+
+func (r *RecommendationRepository) GetRun(userID uint) (*model.RecommendationRun, error) {
+	var run model.RecommendationRun
+	if err := r.db.First(&run, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *RecommendationRepository) MarkChanged(userID uint, at time.Time) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"changed_at"}),
+	}).Create(&model.RecommendationRun{UserID: userID, ChangedAt: &at}).Error
+}
+
+// membres actifs dont les recommandations sont à (re)calculer : jamais
+// calculées, historique modifié, ou calcul antérieur à staleBefore
+func (r *RecommendationRepository) UsersToRefresh(staleBefore time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.User{}).
+		Joins("LEFT JOIN recommendation_runs runs ON runs.user_id = users.id").
+		Where("runs.computed_at IS NULL OR runs.changed_at > runs.computed_at OR runs.computed_at < ?", staleBefore).
+		Where("EXISTS (SELECT 1 FROM tracks WHERE tracks.user_id = users.id)").
+		Order("users.id").
+		Limit(limit).
+		Pluck("users.id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// signalements encore ouverts sur le même contenu (aide au tri de la file)
+const reportTargetCountSQL = "(SELECT COUNT(*) FROM reports same_target WHERE same_target.target_type = reports.target_type " +
+	"AND same_target.target_user_id = reports.target_user_id AND COALESCE(same_target.movie_id, 0) = COALESCE(reports.movie_id, 0) " +
+	"AND same_target.status = 'open')"
+
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// filtres de la file de modération (champs vides => ignorés)
+type ReportFilter struct {
+	Status     string
+	TargetType string
+	Reason     string
+}
+
+// signalement avec le nombre de signalements ouverts sur le même contenu
+type ReportSummary struct {
+	model.Report
+	TargetReportCount int64 `gorm:"column:target_report_count"`
+}
+
+func (r *ReportRepository) Create(report *model.Report) error {
+	return r.db.Create(report).Error
+}
+
+// le reporter (nil : signalement automatique) a-t-il déjà un signalement
+// ouvert sur ce contenu ?
+func (r *ReportRepository) HasOpenReport(reporterID *uint, targetType string, targetUserID uint, movieID *uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&model.Report{}).
+		Where("target_type = ? AND target_user_id = ? AND status = ?", targetType, targetUserID, model.ReportStatusOpen)
+	if reporterID != nil {
+		query = query.Where("reporter_id = ?", *reporterID)
+	} else {
+		query = query.Where("reporter_id IS NULL")
+	}
+	if movieID != nil {
+		query = query.Where("movie_id = ?", *movieID)
+	} else {
+		query = query.Where("movie_id IS NULL")
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+func (r *ReportRepository) GetByID(id uint) (*ReportSummary, error) {
+	var report ReportSummary
+	err := r.db.Model(&model.Report{}).
+		Select("reports.*, "+reportTargetCountSQL+" AS target_report_count").
+		Preload("Reporter").
+		Preload("TargetUser").
+		Preload("Movie").
+		Where("reports.id = ?", id).
+		First(&report).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// file de modération, les plus anciens d'abord
+func (r *ReportRepository) List(filter ReportFilter, page, limit int) ([]ReportSummary, int64, error) {
+	var reports []ReportSummary
+	var total int64
+
+	query := r.db.Model(&model.Report{})
+	if filter.Status != "" {
+		query = query.Where("reports.status = ?", filter.Status)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("reports.target_type = ?", filter.TargetType)
+	}
+	if filter.Reason != "" {
+		query = query.Where("reports.reason = ?", filter.Reason)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.
+		Select("reports.*, " + reportTargetCountSQL + " AS target_report_count").
+		Preload("Reporter").
+		Preload("TargetUser").
+		Preload("Movie").
+		Order("reports.created_at ASC, reports.id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&reports).Error
+	return reports, total, err
+}
+
+// enregistre la mesure et le nouvel état du signalement ensemble
+func (r *ReportRepository) AddAction(report *model.Report, action *model.ReportAction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Report{}).Where("id = ?", report.ID).Updates(map[string]interface{}{
+			"status":         report.Status,
+			"resolved_by_id": report.ResolvedByID,
+			"resolved_at":    report.ResolvedAt,
+			"updated_at":     time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		action.ReportID = report.ID
+		return tx.Create(action).Error
+	})
+}
+
+// historique d'un signalement, dans l'ordre chronologique
+func (r *ReportRepository) ListActions(reportID uint) ([]model.ReportAction, error) {
+	var actions []model.ReportAction
+	err := r.db.Preload("Moderator").
+		Where("report_id = ?", reportID).
+		Order("created_at ASC, id ASC").
+		Find(&actions).Error
+	return actions, err
+}
@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tris acceptés pour la liste des critiques d'un film
+const (
+	ReviewSortRecent  = "recent"
+	ReviewSortPopular = "popular"
+	ReviewSortHighest = "highest"
+	ReviewSortLowest  = "lowest"
+)
+
+// nombre de likes d'une critique (sous-requête corrélée sur reviews)
+const reviewLikeCountSQL = "(SELECT COUNT(*) FROM review_likes WHERE review_likes.review_user_id = reviews.user_id AND review_likes.movie_id = reviews.movie_id)"
+
+// NULL en dernier quel que soit le sens (les critiques sans note)
+var reviewSortOrders = map[string]string{
+	ReviewSortRecent:  "reviews.created_at DESC",
+	ReviewSortPopular: "like_count DESC, reviews.created_at DESC",
+	ReviewSortHighest: "rates.rating IS NULL, rates.rating DESC, reviews.created_at DESC",
+	ReviewSortLowest:  "rates.rating IS NULL, rates.rating ASC, reviews.created_at DESC",
+}
+
+func IsValidReviewSort(sort string) bool {
+	_, ok := reviewSortOrders[sort]
+	return ok
+}
+
+// colonnes communes des listes de critiques ; le paramètre est l'id du viewer
+const reviewSelectSQL = "reviews.user_id, users.username, users.profile_picture_url, rates.rating, reviews.content, reviews.content_html, reviews.is_spoiler, reviews.edited_at, reviews.created_at, reviews.updated_at, " +
+	reviewLikeCountSQL + " AS like_count, " +
+	"EXISTS (SELECT 1 FROM review_likes viewer_likes WHERE viewer_likes.review_user_id = reviews.user_id AND viewer_likes.movie_id = reviews.movie_id AND viewer_likes.user_id = ?) AS liked_by_viewer"
+
+// une critique masquée par la modération n'est visible que de son auteur ;
+// celles d'un profil non visible du viewer sont exclues
+func reviewShownTo(viewerID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("reviews.hidden_at IS NULL OR reviews.user_id = ?", viewerID).
+			Scopes(profileVisibleTo(viewerID, "reviews.user_id"))
+	}
+}
+
+// note de l'auteur jointe seulement s'il l'affiche (ou si c'est le viewer),
+// pour que les tris par note ne la révèlent pas non plus ; users doit être joint
+const reviewRatesJoinSQL = "LEFT JOIN rates ON rates.movie_id = reviews.movie_id AND rates.user_id = reviews.user_id AND (users.show_ratings = ? OR users.id = ?)"
+
+type ReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+type MovieReviewFilter struct {
+	Sort        string
+	FriendsOnly bool // uniquement les users suivis par le viewer
+	ViewerID    uint
+}
+
+type MovieReviewResult struct {
+	UserID            uint       `gorm:"column:user_id"`
+	Username          string     `gorm:"column:username"`
+	ProfilePictureURL *string    `gorm:"column:profile_picture_url"`
+	Rating            *float32   `gorm:"column:rating"`
+	Content           string     `gorm:"column:content"`
+	ContentHTML       string     `gorm:"column:content_html"`
+	IsSpoiler         bool       `gorm:"column:is_spoiler"`
+	LikeCount         int64      `gorm:"column:like_count"`
+	LikedByViewer     bool       `gorm:"column:liked_by_viewer"`
+	EditedAt          *time.Time `gorm:"column:edited_at"`
+	CreatedAt         time.Time  `gorm:"column:created_at"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at"`
+}
+
+// critique récente candidate au classement "populaires de la semaine"
+type PopularReviewCandidate struct {
+	MovieReviewResult
+	MovieID     uint   `gorm:"column:movie_id"`
+	TmdbID      int    `gorm:"column:tmdb_id"`
+	Title       string `gorm:"column:title"`
+	ReleaseYear int    `gorm:"column:release_year"`
+	PosterURL   string `gorm:"column:poster_url"`
+}
+
+func (r *ReviewRepository) ListForMovie(movieID uint, filter MovieReviewFilter, page, limit int) ([]MovieReviewResult, int64, error) {
+	var results []MovieReviewResult
+	var total int64
+
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := r.db.Table("reviews").
+		Select(reviewSelectSQL, filter.ViewerID).
+		Joins("JOIN users ON users.id = reviews.user_id AND users.deleted_at IS NULL").
+		Joins(reviewRatesJoinSQL, true, filter.ViewerID).
+		Where("reviews.movie_id = ?", movieID).
+		Scopes(reviewShownTo(filter.ViewerID), hiddenFilterFor(filter.ViewerID, "reviews.user_id"))
+
+	if filter.FriendsOnly {
+		query = query.Where("reviews.user_id IN (?)",
+			r.db.Model(&model.Follow{}).Select("followed_id").Where("follower_id = ?", filter.ViewerID))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order, ok := reviewSortOrders[filter.Sort]
+	if !ok {
+		order = reviewSortOrders[ReviewSortRecent]
+	}
+
+	if err := query.
+		Order(order).
+		Offset(offset).
+		Limit(limit).
+		Find(&results).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, nil
+}
+
+// le user a-t-il déjà vu (loggé) ce film ?
+func (r *ReviewRepository) HasWatched(userID, movieID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Track{}).
+		Where("user_id = ? AND movie_id = ? AND is_watched = ?", userID, movieID, true).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// films parmi movieIDs que le user a déjà vus
+func (r *ReviewRepository) WatchedMovieIDs(userID uint, movieIDs []uint) (map[uint]bool, error) {
+	return watchedMovieIDs(r.db, userID, movieIDs)
+}
+
+func watchedMovieIDs(db *gorm.DB, userID uint, movieIDs []uint) (map[uint]bool, error) {
+	watched := make(map[uint]bool)
+	if len(movieIDs) == 0 {
+		return watched, nil
+	}
+
+	var ids []uint
+	if err := db.Model(&model.Track{}).
+		Where("user_id = ? AND movie_id IN ? AND is_watched = ?", userID, movieIDs, true).
+		Pluck("movie_id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		watched[id] = true
+	}
+	return watched, nil
+}
+
+func (r *ReviewRepository) Get(reviewUserID, movieID uint) (*model.Review, error) {
+	var review model.Review
+	if err := r.db.Where("user_id = ? AND movie_id = ?", reviewUserID, movieID).First(&review).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// comme Get, mais introuvable si l'auteur et le viewer se bloquent ou si
+// la critique a été masquée par la modération
+func (r *ReviewRepository) GetVisible(viewerID, reviewUserID, movieID uint) (*model.Review, error) {
+	var review model.Review
+	if err := r.db.Where("user_id = ? AND movie_id = ?", reviewUserID, movieID).
+		Scopes(reviewShownTo(viewerID), notBlockedFor(viewerID, "reviews.user_id")).
+		First(&review).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// versions antérieures d'une critique, de la plus récente à la plus ancienne ;
+// includeDeleted : avec celles des critiques supprimées (modération)
+func (r *ReviewRepository) ListRevisions(reviewUserID, movieID uint, includeDeleted bool) ([]model.ReviewRevision, error) {
+	var revisions []model.ReviewRevision
+	query := r.db
+	if includeDeleted {
+		query = query.Unscoped()
+	}
+	err := query.Where("user_id = ? AND movie_id = ?", reviewUserID, movieID).
+		Order("replaced_at DESC, id DESC").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *ReviewRepository) SetCommentsLocked(reviewUserID, movieID uint, locked bool) error {
+	return r.db.Model(&model.Review{}).
+		Where("user_id = ? AND movie_id = ?", reviewUserID, movieID).
+		Update("comments_locked", locked).Error
+}
+
+// hiddenAt nil => critique de nouveau visible
+func (r *ReviewRepository) SetHidden(reviewUserID, movieID uint, hiddenAt *time.Time) error {
+	return checkAffected(r.db.Model(&model.Review{}).
+		Where("user_id = ? AND movie_id = ?", reviewUserID, movieID).
+		Update("hidden_at", hiddenAt))
+}
+
+// impose le flag spoiler ; l'auteur ne pourra plus le retirer
+func (r *ReviewRepository) ForceSpoiler(reviewUserID, movieID uint) error {
+	return checkAffected(r.db.Model(&model.Review{}).
+		Where("user_id = ? AND movie_id = ?", reviewUserID, movieID).
+		Updates(map[string]interface{}{"is_spoiler": true, "spoiler_forced": true}))
+}
+
+// critique existante et visible par viewerID
+func (r *ReviewRepository) Exists(viewerID, reviewUserID, movieID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Review{}).
+		Where("user_id = ? AND movie_id = ?", reviewUserID, movieID).
+		Scopes(reviewShownTo(viewerID), notBlockedFor(viewerID, "reviews.user_id")).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// idempotent : liker deux fois ne compte qu'une fois
+func (r *ReviewRepository) Like(userID, reviewUserID, movieID uint) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.ReviewLike{UserID: userID, ReviewUserID: reviewUserID, MovieID: movieID}).Error
+}
+
+func (r *ReviewRepository) Unlike(userID, reviewUserID, movieID uint) error {
+	return r.db.Where("user_id = ? AND review_user_id = ? AND movie_id = ?", userID, reviewUserID, movieID).
+		Delete(&model.ReviewLike{}).Error
+}
+
+func (r *ReviewRepository) CountLikes(reviewUserID, movieID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.ReviewLike{}).
+		Where("review_user_id = ? AND movie_id = ?", reviewUserID, movieID).
+		Count(&count).Error
+	return count, err
+}
+
+// critiques publiées depuis since ayant au moins un like, les plus likées d'abord ;
+// le classement final (avec décroissance temporelle) est fait par le service.
+// commun à tous les viewers (mis en cache) : profils publics uniquement
+func (r *ReviewRepository) ListPopularCandidates(since time.Time, max int) ([]PopularReviewCandidate, error) {
+	var results []PopularReviewCandidate
+
+	err := r.db.Table("reviews").
+		Select(reviewSelectSQL+", movies.id AS movie_id, movies.tmdb_id, movies.title, movies.release_year, movies.poster_url", 0).
+		Joins("JOIN users ON users.id = reviews.user_id AND users.deleted_at IS NULL").
+		Joins("JOIN movies ON movies.id = reviews.movie_id").
+		Joins(reviewRatesJoinSQL, true, 0).
+		Where("reviews.created_at >= ? AND reviews.hidden_at IS NULL", since).
+		Where("users.profile_visibility = ?", model.ProfileVisibilityPublic).
+		Where(reviewLikeCountSQL + " > 0").
+		Order("like_count DESC, reviews.created_at DESC").
+		Limit(max).
+		Find(&results).Error
+
+	return results, err
+}
+
+// clé d'une critique : (auteur, film)
+type ReviewKey struct {
+	UserID  uint
+	MovieID uint
+}
+
+// critiques likées par le user parmi celles des films movieIDs
+func (r *ReviewRepository) LikedReviews(userID uint, movieIDs []uint) (map[ReviewKey]bool, error) {
+	liked := make(map[ReviewKey]bool)
+	if len(movieIDs) == 0 {
+		return liked, nil
+	}
+
+	var likes []model.ReviewLike
+	if err := r.db.Where("user_id = ? AND movie_id IN ?", userID, movieIDs).Find(&likes).Error; err != nil {
+		return nil, err
+	}
+
+	for _, like := range likes {
+		liked[ReviewKey{UserID: like.ReviewUserID, MovieID: like.MovieID}] = true
+	}
+	return liked, nil
+}
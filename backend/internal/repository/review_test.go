@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+)
+
+func seedMovieReviews(t *testing.T) (*ReviewRepository, *model.Movie, []*model.User) {
+	db := setupTestDB(t)
+	db.AutoMigrate(&model.Follow{})
+
+	movie := &model.Movie{TmdbID: 603, Title: "The Matrix"}
+	db.Create(movie)
+
+	users := []*model.User{
+		{Username: "viewer", Email: "viewer@example.com"},
+		{Username: "ana", Email: "ana@example.com"},
+		{Username: "ben", Email: "ben@example.com"},
+		{Username: "cleo", Email: "cleo@example.com"},
+	}
+	for _, u := range users {
+		db.Create(u)
+	}
+
+	now := time.Now()
+	ratings := map[uint]*float32{}
+	four, two := float32(4), float32(2)
+	ratings[users[1].ID] = &four
+	ratings[users[2].ID] = &two
+
+	for i, u := range users[1:] {
+		db.Create(&model.Review{UserID: u.ID, MovieID: movie.ID, Content: "review by " + u.Username, CreatedAt: now.Add(time.Duration(i) * time.Minute)})
+		if rating := ratings[u.ID]; rating != nil {
+			db.Create(&model.Rate{UserID: u.ID, MovieID: movie.ID, Rating: *rating})
+		}
+	}
+
+	// viewer suit ben ; ben a 2 likes, cleo 1
+	db.Create(&model.Follow{FollowerID: users[0].ID, FollowedID: users[2].ID})
+	db.Create(&model.ReviewLike{UserID: users[0].ID, ReviewUserID: users[2].ID, MovieID: movie.ID})
+	db.Create(&model.ReviewLike{UserID: users[1].ID, ReviewUserID: users[2].ID, MovieID: movie.ID})
+	db.Create(&model.ReviewLike{UserID: users[1].ID, ReviewUserID: users[3].ID, MovieID: movie.ID})
+
+	return NewReviewRepository(db), movie, users
+}
+
+func TestReviewRepository_ListForMovie(t *testing.T) {
+	repo, movie, users := seedMovieReviews(t)
+
+	tests := []struct {
+		name   string
+		filter MovieReviewFilter
+		want   []string
+	}{
+		{"recent", MovieReviewFilter{Sort: ReviewSortRecent}, []string{"cleo", "ben", "ana"}},
+		{"popular", MovieReviewFilter{Sort: ReviewSortPopular}, []string{"ben", "cleo", "ana"}},
+		{"highest rated, unrated last", MovieReviewFilter{Sort: ReviewSortHighest}, []string{"ana", "ben", "cleo"}},
+		{"lowest rated, unrated last", MovieReviewFilter{Sort: ReviewSortLowest}, []string{"ben", "ana", "cleo"}},
+		{"friends only", MovieReviewFilter{Sort: ReviewSortRecent, FriendsOnly: true, ViewerID: users[0].ID}, []string{"ben"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, total, err := repo.ListForMovie(movie.ID, tt.filter, 1, 10)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if int(total) != len(tt.want) || len(results) != len(tt.want) {
+				t.Fatalf("expected %d reviews, got %d (total %d)", len(tt.want), len(results), total)
+			}
+			for i, username := range tt.want {
+				if results[i].Username != username {
+					t.Errorf("expected %s at position %d, got %s", username, i, results[i].Username)
+				}
+			}
+		})
+	}
+}
+
+func TestReviewRepository_LikesAndPopularCandidates(t *testing.T) {
+	repo, movie, users := seedMovieReviews(t)
+	viewer, ana, ben := users[0], users[1], users[2]
+
+	results, _, err := repo.ListForMovie(movie.ID, MovieReviewFilter{Sort: ReviewSortPopular, ViewerID: viewer.ID}, 1, 10)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if results[0].LikeCount != 2 || !results[0].LikedByViewer {
+		t.Errorf("expected ben's review to have 2 likes including the viewer's, got %+v", results[0])
+	}
+
+	// liker deux fois ne compte qu'une fois
+	repo.Like(viewer.ID, ana.ID, movie.ID)
+	repo.Like(viewer.ID, ana.ID, movie.ID)
+	if count, _ := repo.CountLikes(ana.ID, movie.ID); count != 1 {
+		t.Errorf("expected 1 like on ana's review, got %d", count)
+	}
+	repo.Unlike(viewer.ID, ana.ID, movie.ID)
+	if count, _ := repo.CountLikes(ana.ID, movie.ID); count != 0 {
+		t.Errorf("expected unlike to remove the like, got %d", count)
+	}
+
+	candidates, err := repo.ListPopularCandidates(time.Now().Add(-time.Hour), 10)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(candidates) != 2 || candidates[0].UserID != ben.ID || candidates[0].TmdbID != movie.TmdbID {
+		t.Errorf("expected only liked reviews, most liked first, got %+v", candidates)
+	}
+}
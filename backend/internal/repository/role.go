@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+type RoleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+func (r *RoleRepository) List() ([]model.Role, error) {
+	var roles []model.Role
+	err := r.db.Order("id ASC").Find(&roles).Error
+	return roles, err
+}
+
+func (r *RoleRepository) GetByID(id uint) (*model.Role, error) {
+	var role model.Role
+	if err := r.db.First(&role, id).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *RoleRepository) GetByName(name string) (*model.Role, error) {
+	var role model.Role
+	if err := r.db.Where("name = ?", name).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *RoleRepository) Create(role *model.Role) error {
+	return r.db.Create(role).Error
+}
+
+func (r *RoleRepository) Update(role *model.Role) error {
+	return r.db.Save(role).Error
+}
+
+func (r *RoleRepository) Delete(id uint) error {
+	return r.db.Delete(&model.Role{}, id).Error
+}
+
+func (r *RoleRepository) CountUsers(name string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.User{}).Where("role = ?", name).Count(&count).Error
+	return count, err
+}
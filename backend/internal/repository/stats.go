@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// agrégats du bilan annuel ; les requêtes portent sur les films vus par
+// l'utilisateur dans [from, to[ (tracks.watched_date)
+type StatsRepository struct {
+	db *gorm.DB
+}
+
+func NewStatsRepository(db *gorm.DB) *StatsRepository {
+	return &StatsRepository{db: db}
+}
+
+// un film vu sur la période, avec la note de l'utilisateur
+type StatsTrack struct {
+	MovieID         uint       `gorm:"column:movie_id"`
+	TmdbID          int        `gorm:"column:tmdb_id"`
+	Title           string     `gorm:"column:title"`
+	PosterURL       string     `gorm:"column:poster_url"`
+	ReleaseYear     int        `gorm:"column:release_year"`
+	DurationMinutes int        `gorm:"column:duration_minutes"`
+	WatchedDate     time.Time  `gorm:"column:watched_date"`
+	WatchCount      int        `gorm:"column:watch_count"`
+	Rating          *float32   `gorm:"column:rating"`
+	MetadataSynced  *time.Time `gorm:"column:metadata_synced_at"`
+}
+
+type StatsCount struct {
+	Key   string `gorm:"column:stat_key"` // identifiant (genre, pays, personne)
+	Name  string `gorm:"column:name"`
+	Image string `gorm:"column:image"`
+	Films int64  `gorm:"column:films"`
+}
+
+func (r *StatsRepository) watchedIn(userID uint, from, to time.Time) *gorm.DB {
+	return r.db.Table("tracks").
+		Joins("JOIN movies ON movies.id = tracks.movie_id AND movies.deleted_at IS NULL").
+		Where("tracks.user_id = ? AND tracks.is_watched = ?", userID, true).
+		Where("tracks.watched_date >= ? AND tracks.watched_date < ?", from, to)
+}
+
+func (r *StatsRepository) Tracks(userID uint, from, to time.Time) ([]StatsTrack, error) {
+	var tracks []StatsTrack
+	err := r.watchedIn(userID, from, to).
+		Select("tracks.movie_id, movies.tmdb_id, movies.title, movies.poster_url, movies.release_year, movies.duration_minutes, " +
+			"tracks.watched_date, tracks.watch_count, rates.rating, movies.metadata_synced_at").
+		Joins("LEFT JOIN rates ON rates.user_id = tracks.user_id AND rates.movie_id = tracks.movie_id").
+		Order("tracks.watched_date").
+		Scan(&tracks).Error
+	return tracks, err
+}
+
+// dates de visionnage de tous les films vus (une par film : la dernière)
+func (r *StatsRepository) WatchedDates(userID uint) ([]time.Time, error) {
+	var dates []time.Time
+	err := r.db.Model(&model.Track{}).
+		Where("user_id = ? AND is_watched = ? AND watched_date IS NOT NULL", userID, true).
+		Order("watched_date").
+		Pluck("watched_date", &dates).Error
+	return dates, err
+}
+
+func (r *StatsRepository) TopGenres(userID uint, from, to time.Time, limit int) ([]StatsCount, error) {
+	var counts []StatsCount
+	err := r.watchedIn(userID, from, to).
+		Select("genres.id AS stat_key, genres.name, '' AS image, COUNT(*) AS films").
+		Joins("JOIN movie_genres ON movie_genres.movie_id = movies.id").
+		Joins("JOIN genres ON genres.id = movie_genres.genre_id").
+		Group("genres.id, genres.name").
+		Order("films DESC, genres.name").
+		Limit(limit).
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *StatsRepository) TopCountries(userID uint, from, to time.Time, limit int) ([]StatsCount, error) {
+	var counts []StatsCount
+	err := r.watchedIn(userID, from, to).
+		Select("countries.code AS stat_key, countries.name, '' AS image, COUNT(*) AS films").
+		Joins("JOIN movie_countries ON movie_countries.movie_id = movies.id").
+		Joins("JOIN countries ON countries.code = movie_countries.country_code").
+		Group("countries.code, countries.name").
+		Order("films DESC, countries.name").
+		Limit(limit).
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *StatsRepository) TopDirectors(userID uint, from, to time.Time, limit int) ([]StatsCount, error) {
+	var counts []StatsCount
+	err := r.watchedIn(userID, from, to).
+		Select("people.tmdb_id AS stat_key, people.name, people.profile_picture_url AS image, COUNT(DISTINCT movies.id) AS films").
+		Joins("JOIN movie_crews ON movie_crews.movie_id = movies.id AND movie_crews.job = ?", "Director").
+		Joins("JOIN people ON people.id = movie_crews.person_id").
+		Group("people.tmdb_id, people.name, people.profile_picture_url").
+		Order("films DESC, people.name").
+		Limit(limit).
+		Scan(&counts).Error
+	return counts, err
+}
+
+// maxOrder : seuls les premiers rôles comptent (CastOrder < maxOrder)
+func (r *StatsRepository) TopActors(userID uint, from, to time.Time, maxOrder, limit int) ([]StatsCount, error) {
+	var counts []StatsCount
+	err := r.watchedIn(userID, from, to).
+		Select("people.tmdb_id AS stat_key, people.name, people.profile_picture_url AS image, COUNT(*) AS films").
+		Joins("JOIN movie_casts ON movie_casts.movie_id = movies.id AND movie_casts.cast_order < ?", maxOrder).
+		Joins("JOIN people ON people.id = movie_casts.person_id").
+		Group("people.tmdb_id, people.name, people.profile_picture_url").
+		Order("films DESC, people.name").
+		Limit(limit).
+		Scan(&counts).Error
+	return counts, err
+}
+
+// moyenne des notes des autres membres sur les films notés par l'utilisateur
+// sur la période ; nil si personne d'autre ne les a notés
+func (r *StatsRepository) CommunityAverage(userID uint, from, to time.Time) (*float64, error) {
+	var average *float64
+	rated := r.watchedIn(userID, from, to).
+		Select("tracks.movie_id").
+		Joins("JOIN rates mine ON mine.user_id = tracks.user_id AND mine.movie_id = tracks.movie_id")
+	err := r.db.Model(&model.Rate{}).
+		Select("AVG(rating)").
+		Where("movie_id IN (?) AND user_id <> ?", rated, userID).
+		Scan(&average).Error
+	return average, err
+}
+
+// change dès qu'un suivi ou une note de l'utilisateur change : sert de clé de cache
+type StatsFingerprint struct {
+	Tracks    int64  `gorm:"column:tracks"`
+	LastTrack string `gorm:"column:last_track"`
+	Rates     int64  `gorm:"column:rates"`
+	LastRate  string `gorm:"column:last_rate"`
+}
+
+func (r *StatsRepository) Fingerprint(userID uint) (StatsFingerprint, error) {
+	var fingerprint StatsFingerprint
+	err := r.db.Raw(`SELECT
+		(SELECT COUNT(*) FROM tracks WHERE user_id = ?) AS tracks,
+		(SELECT COALESCE(CAST(MAX(updated_at) AS TEXT), '') FROM tracks WHERE user_id = ?) AS last_track,
+		(SELECT COUNT(*) FROM rates WHERE user_id = ?) AS rates,
+		(SELECT COALESCE(CAST(MAX(updated_at) AS TEXT), '') FROM rates WHERE user_id = ?) AS last_rate`,
+		userID, userID, userID, userID).
+		Scan(&fingerprint).Error
+	return fingerprint, err
+}
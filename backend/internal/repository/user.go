@@ -1,21 +1,31 @@
 package repository
 
 import (
+	"strings"
+	"time"
+
 	"github.com/Nowap83/FrameRate/backend/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
 	Create(user *model.User) error
 	GetByID(id uint) (*model.User, error)
-	GetAllUsers(page, limit int) ([]*model.User, int64, error)
+	GetAllUsers(filter UserFilter, page, limit int) ([]*model.User, int64, error)
+	GetByIDUnscoped(id uint) (*model.User, error)
+	Restore(id uint, username, email string) error
 	GetByEmailOrUsername(login string) (*model.User, error)
 	GetByEmail(email string) (*model.User, error)
 	GetByUsername(username string) (*model.User, error)
+	SearchDiscoverable(viewerID uint, query string, limit int) ([]*model.User, error)
 	GetByVerificationToken(token string) (*model.User, error)
 	Update(user *model.User) error
 	UpdateFields(id uint, updates map[string]interface{}) error
 	Delete(id uint) error
+	CreateMagicLinkToken(token *model.MagicLinkToken) error
+	CountMagicLinkTokensSince(userID uint, since time.Time) (int64, error)
+	ConsumeMagicLinkToken(tokenHash string, now time.Time) (*model.User, error)
 }
 
 type GormUserRepository struct {
@@ -38,12 +48,73 @@ func (r *GormUserRepository) GetByID(id uint) (*model.User, error) {
 	return &user, nil
 }
 
-func (r *GormUserRepository) GetAllUsers(page, limit int) ([]*model.User, int64, error) {
+// valeurs acceptées pour UserFilter.Deleted
+const (
+	DeletedExclude = ""
+	DeletedInclude = "include"
+	DeletedOnly    = "only"
+)
+
+// colonnes triables de la liste admin
+var userSortColumns = map[string]string{
+	"created_at": "created_at",
+	"username":   "username",
+	"email":      "email",
+}
+
+// filtres de la liste admin (champs vides => ignorés)
+type UserFilter struct {
+	Query       string // sous-chaîne du username ou de l'email
+	Verified    *bool
+	Role        string
+	Suspended   *bool
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Deleted     string
+	Sort        string
+	Order       string
+}
+
+func (r *GormUserRepository) GetAllUsers(filter UserFilter, page, limit int) ([]*model.User, int64, error) {
 	var users []*model.User
 	var total int64
 
+	query := r.db.Model(&model.User{})
+
+	switch filter.Deleted {
+	case DeletedInclude:
+		query = query.Unscoped()
+	case DeletedOnly:
+		query = query.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+
+	if filter.Query != "" {
+		pattern := "%" + strings.ToLower(filter.Query) + "%"
+		query = query.Where("LOWER(username) LIKE ? OR LOWER(email) LIKE ?", pattern, pattern)
+	}
+	if filter.Verified != nil {
+		query = query.Where("is_verified = ?", *filter.Verified)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Suspended != nil {
+		active := "suspended_at IS NOT NULL AND (suspended_until IS NULL OR suspended_until > ?)"
+		if *filter.Suspended {
+			query = query.Where(active, time.Now())
+		} else {
+			query = query.Where("NOT ("+active+")", time.Now())
+		}
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at <= ?", *filter.CreatedTo)
+	}
+
 	// Count total users
-	if err := r.db.Model(&model.User{}).Count(&total).Error; err != nil {
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -53,14 +124,48 @@ func (r *GormUserRepository) GetAllUsers(page, limit int) ([]*model.User, int64,
 		offset = 0
 	}
 
+	column, ok := userSortColumns[filter.Sort]
+	if !ok {
+		column = "created_at"
+	}
+	direction := "DESC"
+	if filter.Order == "asc" {
+		direction = "ASC"
+	}
+
 	// Fetch paginated users
-	if err := r.db.Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+	if err := query.
+		Order(column + " " + direction).
+		Order("id " + direction).
+		Offset(offset).
+		Limit(limit).
+		Find(&users).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return users, total, nil
 }
 
+// inclut les comptes soft-deleted
+func (r *GormUserRepository) GetByIDUnscoped(id uint) (*model.User, error) {
+	var user model.User
+	if err := r.db.Unscoped().First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// annule le soft-delete et remet les identifiants d'origine
+func (r *GormUserRepository) Restore(id uint, username, email string) error {
+	return r.db.Unscoped().Model(&model.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"deleted_at":       nil,
+		"username":         username,
+		"email":            email,
+		"deleted_username": nil,
+		"deleted_email":    nil,
+	}).Error
+}
+
 func (r *GormUserRepository) GetByEmailOrUsername(login string) (*model.User, error) {
 	var user model.User
 	if err := r.db.Where("email = ? OR username = ?", login, login).First(&user).Error; err != nil {
@@ -85,6 +190,25 @@ func (r *GormUserRepository) GetByUsername(username string) (*model.User, error)
 	return &user, nil
 }
 
+// recherche par nom d'utilisateur parmi les comptes qui acceptent d'apparaître
+// dans la recherche, hors blocages ; les noms qui commencent par query d'abord
+func (r *GormUserRepository) SearchDiscoverable(viewerID uint, query string, limit int) ([]*model.User, error) {
+	var users []*model.User
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(query))
+
+	err := r.db.Model(&model.User{}).
+		Where("discoverable = ?", true).
+		Where(`LOWER(username) LIKE ? ESCAPE '\'`, "%"+escaped+"%").
+		Scopes(notBlockedFor(viewerID, "users.id")).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  `CASE WHEN LOWER(username) LIKE ? ESCAPE '\' THEN 0 ELSE 1 END, username`,
+			Vars: []interface{}{escaped + "%"},
+		}}).
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
 func (r *GormUserRepository) GetByVerificationToken(token string) (*model.User, error) {
 	var user model.User
 	if err := r.db.Where("verification_token = ?", token).First(&user).Error; err != nil {
@@ -104,3 +228,45 @@ func (r *GormUserRepository) UpdateFields(id uint, updates map[string]interface{
 func (r *GormUserRepository) Delete(id uint) error {
 	return r.db.Delete(&model.User{}, id).Error
 }
+
+func (r *GormUserRepository) CreateMagicLinkToken(token *model.MagicLinkToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *GormUserRepository) CountMagicLinkTokensSince(userID uint, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.MagicLinkToken{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&count).Error
+	return count, err
+}
+
+// marque le token comme utilisé et retourne son user, en une seule transaction
+// pour qu'un même lien ne puisse pas être consommé deux fois
+func (r *GormUserRepository) ConsumeMagicLinkToken(tokenHash string, now time.Time) (*model.User, error) {
+	var user model.User
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var token model.MagicLinkToken
+		if err := tx.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+			First(&token).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.MagicLinkToken{}).
+			Where("id = ? AND used_at IS NULL", token.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.First(&user, token.UserID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/Nowap83/FrameRate/backend/internal/model"
 	"github.com/glebarez/sqlite"
@@ -15,7 +16,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		t.Fatalf("Failed to open test database: %v", err)
 	}
 
-	err = db.AutoMigrate(&model.User{}, &model.Movie{}, &model.Track{}, &model.Rate{}, &model.Review{})
+	err = db.AutoMigrate(&model.User{}, &model.MagicLinkToken{}, &model.Movie{}, &model.Track{}, &model.Rate{}, &model.Review{}, &model.ReviewLike{}, &model.ReviewComment{}, &model.ReviewRevision{}, &model.Block{}, &model.Mute{})
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
@@ -220,7 +221,7 @@ func TestUserRepository_GetAllUsers(t *testing.T) {
 		})
 	}
 
-	users, total, err := repo.GetAllUsers(1, 3)
+	users, total, err := repo.GetAllUsers(UserFilter{}, 1, 3)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -231,8 +232,85 @@ func TestUserRepository_GetAllUsers(t *testing.T) {
 		t.Errorf("expected 3 users per page, got %d", len(users))
 	}
 
-	users2, total2, err := repo.GetAllUsers(2, 3)
+	users2, total2, err := repo.GetAllUsers(UserFilter{}, 2, 3)
 	if err != nil || total2 != 5 || len(users2) != 2 {
 		t.Errorf("expected second page to have 2 users, got %d", len(users2))
 	}
 }
+
+func TestUserRepository_GetAllUsers_Filters(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	now := time.Now()
+	db.Create(&model.User{Username: "alice", Email: "alice@example.com", IsVerified: true, Role: model.RoleAdmin, CreatedAt: now.Add(-48 * time.Hour)})
+	db.Create(&model.User{Username: "bob", Email: "bob@films.org", IsVerified: false, Role: model.RoleUser, CreatedAt: now.Add(-24 * time.Hour)})
+	db.Create(&model.User{Username: "carol", Email: "carol@example.com", IsVerified: true, Role: model.RoleUser, SuspendedAt: &now, CreatedAt: now})
+	deleted := &model.User{Username: "dave", Email: "dave@example.com", Role: model.RoleUser}
+	db.Create(deleted)
+	db.Delete(deleted)
+
+	verified := true
+	suspended := true
+	from := now.Add(-36 * time.Hour)
+
+	tests := []struct {
+		name   string
+		filter UserFilter
+		want   []string
+	}{
+		{"default excludes deleted, newest first", UserFilter{}, []string{"carol", "bob", "alice"}},
+		{"search is case-insensitive on username or email", UserFilter{Query: "FILMS"}, []string{"bob"}},
+		{"verified", UserFilter{Verified: &verified, Sort: "username", Order: "asc"}, []string{"alice", "carol"}},
+		{"role", UserFilter{Role: model.RoleAdmin}, []string{"alice"}},
+		{"suspended", UserFilter{Suspended: &suspended}, []string{"carol"}},
+		{"created range", UserFilter{CreatedFrom: &from, Sort: "username", Order: "asc"}, []string{"bob", "carol"}},
+		{"only deleted", UserFilter{Deleted: DeletedOnly}, []string{"dave"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, total, err := repo.GetAllUsers(tt.filter, 1, 10)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if int(total) != len(tt.want) || len(users) != len(tt.want) {
+				t.Fatalf("expected %d users, got %d (total %d)", len(tt.want), len(users), total)
+			}
+			for i, username := range tt.want {
+				if users[i].Username != username {
+					t.Errorf("expected %s at position %d, got %s", username, i, users[i].Username)
+				}
+			}
+		})
+	}
+}
+
+func TestUserRepository_ConsumeMagicLinkToken(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	user := &model.User{Username: "testuser", Email: "test@example.com"}
+	db.Create(user)
+
+	now := time.Now()
+	repo.CreateMagicLinkToken(&model.MagicLinkToken{UserID: user.ID, TokenHash: "valid", ExpiresAt: now.Add(time.Minute)})
+	repo.CreateMagicLinkToken(&model.MagicLinkToken{UserID: user.ID, TokenHash: "expired", ExpiresAt: now.Add(-time.Minute)})
+
+	count, err := repo.CountMagicLinkTokensSince(user.ID, now.Add(-time.Hour))
+	if err != nil || count != 2 {
+		t.Errorf("expected 2 tokens, got %d (%v)", count, err)
+	}
+
+	found, err := repo.ConsumeMagicLinkToken("valid", now)
+	if err != nil || found.ID != user.ID {
+		t.Fatalf("expected token to be consumed, got %v", err)
+	}
+
+	if _, err := repo.ConsumeMagicLinkToken("valid", now); err == nil {
+		t.Errorf("expected token to be single use")
+	}
+	if _, err := repo.ConsumeMagicLinkToken("expired", now); err == nil {
+		t.Errorf("expected expired token to be rejected")
+	}
+}
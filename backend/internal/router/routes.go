@@ -3,6 +3,7 @@ package router
 import (
 	"github.com/Nowap83/FrameRate/backend/internal/handler"
 	"github.com/Nowap83/FrameRate/backend/internal/middleware"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
 	"github.com/Nowap83/FrameRate/backend/internal/repository"
 	"github.com/Nowap83/FrameRate/backend/internal/service"
 	"github.com/Nowap83/FrameRate/backend/internal/utils"
@@ -14,10 +15,26 @@ import (
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(r *gin.Engine, db *gorm.DB, rdb *redis.Client, emailService *utils.EmailService) {
+func SetupRoutes(r *gin.Engine, db *gorm.DB, rdb *redis.Client, emailService *utils.EmailService, dispatcher *service.Dispatcher, scheduler *service.Scheduler, events *service.EventBroker) {
+
+	auditRepo := repository.NewAuditRepository(db)
+	auditService := service.NewAuditService(auditRepo)
+	auditHandler := handler.NewAuditHandler(auditService)
+
+	followRepo := repository.NewFollowRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	notificationService := service.NewNotificationService(notificationRepo, followRepo, dispatcher)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	eventHandler := handler.NewEventHandler(events)
 
 	userRepo := repository.NewUserRepository(db)
 	authService := service.NewAuthService(userRepo, emailService)
+	authService.SetAuditService(auditService)
+	authService.SetDispatcher(dispatcher)
+	authService.SetNotificationService(notificationService)
+
+	contentPolicy := service.NewContentPolicyFromEnv()
+	authService.SetContentPolicy(contentPolicy)
 
 	authHandler := handler.NewAuthHandler(authService)
 
@@ -28,11 +45,86 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB, rdb *redis.Client, emailService *ut
 
 	movieRepo := repository.NewMovieRepository(db)
 	movieService := service.NewMovieService(movieRepo, tmdbService)
+	movieService.SetNotificationService(notificationService)
+	movieService.SetEventBroker(events)
+	movieService.SetContentPolicy(contentPolicy)
 	movieHandler := handler.NewMovieHandler(movieService)
 
 	userService := service.NewUserService(userRepo, movieRepo)
+	userService.SetAuditService(auditService)
+	userService.SetEventBroker(events)
+	userService.SetContentPolicy(contentPolicy)
+	userService.SetNotificationService(notificationService)
 	userHandler := handler.NewUserHandler(userService)
 
+	blockService := service.NewBlockService(repository.NewBlockRepository(db), userRepo)
+	blockHandler := handler.NewBlockHandler(blockService)
+
+	followService := service.NewFollowService(followRepo, userRepo)
+	followService.SetBlockService(blockService)
+	userService.SetFollowService(followService)
+	userService.SetBlockService(blockService)
+	followHandler := handler.NewFollowHandler(followService)
+
+	reviewRepo := repository.NewReviewRepository(db)
+	reviewService := service.NewReviewService(reviewRepo, movieRepo, cacheService)
+	reviewService.SetBlockService(blockService)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+
+	commentRepo := repository.NewCommentRepository(db)
+	commentService := service.NewCommentService(commentRepo, reviewRepo, movieRepo)
+	commentService.SetBlockService(blockService)
+	commentHandler := handler.NewCommentHandler(commentService)
+
+	statsService := service.NewStatsService(repository.NewStatsRepository(db), userRepo, movieService, cacheService)
+	statsService.SetDispatcher(dispatcher)
+	statsHandler := handler.NewStatsHandler(statsService)
+
+	challengeService := service.NewChallengeService(repository.NewChallengeRepository(db), userRepo, movieRepo, movieService)
+	challengeService.SetDispatcher(dispatcher)
+	challengeService.SetAuditService(auditService)
+	movieService.SetChallengeService(challengeService)
+	userService.SetChallengeService(challengeService)
+	challengeHandler := handler.NewChallengeHandler(challengeService)
+
+	achievementService := service.NewAchievementService(repository.NewBadgeRepository(db), repository.NewChallengeRepository(db), userRepo, movieService)
+	achievementService.SetNotificationService(notificationService)
+	achievementService.SetDispatcher(dispatcher)
+	achievementService.SetAuditService(auditService)
+	movieService.SetAchievementService(achievementService)
+	badgeHandler := handler.NewBadgeHandler(achievementService)
+
+	recommendationService := service.NewRecommendationService(repository.NewRecommendationRepository(db), userRepo, movieService)
+	recommendationService.SetDispatcher(dispatcher)
+	recommendationService.Schedule(scheduler)
+	movieService.SetRecommendationService(recommendationService)
+	recommendationHandler := handler.NewRecommendationHandler(recommendationService)
+
+	listRepo := repository.NewListRepository(db)
+	listService := service.NewListService(listRepo, userRepo, movieService)
+	listService.SetBlockService(blockService)
+	listHandler := handler.NewListHandler(listService)
+
+	roleRepo := repository.NewRoleRepository(db)
+	authzService := service.NewAuthorizationService(userRepo, roleRepo)
+	userService.SetAuthorizationService(authzService)
+	reviewService.SetAuthorizationService(authzService)
+	roleService := service.NewRoleService(roleRepo, userRepo, authzService)
+	roleService.SetAuditService(auditService)
+	roleService.SetNotificationService(notificationService)
+	roleHandler := handler.NewRoleHandler(roleService)
+
+	moderationService := service.NewModerationService(repository.NewReportRepository(db), reviewRepo, movieRepo, userRepo, userService, cacheService)
+	moderationService.SetAuthorizationService(authzService)
+	moderationService.SetNotificationService(notificationService)
+	moderationService.SetAuditService(auditService)
+	contentPolicy.SetFlagger(moderationService)
+	reportHandler := handler.NewReportHandler(moderationService)
+
+	requirePermission := func(permission string) gin.HandlerFunc {
+		return middleware.RequirePermission(authzService, permission)
+	}
+
 	// Health check (verif serveur)
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -50,6 +142,9 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB, rdb *redis.Client, emailService *ut
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
 			auth.GET("/verify-email", authHandler.VerifyEmail)
+			auth.POST("/magic-link", authHandler.RequestMagicLink)
+			auth.POST("/magic-link/verify", authHandler.LoginWithMagicLink)
+			auth.POST("/logout", middleware.CSRFProtected(), authHandler.Logout)
 		}
 
 		// TMDB
@@ -71,14 +166,38 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB, rdb *redis.Client, emailService *ut
 
 		// Routes protégées
 		protected := api.Group("")
-		protected.Use(middleware.AuthRequired(), middleware.APIRateLimiter())
+		protected.Use(middleware.AuthRequired(), middleware.RejectSuspended(authzService), middleware.APIRateLimiter())
 		{
 			// Admin routes
 			admin := protected.Group("/admin")
-			admin.Use(middleware.AdminRequired(userRepo))
 			{
-				admin.GET("/users", userHandler.GetAllUsers)
-				admin.DELETE("/users/:id", userHandler.DeleteUserAdmin)
+				admin.GET("/users", requirePermission(model.PermUsersRead), userHandler.GetAllUsers)
+				admin.GET("/users/:id", requirePermission(model.PermUsersRead), userHandler.GetUserAdmin)
+				admin.DELETE("/users/:id", requirePermission(model.PermUsersDelete), userHandler.DeleteUserAdmin)
+				admin.POST("/users/:id/suspend", requirePermission(model.PermUsersManage), userHandler.SuspendUser)
+				admin.POST("/users/:id/unsuspend", requirePermission(model.PermUsersManage), userHandler.UnsuspendUser)
+				admin.POST("/users/:id/verify", requirePermission(model.PermUsersManage), userHandler.ForceVerifyUser)
+				admin.POST("/users/:id/restore", requirePermission(model.PermUsersManage), userHandler.RestoreUser)
+				admin.PUT("/users/:id/role", requirePermission(model.PermRolesManage), roleHandler.AssignRole)
+				admin.GET("/audit-events", requirePermission(model.PermAuditRead), auditHandler.ListEvents)
+
+				admin.GET("/roles", requirePermission(model.PermRolesManage), roleHandler.ListRoles)
+				admin.POST("/roles", requirePermission(model.PermRolesManage), roleHandler.CreateRole)
+				admin.PUT("/roles/:id", requirePermission(model.PermRolesManage), roleHandler.UpdateRole)
+				admin.DELETE("/roles/:id", requirePermission(model.PermRolesManage), roleHandler.DeleteRole)
+
+				admin.GET("/reports", requirePermission(model.PermReportsManage), reportHandler.GetReports)
+				admin.GET("/reports/:id", requirePermission(model.PermReportsManage), reportHandler.GetReport)
+				admin.POST("/reports/:id/actions", requirePermission(model.PermReportsManage), reportHandler.ApplyAction)
+
+				admin.POST("/challenges", requirePermission(model.PermChallengesManage), challengeHandler.CreateChallenge)
+				admin.PUT("/challenges/:id", requirePermission(model.PermChallengesManage), challengeHandler.UpdateChallenge)
+				admin.DELETE("/challenges/:id", requirePermission(model.PermChallengesManage), challengeHandler.DeleteChallenge)
+
+				admin.GET("/badges", requirePermission(model.PermBadgesManage), badgeHandler.ListBadges)
+				admin.POST("/badges", requirePermission(model.PermBadgesManage), badgeHandler.CreateBadge)
+				admin.PUT("/badges/:id", requirePermission(model.PermBadgesManage), badgeHandler.UpdateBadge)
+				admin.DELETE("/badges/:id", requirePermission(model.PermBadgesManage), badgeHandler.DeleteBadge)
 			}
 
 			// Users
@@ -88,19 +207,112 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB, rdb *redis.Client, emailService *ut
 				users.GET("/me/films", userHandler.GetMyFilms)
 				users.GET("/me/reviews", userHandler.GetMyReviews)
 				users.GET("/me/watchlist", userHandler.GetMyWatchlist)
+				users.GET("/me/stats", statsHandler.GetYearStats)
+				users.GET("/me/activity", statsHandler.GetActivity)
+				users.GET("/me/goal", challengeHandler.GetGoal)
+				users.PUT("/me/goal", challengeHandler.SetGoal)
+				users.DELETE("/me/goal", challengeHandler.DeleteGoal)
+				users.GET("/me/challenges", challengeHandler.GetMyChallenges)
+				users.GET("/me/badges", badgeHandler.GetMyBadges)
+				users.GET("/me/recommendations", recommendationHandler.GetMyRecommendations)
 				users.PUT("/me", userHandler.UpdateProfile)
 				users.POST("/me/avatar", userHandler.UploadAvatar)
 				users.PUT("/me/password", userHandler.ChangePassword)
 				users.DELETE("/me", userHandler.DeleteAccount)
+				users.GET("/me/security-activity", auditHandler.GetMySecurityActivity)
+				users.GET("/me/lists", listHandler.GetMyLists)
+				users.GET("/me/lists/shared", listHandler.GetSharedLists)
+				users.GET("/me/list-invitations", listHandler.GetInvitations)
+				users.GET("/me/blocks", blockHandler.GetBlockedUsers)
+				users.GET("/me/mutes", blockHandler.GetMutedUsers)
+				users.GET("/me/privacy", userHandler.GetPrivacySettings)
+				users.PUT("/me/privacy", userHandler.UpdatePrivacySettings)
 				users.GET("/check-username", userHandler.CheckUsername)
+				users.GET("/search", userHandler.SearchUsers)
+				users.GET("/:id", userHandler.GetUserProfile)
+				users.GET("/:id/films", userHandler.GetUserFilms)
+				users.GET("/:id/reviews", userHandler.GetUserReviews)
+				users.GET("/:id/watchlist", userHandler.GetUserWatchlist)
+				users.GET("/:id/compatibility", userHandler.GetTasteCompatibility)
+				users.POST("/:id/follow", followHandler.Follow)
+				users.DELETE("/:id/follow", followHandler.Unfollow)
+				users.POST("/:id/block", blockHandler.Block)
+				users.DELETE("/:id/block", blockHandler.Unblock)
+				users.POST("/:id/mute", blockHandler.Mute)
+				users.DELETE("/:id/mute", blockHandler.Unmute)
+				users.GET("/:id/lists", listHandler.GetUserLists)
+			}
+
+			// Temps réel (SSE)
+			protected.GET("/events", eventHandler.Stream)
+
+			// Notifications
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("", notificationHandler.GetNotifications)
+				notifications.GET("/unread-count", notificationHandler.GetUnreadCount)
+				notifications.POST("/read", notificationHandler.MarkRead)
+				notifications.POST("/read-all", notificationHandler.MarkAllRead)
+				notifications.GET("/preferences", notificationHandler.GetPreferences)
+				notifications.PUT("/preferences", notificationHandler.UpdatePreferences)
 			}
 
+			// Lists
+			lists := protected.Group("/lists")
+			{
+				lists.POST("", listHandler.CreateList)
+				lists.GET("/:id", listHandler.GetList)
+				lists.PUT("/:id", listHandler.UpdateList)
+				lists.DELETE("/:id", listHandler.DeleteList)
+				lists.POST("/:id/entries", listHandler.AddEntry)
+				lists.PUT("/:id/entries/:entry_id", listHandler.UpdateEntry)
+				lists.DELETE("/:id/entries/:entry_id", listHandler.RemoveEntry)
+				lists.PUT("/:id/order", listHandler.ReorderList)
+				lists.GET("/:id/collaborators", listHandler.GetCollaborators)
+				lists.POST("/:id/collaborators", listHandler.InviteCollaborator)
+				lists.PUT("/:id/collaborators/:user_id", listHandler.UpdateCollaborator)
+				lists.DELETE("/:id/collaborators/:user_id", listHandler.RemoveCollaborator)
+				lists.POST("/:id/invitation/accept", listHandler.AcceptInvitation)
+				lists.POST("/:id/invitation/decline", listHandler.DeclineInvitation)
+			}
+
+			// Défis
+			challenges := protected.Group("/challenges")
+			{
+				challenges.GET("", challengeHandler.ListChallenges)
+				challenges.GET("/:id", challengeHandler.GetChallenge)
+				challenges.POST("/:id/join", challengeHandler.JoinChallenge)
+				challenges.DELETE("/:id/join", challengeHandler.LeaveChallenge)
+			}
+
+			// Signalements
+			protected.POST("/reports", reportHandler.CreateReport)
+
+			// Reviews (communauté)
+			protected.GET("/reviews/popular", reviewHandler.GetPopularReviews)
+			protected.PUT("/comments/:id", commentHandler.UpdateComment)
+			protected.DELETE("/comments/:id", commentHandler.DeleteComment)
+
 			// Movies (tracking, rating, review)
 			movies := protected.Group("/movies")
 			{
 				movies.GET("/:tmdb_id/interaction", movieHandler.GetMovieInteraction)
+				movies.GET("/:tmdb_id/reviews", reviewHandler.GetMovieReviews)
+				movies.POST("/:tmdb_id/reviews/:user_id/like", reviewHandler.LikeReview)
+				movies.DELETE("/:tmdb_id/reviews/:user_id/like", reviewHandler.UnlikeReview)
+				movies.GET("/:tmdb_id/reviews/:user_id/revisions", reviewHandler.GetReviewHistory)
+				movies.GET("/:tmdb_id/reviews/:user_id/comments", commentHandler.GetComments)
+				movies.POST("/:tmdb_id/reviews/:user_id/comments", commentHandler.CreateComment)
+				movies.POST("/:tmdb_id/reviews/:user_id/comments/lock", commentHandler.LockComments)
+				movies.DELETE("/:tmdb_id/reviews/:user_id/comments/lock", commentHandler.UnlockComments)
 				movies.POST("/:tmdb_id/track", movieHandler.TrackMovie)
+				movies.PUT("/:tmdb_id/track", movieHandler.UpdateTrack)
+				movies.DELETE("/:tmdb_id/track", movieHandler.DeleteTrack)
 				movies.POST("/:tmdb_id/rate", movieHandler.RateMovie)
+				movies.PUT("/:tmdb_id/rate", movieHandler.UpdateRating)
+				movies.DELETE("/:tmdb_id/rate", movieHandler.DeleteRating)
+				movies.PUT("/:tmdb_id/review", movieHandler.UpdateReview)
+				movies.DELETE("/:tmdb_id/review", movieHandler.DeleteReview)
 				movies.POST("/:tmdb_id/log", movieHandler.LogMovie)
 			}
 		}
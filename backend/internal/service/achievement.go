@@ -0,0 +1,486 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrBadgeNotFound  = errors.New("badge not found")
+	ErrBadgeCodeTaken = errors.New("badge code already taken")
+	ErrInvalidBadge   = errors.New("invalid badge")
+	badgeCodePattern  = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,49}$`)
+)
+
+const badgeBackfillBatch = 500
+
+// badges : règles lues en base et évaluées en tâche de fond après chaque
+// événement du journal ou des critiques (ActivityChanged). un badge obtenu
+// n'est jamais retiré
+type AchievementService struct {
+	badgeRepo  *repository.BadgeRepository
+	watched    *repository.ChallengeRepository // films vus avec leurs attributs
+	userRepo   repository.UserRepository
+	movies     *MovieService
+	notifier   *NotificationService
+	dispatcher *Dispatcher
+	audit      *AuditService
+}
+
+// movies sert à importer les métadonnées manquantes quand une règle porte sur
+// les genres, pays ou réalisateurs
+func NewAchievementService(badgeRepo *repository.BadgeRepository, watched *repository.ChallengeRepository, userRepo repository.UserRepository, movies *MovieService) *AchievementService {
+	return &AchievementService{
+		badgeRepo: badgeRepo,
+		watched:   watched,
+		userRepo:  userRepo,
+		movies:    movies,
+	}
+}
+
+func (s *AchievementService) SetNotificationService(notifier *NotificationService) {
+	s.notifier = notifier
+}
+
+func (s *AchievementService) SetDispatcher(dispatcher *Dispatcher) {
+	s.dispatcher = dispatcher
+}
+
+func (s *AchievementService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
+// ÉVALUATION
+
+// à appeler après chaque événement du journal (film vu, noté, retiré) ou des critiques
+func (s *AchievementService) ActivityChanged(userID uint) {
+	if s == nil {
+		return
+	}
+	s.dispatcher.Dispatch("achievements.evaluate", func() error {
+		return s.evaluate(userID)
+	})
+}
+
+// attribue les badges non encore obtenus dont la règle est satisfaite
+func (s *AchievementService) evaluate(userID uint) error {
+	badges, err := s.badgeRepo.List()
+	if err != nil {
+		return err
+	}
+	earned, err := s.badgeRepo.Earned(userID)
+	if err != nil {
+		return err
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	meter := &badgeMeter{service: s, user: user, syncMetadata: true}
+	for i := range badges {
+		badge := &badges[i]
+		if _, ok := earned[badge.ID]; ok {
+			continue
+		}
+		value, err := meter.value(badge.Rule)
+		if err != nil {
+			return err
+		}
+		if value < badge.Rule.Threshold {
+			continue
+		}
+
+		awarded, err := s.badgeRepo.Award(&model.UserBadge{UserID: userID, BadgeID: badge.ID, AwardedAt: time.Now()})
+		if err != nil {
+			return err
+		}
+		if awarded {
+			s.notifier.Notify([]uint{userID}, nil, model.BadgeEarnedPayload{
+				BadgeID: badge.ID,
+				Code:    badge.Code,
+				Name:    badge.Name,
+				Icon:    badge.Icon,
+			})
+		}
+	}
+	return nil
+}
+
+// réévalue tous les membres actifs, après l'ajout ou la modification d'une règle
+func (s *AchievementService) backfill() {
+	s.dispatcher.Dispatch("achievements.backfill", func() error {
+		var afterID uint
+		for {
+			userIDs, err := s.badgeRepo.ActiveUserIDs(afterID, badgeBackfillBatch)
+			if err != nil {
+				return err
+			}
+			for _, userID := range userIDs {
+				if err := s.evaluate(userID); err != nil {
+					return fmt.Errorf("evaluate user %d: %w", userID, err)
+				}
+			}
+			if len(userIDs) < badgeBackfillBatch {
+				return nil
+			}
+			afterID = userIDs[len(userIDs)-1]
+		}
+	})
+}
+
+// mesure les métriques des règles d'un utilisateur ; chaque donnée n'est
+// chargée qu'une fois, et seulement si une règle en a besoin
+type badgeMeter struct {
+	service      *AchievementService
+	user         *model.User
+	syncMetadata bool // false en lecture : pas d'appel TMDB pour afficher la progression
+
+	movies []repository.ChallengeMovie
+	loaded bool
+	counts map[string]int
+}
+
+func (m *badgeMeter) value(rule model.BadgeRule) (int, error) {
+	switch rule.Metric {
+	case model.BadgeMetricFilms:
+		movies, err := m.watchedMovies(rule.NeedsMetadata())
+		if err != nil {
+			return 0, err
+		}
+		return filmsMetric(rule, movies, m.user.Zone()), nil
+	case model.BadgeMetricStreakDays:
+		movies, err := m.watchedMovies(false)
+		if err != nil {
+			return 0, err
+		}
+		days := make([]time.Time, 0, len(movies))
+		for _, movie := range movies {
+			days = append(days, localDay(movie.WatchedAt, m.user.Zone()))
+		}
+		sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+		return longestStreak(days).Days, nil
+	case model.BadgeMetricReviews:
+		return m.count(rule.Metric, m.service.badgeRepo.CountReviews)
+	case model.BadgeMetricRatings:
+		return m.count(rule.Metric, m.service.badgeRepo.CountRatings)
+	case model.BadgeMetricRewatches:
+		return m.count(rule.Metric, m.service.badgeRepo.CountRewatches)
+	default:
+		return 0, nil
+	}
+}
+
+func (m *badgeMeter) count(metric string, fetch func(userID uint) (int64, error)) (int, error) {
+	if value, ok := m.counts[metric]; ok {
+		return value, nil
+	}
+	value, err := fetch(m.user.ID)
+	if err != nil {
+		return 0, err
+	}
+	if m.counts == nil {
+		m.counts = make(map[string]int)
+	}
+	m.counts[metric] = int(value)
+	return int(value), nil
+}
+
+// les films sont rechargés une fois si une règle a besoin des métadonnées
+func (m *badgeMeter) watchedMovies(needsMetadata bool) ([]repository.ChallengeMovie, error) {
+	needsMetadata = needsMetadata && m.syncMetadata
+	if m.loaded && !needsMetadata {
+		return m.movies, nil
+	}
+	movies, err := watchedWithMetadata(m.service.watched, m.service.movies, m.user.ID, nil, needsMetadata)
+	if err != nil {
+		return nil, err
+	}
+	m.movies, m.loaded = movies, true
+	if needsMetadata {
+		m.syncMetadata = false // déjà importées
+	}
+	return movies, nil
+}
+
+// meilleur groupe (période × valeur de Per) : nombre de films, ou de valeurs
+// distinctes de Distinct, parmi les films retenus par Filter
+func filmsMetric(rule model.BadgeRule, movies []repository.ChallengeMovie, loc *time.Location) int {
+	groups := make(map[string]map[string]bool)
+	for _, movie := range movies {
+		if rule.Filter != nil && !matchesCriteria(*rule.Filter, movie) {
+			continue
+		}
+
+		period := ""
+		switch rule.Period {
+		case model.BadgePeriodMonth:
+			period = movie.WatchedAt.In(loc).Format("2006-01")
+		case model.BadgePeriodYear:
+			period = movie.WatchedAt.In(loc).Format("2006")
+		}
+		per := []string{""}
+		if rule.Per != "" {
+			per = dimensionValues(movie, rule.Per)
+		}
+		values := []string{strconv.FormatUint(uint64(movie.MovieID), 10)}
+		if rule.Distinct != "" {
+			values = dimensionValues(movie, rule.Distinct)
+		}
+
+		for _, p := range per {
+			key := period + "|" + p
+			if groups[key] == nil {
+				groups[key] = make(map[string]bool)
+			}
+			for _, v := range values {
+				groups[key][v] = true
+			}
+		}
+	}
+
+	best := 0
+	for _, values := range groups {
+		if len(values) > best {
+			best = len(values)
+		}
+	}
+	return best
+}
+
+func dimensionValues(movie repository.ChallengeMovie, dimension string) []string {
+	switch dimension {
+	case model.BadgeDimensionCountries:
+		return movie.Countries
+	case model.BadgeDimensionGenres:
+		return intKeys(movie.Genres)
+	case model.BadgeDimensionDirectors:
+		return intKeys(movie.Directors)
+	case model.BadgeDimensionDecades:
+		if movie.ReleaseYear == 0 {
+			return nil
+		}
+		return []string{strconv.Itoa(movie.ReleaseYear / 10 * 10)}
+	}
+	return nil
+}
+
+// MEMBRES
+
+// badges obtenus (plus récents d'abord) et à obtenir, avec la progression
+func (s *AchievementService) GetUserBadges(userID uint) (*dto.UserBadgesResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	badges, err := s.badgeRepo.List()
+	if err != nil {
+		return nil, errors.New("failed to fetch badges")
+	}
+	earned, err := s.badgeRepo.Earned(userID)
+	if err != nil {
+		return nil, errors.New("failed to fetch badges")
+	}
+
+	response := &dto.UserBadgesResponse{Earned: []dto.UserBadgeResponse{}, Locked: []dto.UserBadgeResponse{}}
+	meter := &badgeMeter{service: s, user: user}
+	for i := range badges {
+		badge := &badges[i]
+		item := dto.UserBadgeResponse{
+			ID:          badge.ID,
+			Code:        badge.Code,
+			Name:        badge.Name,
+			Description: badge.Description,
+			Icon:        badge.Icon,
+			Threshold:   badge.Rule.Threshold,
+		}
+		if award, ok := earned[badge.ID]; ok {
+			awardedAt := award.AwardedAt
+			item.AwardedAt = &awardedAt
+			item.Progress = item.Threshold
+			response.Earned = append(response.Earned, item)
+			continue
+		}
+
+		value, err := meter.value(badge.Rule)
+		if err != nil {
+			return nil, errors.New("failed to compute badge progress")
+		}
+		item.Progress = min(value, item.Threshold)
+		response.Locked = append(response.Locked, item)
+	}
+
+	sort.SliceStable(response.Earned, func(i, j int) bool {
+		return response.Earned[i].AwardedAt.After(*response.Earned[j].AwardedAt)
+	})
+	return response, nil
+}
+
+// ADMINISTRATION
+
+func (s *AchievementService) ListBadges() ([]dto.BadgeResponse, error) {
+	badges, err := s.badgeRepo.List()
+	if err != nil {
+		return nil, errors.New("failed to fetch badges")
+	}
+	responses := make([]dto.BadgeResponse, 0, len(badges))
+	for i := range badges {
+		responses = append(responses, dto.ToBadgeResponse(&badges[i]))
+	}
+	return responses, nil
+}
+
+// un nouveau badge est attribué en arrière-plan aux membres qui le méritent déjà
+func (s *AchievementService) CreateBadge(adminID uint, input dto.CreateBadgeRequest, meta dto.RequestMeta) (*dto.BadgeResponse, error) {
+	if !badgeCodePattern.MatchString(input.Code) {
+		return nil, fmt.Errorf("%w: code must be lowercase letters, digits or '-'", ErrInvalidBadge)
+	}
+	rule := input.Rule
+	if err := validateBadgeRule(&rule); err != nil {
+		return nil, err
+	}
+	exists, err := s.badgeRepo.CodeExists(input.Code)
+	if err != nil {
+		return nil, errors.New("failed to create badge")
+	}
+	if exists {
+		return nil, ErrBadgeCodeTaken
+	}
+
+	badge := &model.Badge{
+		Code:        input.Code,
+		Name:        input.Name,
+		Description: input.Description,
+		Icon:        input.Icon,
+		Rule:        rule,
+		Position:    input.Position,
+	}
+	if err := s.badgeRepo.Create(badge); err != nil {
+		return nil, errors.New("failed to create badge")
+	}
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditAdminBadgeCreated,
+		ActorID:  uintPtr(adminID),
+		Metadata: map[string]interface{}{"badge": badge.Code, "rule": badge.Rule},
+	})
+	s.backfill()
+
+	response := dto.ToBadgeResponse(badge)
+	return &response, nil
+}
+
+// une règle modifiée est réévaluée pour tous ; les badges déjà obtenus sont conservés
+func (s *AchievementService) UpdateBadge(adminID, badgeID uint, input dto.UpdateBadgeRequest, meta dto.RequestMeta) (*dto.BadgeResponse, error) {
+	badge, err := s.badgeRepo.GetByID(badgeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBadgeNotFound
+		}
+		return nil, errors.New("failed to fetch badge")
+	}
+
+	if input.Name != nil {
+		badge.Name = *input.Name
+	}
+	if input.Description != nil {
+		badge.Description = *input.Description
+	}
+	if input.Icon != nil {
+		badge.Icon = *input.Icon
+	}
+	if input.Position != nil {
+		badge.Position = *input.Position
+	}
+	if input.Rule != nil {
+		rule := *input.Rule
+		if err := validateBadgeRule(&rule); err != nil {
+			return nil, err
+		}
+		badge.Rule = rule
+	}
+
+	if err := s.badgeRepo.Update(badge); err != nil {
+		return nil, errors.New("failed to update badge")
+	}
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditAdminBadgeUpdated,
+		ActorID:  uintPtr(adminID),
+		Metadata: map[string]interface{}{"badge": badge.Code, "rule": badge.Rule},
+	})
+	if input.Rule != nil {
+		s.backfill()
+	}
+
+	response := dto.ToBadgeResponse(badge)
+	return &response, nil
+}
+
+// les badges déjà obtenus disparaissent avec la définition
+func (s *AchievementService) DeleteBadge(adminID, badgeID uint, meta dto.RequestMeta) error {
+	if err := s.badgeRepo.Delete(badgeID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrBadgeNotFound
+		}
+		return errors.New("failed to delete badge")
+	}
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditAdminBadgeDeleted,
+		ActorID:  uintPtr(adminID),
+		Metadata: map[string]interface{}{"badge_id": badgeID},
+	})
+	return nil
+}
+
+func validateBadgeRule(rule *model.BadgeRule) error {
+	if !containsString(model.BadgeMetrics, rule.Metric) {
+		return fmt.Errorf("%w: unknown metric %q", ErrInvalidBadge, rule.Metric)
+	}
+	if rule.Threshold < 1 {
+		return fmt.Errorf("%w: threshold must be at least 1", ErrInvalidBadge)
+	}
+	if rule.Metric != model.BadgeMetricFilms {
+		if rule.Filter != nil || rule.Distinct != "" || rule.Per != "" || rule.Period != "" {
+			return fmt.Errorf("%w: filter, distinct, per and period only apply to the films metric", ErrInvalidBadge)
+		}
+		return nil
+	}
+
+	for _, dimension := range []string{rule.Distinct, rule.Per} {
+		if dimension != "" && !containsString(model.BadgeDimensions, dimension) {
+			return fmt.Errorf("%w: unknown dimension %q", ErrInvalidBadge, dimension)
+		}
+	}
+	if rule.Distinct != "" && rule.Distinct == rule.Per {
+		return fmt.Errorf("%w: distinct and per must differ", ErrInvalidBadge)
+	}
+	if rule.Period != "" && rule.Period != model.BadgePeriodMonth && rule.Period != model.BadgePeriodYear {
+		return fmt.Errorf("%w: unknown period %q", ErrInvalidBadge, rule.Period)
+	}
+	if rule.Filter != nil {
+		if err := normalizeCriteria(rule.Filter); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidBadge, err)
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
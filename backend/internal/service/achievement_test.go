@@ -0,0 +1,147 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+)
+
+func TestAchievementService_Badges(t *testing.T) {
+	mann := dto.TMDBCrewMember{ID: 638, Name: "Michael Mann"}
+	catalog := map[int]dto.TMDBMovieDetails{
+		300: tmdbDetails(300, "Thief", "1981-03-27", 120, nil, "US", nil, mann),
+		301: tmdbDetails(301, "Manhunter", "1986-08-15", 120, nil, "US", nil, mann),
+		302: tmdbDetails(302, "Heat", "1995-12-15", 170, nil, "US", nil, mann),
+		303: tmdbDetails(303, "Amélie", "2001-04-25", 122, nil, "FR", nil, dto.TMDBCrewMember{ID: 2419, Name: "Jean-Pierre Jeunet"}),
+	}
+	db, tmdbService := setupCatalogTest(t, catalog, &model.Badge{}, &model.UserBadge{}, &model.Notification{}, &model.NotificationPreference{})
+	for _, badge := range model.DefaultBadges() {
+		db.Create(&badge)
+	}
+	movieRepo := repository.NewMovieRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	movieService := NewMovieService(movieRepo, tmdbService)
+	// dispatcher nil : les badges sont évalués immédiatement
+	achievementService := NewAchievementService(repository.NewBadgeRepository(db), repository.NewChallengeRepository(db), userRepo, movieService)
+	achievementService.SetNotificationService(NewNotificationService(repository.NewNotificationRepository(db), repository.NewFollowRepository(db), nil))
+	movieService.SetAchievementService(achievementService)
+
+	admin := &model.User{Username: "admin", Email: "admin@example.com"}
+	user := &model.User{Username: "cinephile", Email: "cinephile@example.com"}
+	db.Create(admin)
+	db.Create(user)
+
+	badges := func() *dto.UserBadgesResponse {
+		t.Helper()
+		response, err := achievementService.GetUserBadges(user.ID)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		return response
+	}
+	find := func(items []dto.UserBadgeResponse, code string) *dto.UserBadgeResponse {
+		for i := range items {
+			if items[i].Code == code {
+				return &items[i]
+			}
+		}
+		return nil
+	}
+
+	if response := badges(); len(response.Earned) != 0 || len(response.Locked) != 4 {
+		t.Fatalf("expected 4 locked badges, got %+v", response)
+	}
+
+	// première critique
+	review := "A heist film about loneliness."
+	if err := movieService.LogMovie(user.ID, 302, dto.LogMovieRequest{ReviewText: &review}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if earned := find(badges().Earned, "first-review"); earned == nil || earned.AwardedAt == nil || earned.Progress != 1 {
+		t.Errorf("expected first-review earned, got %+v", earned)
+	}
+	var notifications int64
+	db.Model(&model.Notification{}).Where("user_id = ? AND type = ?", user.ID, model.NotificationBadgeEarned).Count(&notifications)
+	if notifications != 1 {
+		t.Errorf("expected 1 badge notification, got %d", notifications)
+	}
+
+	// deux films de Michael Mann en mars, un autre film en avril
+	month := time.Date(2026, 3, 10, 20, 0, 0, 0, user.Zone())
+	for i, tmdbID := range []int{300, 301} {
+		watchedAt := month.AddDate(0, 0, i)
+		if err := movieService.LogMovie(user.ID, tmdbID, dto.LogMovieRequest{WatchedDate: &watchedAt}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	nextMonth := month.AddDate(0, 1, 0)
+	if err := movieService.LogMovie(user.ID, 303, dto.LogMovieRequest{WatchedDate: &nextMonth}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	response := badges()
+	// Heat vu aujourd'hui : 2 films de Mann en mars au mieux
+	if locked := find(response.Locked, "director-month-5"); locked == nil || locked.Progress != 2 || locked.Threshold != 5 {
+		t.Errorf("expected director-month-5 at 2/5, got %+v", locked)
+	}
+	if locked := find(response.Locked, "decades-10"); locked == nil || locked.Progress != 3 {
+		t.Errorf("expected 3 decades, got %+v", locked)
+	}
+
+	// règle invalide
+	invalid := []model.BadgeRule{
+		{Metric: "likes", Threshold: 1},
+		{Metric: model.BadgeMetricFilms, Threshold: 0},
+		{Metric: model.BadgeMetricReviews, Threshold: 1, Per: model.BadgeDimensionDirectors},
+		{Metric: model.BadgeMetricFilms, Threshold: 1, Distinct: "actors"},
+		{Metric: model.BadgeMetricFilms, Threshold: 1, Distinct: model.BadgeDimensionGenres, Per: model.BadgeDimensionGenres},
+		{Metric: model.BadgeMetricFilms, Threshold: 1, Period: "week"},
+	}
+	for _, rule := range invalid {
+		if _, err := achievementService.CreateBadge(admin.ID, dto.CreateBadgeRequest{Code: "invalid", Name: "Invalid", Rule: rule}, dto.RequestMeta{}); !errors.Is(err, ErrInvalidBadge) {
+			t.Errorf("expected ErrInvalidBadge for %+v, got %v", rule, err)
+		}
+	}
+	if _, err := achievementService.CreateBadge(admin.ID, dto.CreateBadgeRequest{Code: "Films 100", Name: "Films", Rule: model.BadgeRule{Metric: model.BadgeMetricFilms, Threshold: 1}}, dto.RequestMeta{}); !errors.Is(err, ErrInvalidBadge) {
+		t.Errorf("expected ErrInvalidBadge for code, got %v", err)
+	}
+	if _, err := achievementService.CreateBadge(admin.ID, dto.CreateBadgeRequest{Code: "films-100", Name: "Films", Rule: model.BadgeRule{Metric: model.BadgeMetricFilms, Threshold: 1}}, dto.RequestMeta{}); !errors.Is(err, ErrBadgeCodeTaken) {
+		t.Errorf("expected ErrBadgeCodeTaken, got %v", err)
+	}
+
+	// un badge créé par un administrateur est attribué à ceux qui le méritent déjà
+	created, err := achievementService.CreateBadge(admin.ID, dto.CreateBadgeRequest{
+		Code: "director-month-2",
+		Name: "Double feature",
+		Rule: model.BadgeRule{Metric: model.BadgeMetricFilms, Per: model.BadgeDimensionDirectors, Period: model.BadgePeriodMonth, Threshold: 2},
+	}, dto.RequestMeta{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if earned := find(badges().Earned, created.Code); earned == nil {
+		t.Errorf("expected %s earned by backfill", created.Code)
+	}
+
+	// relever le seuil ne retire pas un badge obtenu
+	threshold := model.BadgeRule{Metric: model.BadgeMetricFilms, Per: model.BadgeDimensionDirectors, Period: model.BadgePeriodMonth, Threshold: 3}
+	if _, err := achievementService.UpdateBadge(admin.ID, created.ID, dto.UpdateBadgeRequest{Rule: &threshold}, dto.RequestMeta{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if earned := find(badges().Earned, created.Code); earned == nil || earned.Threshold != 3 {
+		t.Errorf("expected %s kept after rule change, got %+v", created.Code, earned)
+	}
+
+	if err := achievementService.DeleteBadge(admin.ID, created.ID, dto.RequestMeta{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if find(badges().Earned, created.Code) != nil {
+		t.Errorf("expected deleted badge hidden")
+	}
+	if err := achievementService.DeleteBadge(admin.ID, created.ID, dto.RequestMeta{}); !errors.Is(err, ErrBadgeNotFound) {
+		t.Errorf("expected ErrBadgeNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+type AuditService struct {
+	auditRepo *repository.AuditRepository
+}
+
+func NewAuditService(auditRepo *repository.AuditRepository) *AuditService {
+	return &AuditService{auditRepo: auditRepo}
+}
+
+// enregistre un événement ; un échec est loggé mais ne bloque jamais la requête.
+// un *AuditService nil (tests, services non câblés) n'enregistre rien
+func (s *AuditService) Record(meta dto.RequestMeta, event model.AuditEvent) {
+	if s == nil {
+		return
+	}
+
+	event.IP = meta.IP
+	event.UserAgent = meta.UserAgent
+	if len(event.UserAgent) > 500 {
+		event.UserAgent = event.UserAgent[:500]
+	}
+
+	if err := s.auditRepo.Create(&event); err != nil {
+		utils.Log.Error("Failed to record audit event",
+			zap.String("action", event.Action),
+			zap.Error(err),
+		)
+	}
+}
+
+// (admin) liste filtrable de tous les événements
+func (s *AuditService) ListEvents(filter repository.AuditFilter, page, limit int) (*dto.PaginatedAuditEventsResponse, error) {
+	events, total, err := s.auditRepo.List(filter, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to fetch audit events")
+	}
+	return toPaginatedAuditEvents(events, total, page, limit), nil
+}
+
+// activité de sécurité récente du user
+func (s *AuditService) ListSecurityActivity(userID uint, page, limit int) (*dto.PaginatedAuditEventsResponse, error) {
+	events, total, err := s.auditRepo.ListForUser(userID, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to fetch security activity")
+	}
+	return toPaginatedAuditEvents(events, total, page, limit), nil
+}
+
+// dernière connexion réussie (mot de passe ou lien magique), nil si inconnue
+func (s *AuditService) LastLogin(userID uint) *time.Time {
+	if s == nil {
+		return nil
+	}
+	at, err := s.auditRepo.LastByActor(userID, model.AuditLogin, model.AuditMagicLinkLogin)
+	if err != nil {
+		return nil
+	}
+	return at
+}
+
+func toPaginatedAuditEvents(events []model.AuditEvent, total int64, page, limit int) *dto.PaginatedAuditEventsResponse {
+	responses := make([]dto.AuditEventResponse, 0, len(events))
+	for i := range events {
+		responses = append(responses, dto.ToAuditEventResponse(&events[i]))
+	}
+
+	return &dto.PaginatedAuditEventsResponse{
+		Events:     responses,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}
+}
+
+func uintPtr(v uint) *uint {
+	return &v
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/Nowap83/FrameRate/backend/internal/dto"
@@ -15,11 +16,27 @@ import (
 
 type EmailSender interface {
 	SendVerificationEmail(to, username, token string) error
+	SendMagicLinkEmail(to, username, token string) error
 }
 
+var ErrAccountSuspended = errors.New("account suspended")
+
+const (
+	magicLinkTTL          = 15 * time.Minute
+	magicLinkWindow       = time.Hour
+	magicLinkMaxPerWindow = 3
+)
+
+// message identique que le compte existe ou non (anti-énumération)
+const magicLinkSentMessage = "If an account exists for this email, a login link has been sent."
+
 type AuthService struct {
 	userRepo     repository.UserRepository
 	emailService EmailSender
+	audit        *AuditService
+	dispatcher   *Dispatcher
+	notifier     *NotificationService
+	policy       *ContentPolicy
 }
 
 func NewAuthService(userRepo repository.UserRepository, emailService EmailSender) *AuthService {
@@ -29,6 +46,23 @@ func NewAuthService(userRepo repository.UserRepository, emailService EmailSender
 	}
 }
 
+func (s *AuthService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
+// les emails partent via le dispatcher ; sans lui ils sont envoyés pendant la requête
+func (s *AuthService) SetDispatcher(dispatcher *Dispatcher) {
+	s.dispatcher = dispatcher
+}
+
+func (s *AuthService) SetNotificationService(notifier *NotificationService) {
+	s.notifier = notifier
+}
+
+func (s *AuthService) SetContentPolicy(policy *ContentPolicy) {
+	s.policy = policy
+}
+
 //
 // REGISTER
 //
@@ -50,6 +84,12 @@ func (s *AuthService) Register(input dto.RegisterRequest) (*dto.RegisterResponse
 		return nil, errors.New("database error")
 	}
 
+	// noms réservés ou interdits
+	verdict, err := s.policy.Check(ContentFieldUsername, input.Username)
+	if err != nil {
+		return nil, err
+	}
+
 	// hashage password
 	hashedPassword, err := utils.HashPassword(input.Password)
 	if err != nil {
@@ -72,32 +112,29 @@ func (s *AuthService) Register(input dto.RegisterRequest) (*dto.RegisterResponse
 		VerificationToken: &verificationToken,
 		TokenExpiresAt:    &expiresAt,
 		IsVerified:        false,
-		IsAdmin:           false,
+		Role:              model.RoleUser,
 	}
 
 	if err := s.userRepo.Create(&user); err != nil {
 		return nil, errors.New("failed to create user")
 	}
+	s.policy.Flag(verdict, ContentTarget{Type: model.ReportTargetProfile, UserID: user.ID, Snapshot: user.Username})
 
-	// go routine envoi email
-	go func() {
+	// envoi email en arrière-plan
+	s.dispatcher.Dispatch("verification_email", func() error {
 		if err := s.emailService.SendVerificationEmail(
 			user.Email,
 			user.Username,
 			verificationToken,
 		); err != nil {
-			utils.Log.Error("Failed to send verification email",
-				zap.Uint("user_id", user.ID),
-				zap.String("email", user.Email),
-				zap.Error(err),
-			)
-		} else {
-			utils.Log.Info("Verification email sent",
-				zap.Uint("user_id", user.ID),
-				zap.String("email", user.Email),
-			)
+			return fmt.Errorf("send verification email to user %d: %w", user.ID, err)
 		}
-	}()
+		utils.Log.Info("Verification email sent",
+			zap.Uint("user_id", user.ID),
+			zap.String("email", user.Email),
+		)
+		return nil
+	})
 
 	return &dto.RegisterResponse{
 		Message: "Registration successful! Please check your email to verify your account.",
@@ -108,11 +145,15 @@ func (s *AuthService) Register(input dto.RegisterRequest) (*dto.RegisterResponse
 // LOGIN
 //
 
-func (s *AuthService) Login(input dto.LoginRequest) (*dto.LoginResponse, error) {
+func (s *AuthService) Login(input dto.LoginRequest, meta dto.RequestMeta) (*dto.LoginResponse, error) {
 	// cherche user par mail ou username
 	user, err := s.userRepo.GetByEmailOrUsername(input.Login)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.audit.Record(meta, model.AuditEvent{
+				Action:   model.AuditLoginFailed,
+				Metadata: map[string]interface{}{"login": input.Login, "reason": "unknown_user"},
+			})
 			return nil, errors.New("invalid credentials")
 		}
 		return nil, errors.New("database error")
@@ -120,19 +161,39 @@ func (s *AuthService) Login(input dto.LoginRequest) (*dto.LoginResponse, error)
 
 	// verif du mdp
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.Password)); err != nil {
+		s.audit.Record(meta, model.AuditEvent{
+			Action:   model.AuditLoginFailed,
+			TargetID: uintPtr(user.ID),
+			Metadata: map[string]interface{}{"reason": "invalid_password"},
+		})
 		return nil, errors.New("invalid credentials")
 	}
 
 	if !user.IsVerified {
+		s.audit.Record(meta, model.AuditEvent{
+			Action:   model.AuditLoginFailed,
+			TargetID: uintPtr(user.ID),
+			Metadata: map[string]interface{}{"reason": "email_not_verified"},
+		})
 		return nil, errors.New("email not verified. please check your inbox")
 	}
 
+	if err := s.checkNotSuspended(user, meta); err != nil {
+		return nil, err
+	}
+
 	// genere jwt
 	token, err := utils.GenerateToken(user.ID)
 	if err != nil {
 		return nil, errors.New("failed to generate token")
 	}
 
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditLogin,
+		ActorID:  uintPtr(user.ID),
+		TargetID: uintPtr(user.ID),
+	})
+
 	return dto.NewLoginResponse(token, user), nil
 }
 
@@ -175,6 +236,8 @@ func (s *AuthService) VerifyEmail(token string) (*dto.VerifyEmailResponse, error
 		return nil, errors.New("failed to verify email")
 	}
 
+	s.notifier.Notify([]uint{user.ID}, nil, model.EmailVerifiedPayload{Email: user.Email})
+
 	// gen jwt
 	jwtToken, err := utils.GenerateToken(user.ID)
 	if err != nil {
@@ -187,3 +250,107 @@ func (s *AuthService) VerifyEmail(token string) (*dto.VerifyEmailResponse, error
 		"Email verified successfully! You are now logged in.",
 	), nil
 }
+
+//
+// MAGIC LINK
+//
+
+func (s *AuthService) RequestMagicLink(input dto.MagicLinkRequest, meta dto.RequestMeta) (*dto.MessageResponse, error) {
+	response := &dto.MessageResponse{Message: magicLinkSentMessage}
+
+	user, err := s.userRepo.GetByEmail(input.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response, nil
+		}
+		return nil, errors.New("database error")
+	}
+
+	// comptes non vérifiés : même réponse, aucun envoi
+	if !user.IsVerified {
+		return response, nil
+	}
+
+	// limite par email, silencieuse pour ne pas révéler l'existence du compte
+	sent, err := s.userRepo.CountMagicLinkTokensSince(user.ID, time.Now().Add(-magicLinkWindow))
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	if sent >= magicLinkMaxPerWindow {
+		utils.Log.Warn("Magic link rate limit reached", zap.Uint("user_id", user.ID))
+		return response, nil
+	}
+
+	token, err := utils.GenerateVerificationToken()
+	if err != nil {
+		return nil, errors.New("failed to generate login link")
+	}
+
+	if err := s.userRepo.CreateMagicLinkToken(&model.MagicLinkToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(magicLinkTTL),
+	}); err != nil {
+		return nil, errors.New("failed to generate login link")
+	}
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditMagicLinkRequested,
+		TargetID: uintPtr(user.ID),
+	})
+
+	// envoi email en arrière-plan
+	s.dispatcher.Dispatch("magic_link_email", func() error {
+		if err := s.emailService.SendMagicLinkEmail(user.Email, user.Username, token); err != nil {
+			return fmt.Errorf("send magic link email to user %d: %w", user.ID, err)
+		}
+		return nil
+	})
+
+	return response, nil
+}
+
+func (s *AuthService) LoginWithMagicLink(token string, meta dto.RequestMeta) (*dto.LoginResponse, error) {
+	user, err := s.userRepo.ConsumeMagicLinkToken(utils.HashToken(token), time.Now())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.audit.Record(meta, model.AuditEvent{
+				Action:   model.AuditLoginFailed,
+				Metadata: map[string]interface{}{"reason": "invalid_magic_link"},
+			})
+			return nil, errors.New("invalid or expired login link")
+		}
+		return nil, errors.New("database error")
+	}
+
+	if err := s.checkNotSuspended(user, meta); err != nil {
+		return nil, err
+	}
+
+	// genere jwt
+	jwtToken, err := utils.GenerateToken(user.ID)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditMagicLinkLogin,
+		ActorID:  uintPtr(user.ID),
+		TargetID: uintPtr(user.ID),
+	})
+
+	return dto.NewLoginResponse(jwtToken, user), nil
+}
+
+// refuse la connexion d'un compte suspendu par un admin
+func (s *AuthService) checkNotSuspended(user *model.User, meta dto.RequestMeta) error {
+	if !user.IsSuspended(time.Now()) {
+		return nil
+	}
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditLoginSuspended,
+		TargetID: uintPtr(user.ID),
+	})
+	return ErrAccountSuspended
+}
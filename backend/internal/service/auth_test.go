@@ -7,6 +7,7 @@ import (
 
 	"github.com/Nowap83/FrameRate/backend/internal/dto"
 	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
 	"github.com/Nowap83/FrameRate/backend/internal/utils"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
@@ -20,14 +21,21 @@ type MockUserRepository struct {
 
 	CreateFn                 func(user *model.User) error
 	GetByIDFn                func(id uint) (*model.User, error)
-	GetAllUsersFn            func(page, limit int) ([]*model.User, int64, error)
+	GetAllUsersFn            func(filter repository.UserFilter, page, limit int) ([]*model.User, int64, error)
 	GetByEmailOrUsernameFn   func(login string) (*model.User, error)
 	GetByEmailFn             func(email string) (*model.User, error)
 	GetByUsernameFn          func(username string) (*model.User, error)
+	SearchDiscoverableFn     func(viewerID uint, query string, limit int) ([]*model.User, error)
 	GetByVerificationTokenFn func(token string) (*model.User, error)
 	UpdateFn                 func(user *model.User) error
 	UpdateFieldsFn           func(id uint, updates map[string]interface{}) error
 	DeleteFn                 func(id uint) error
+	GetByIDUnscopedFn        func(id uint) (*model.User, error)
+	RestoreFn                func(id uint, username, email string) error
+
+	CreateMagicLinkTokenFn      func(token *model.MagicLinkToken) error
+	CountMagicLinkTokensSinceFn func(userID uint, since time.Time) (int64, error)
+	ConsumeMagicLinkTokenFn     func(tokenHash string, now time.Time) (*model.User, error)
 }
 
 func (m *MockUserRepository) Create(user *model.User) error {
@@ -42,9 +50,9 @@ func (m *MockUserRepository) GetByID(id uint) (*model.User, error) {
 	}
 	return m.User, m.Err
 }
-func (m *MockUserRepository) GetAllUsers(page, limit int) ([]*model.User, int64, error) {
+func (m *MockUserRepository) GetAllUsers(filter repository.UserFilter, page, limit int) ([]*model.User, int64, error) {
 	if m.GetAllUsersFn != nil {
-		return m.GetAllUsersFn(page, limit)
+		return m.GetAllUsersFn(filter, page, limit)
 	}
 	return nil, 0, m.Err
 }
@@ -66,6 +74,12 @@ func (m *MockUserRepository) GetByUsername(username string) (*model.User, error)
 	}
 	return m.User, m.Err
 }
+func (m *MockUserRepository) SearchDiscoverable(viewerID uint, query string, limit int) ([]*model.User, error) {
+	if m.SearchDiscoverableFn != nil {
+		return m.SearchDiscoverableFn(viewerID, query, limit)
+	}
+	return nil, m.Err
+}
 func (m *MockUserRepository) GetByVerificationToken(token string) (*model.User, error) {
 	if m.GetByVerificationTokenFn != nil {
 		return m.GetByVerificationTokenFn(token)
@@ -91,9 +105,41 @@ func (m *MockUserRepository) Delete(id uint) error {
 	return m.Err
 }
 
+func (m *MockUserRepository) CreateMagicLinkToken(token *model.MagicLinkToken) error {
+	if m.CreateMagicLinkTokenFn != nil {
+		return m.CreateMagicLinkTokenFn(token)
+	}
+	return m.Err
+}
+func (m *MockUserRepository) CountMagicLinkTokensSince(userID uint, since time.Time) (int64, error) {
+	if m.CountMagicLinkTokensSinceFn != nil {
+		return m.CountMagicLinkTokensSinceFn(userID, since)
+	}
+	return 0, m.Err
+}
+func (m *MockUserRepository) ConsumeMagicLinkToken(tokenHash string, now time.Time) (*model.User, error) {
+	if m.ConsumeMagicLinkTokenFn != nil {
+		return m.ConsumeMagicLinkTokenFn(tokenHash, now)
+	}
+	return m.User, m.Err
+}
+func (m *MockUserRepository) GetByIDUnscoped(id uint) (*model.User, error) {
+	if m.GetByIDUnscopedFn != nil {
+		return m.GetByIDUnscopedFn(id)
+	}
+	return m.User, m.Err
+}
+func (m *MockUserRepository) Restore(id uint, username, email string) error {
+	if m.RestoreFn != nil {
+		return m.RestoreFn(id, username, email)
+	}
+	return m.Err
+}
+
 // MockEmailSender
 type MockEmailSender struct {
 	SendVerificationEmailFn func(to, username, token string) error
+	SendMagicLinkEmailFn    func(to, username, token string) error
 	Sent                    bool
 }
 
@@ -105,6 +151,14 @@ func (m *MockEmailSender) SendVerificationEmail(to, username, token string) erro
 	return nil
 }
 
+func (m *MockEmailSender) SendMagicLinkEmail(to, username, token string) error {
+	m.Sent = true
+	if m.SendMagicLinkEmailFn != nil {
+		return m.SendMagicLinkEmailFn(to, username, token)
+	}
+	return nil
+}
+
 func TestAuthService_Register_Success(t *testing.T) {
 	utils.Log = zap.NewNop()
 	userRepo := &MockUserRepository{
@@ -192,7 +246,7 @@ func TestAuthService_Login_Success(t *testing.T) {
 		Password: "password123",
 	}
 
-	resp, err := authService.Login(req)
+	resp, err := authService.Login(req, dto.RequestMeta{})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -222,12 +276,43 @@ func TestAuthService_Login_NotVerified(t *testing.T) {
 		Password: "password123",
 	}
 
-	_, err := authService.Login(req)
+	_, err := authService.Login(req, dto.RequestMeta{})
 	if err == nil || err.Error() != "email not verified. please check your inbox" {
 		t.Fatalf("expected error 'email not verified...', got %v", err)
 	}
 }
 
+func TestAuthService_Login_Suspended(t *testing.T) {
+	utils.Log = zap.NewNop()
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	suspendedAt := time.Now().Add(-time.Hour)
+	expired := time.Now().Add(-time.Minute)
+
+	user := &model.User{
+		ID:           1,
+		Username:     "testuser",
+		Email:        "testuser@example.com",
+		PasswordHash: string(hashedPassword),
+		IsVerified:   true,
+		SuspendedAt:  &suspendedAt,
+	}
+	userRepo := &MockUserRepository{
+		GetByEmailOrUsernameFn: func(login string) (*model.User, error) { return user, nil },
+	}
+	authService := NewAuthService(userRepo, &MockEmailSender{})
+	req := dto.LoginRequest{Login: "testuser", Password: "password123"}
+
+	if _, err := authService.Login(req, dto.RequestMeta{}); err != ErrAccountSuspended {
+		t.Fatalf("expected ErrAccountSuspended, got %v", err)
+	}
+
+	// une suspension arrivée à échéance ne bloque plus
+	user.SuspendedUntil = &expired
+	if _, err := authService.Login(req, dto.RequestMeta{}); err != nil {
+		t.Fatalf("expected expired suspension to allow login, got %v", err)
+	}
+}
+
 func TestAuthService_VerifyEmail_Success(t *testing.T) {
 	utils.Log = zap.NewNop()
 	expires := time.Now().Add(1 * time.Hour)
@@ -275,3 +360,123 @@ func TestAuthService_VerifyEmail_Expired(t *testing.T) {
 		t.Fatalf("expected error for expired token, got %v", err)
 	}
 }
+
+func TestAuthService_RequestMagicLink(t *testing.T) {
+	utils.Log = zap.NewNop()
+
+	t.Run("Unknown email", func(t *testing.T) {
+		userRepo := &MockUserRepository{
+			GetByEmailFn: func(email string) (*model.User, error) {
+				return nil, gorm.ErrRecordNotFound
+			},
+		}
+		emailSender := &MockEmailSender{}
+		authService := NewAuthService(userRepo, emailSender)
+
+		resp, err := authService.RequestMagicLink(dto.MagicLinkRequest{Email: "nobody@example.com"}, dto.RequestMeta{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if resp.Message != magicLinkSentMessage {
+			t.Errorf("expected generic message, got %s", resp.Message)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if emailSender.Sent {
+			t.Errorf("expected no email for unknown address")
+		}
+	})
+
+	t.Run("Verified user", func(t *testing.T) {
+		var stored *model.MagicLinkToken
+		userRepo := &MockUserRepository{
+			GetByEmailFn: func(email string) (*model.User, error) {
+				return &model.User{ID: 1, Email: email, IsVerified: true}, nil
+			},
+			CreateMagicLinkTokenFn: func(token *model.MagicLinkToken) error {
+				stored = token
+				return nil
+			},
+		}
+		sentToken := make(chan string, 1)
+		emailSender := &MockEmailSender{
+			SendMagicLinkEmailFn: func(to, username, token string) error {
+				sentToken <- token
+				return nil
+			},
+		}
+		authService := NewAuthService(userRepo, emailSender)
+
+		resp, err := authService.RequestMagicLink(dto.MagicLinkRequest{Email: "user@example.com"}, dto.RequestMeta{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if resp.Message != magicLinkSentMessage {
+			t.Errorf("expected generic message, got %s", resp.Message)
+		}
+
+		select {
+		case token := <-sentToken:
+			if stored == nil || stored.TokenHash != utils.HashToken(token) {
+				t.Errorf("expected only the token hash to be stored")
+			}
+			if stored.TokenHash == token {
+				t.Errorf("expected plaintext token not to be stored")
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected magic link email to be sent")
+		}
+	})
+
+	t.Run("Rate limited", func(t *testing.T) {
+		userRepo := &MockUserRepository{
+			GetByEmailFn: func(email string) (*model.User, error) {
+				return &model.User{ID: 1, Email: email, IsVerified: true}, nil
+			},
+			CountMagicLinkTokensSinceFn: func(userID uint, since time.Time) (int64, error) {
+				return magicLinkMaxPerWindow, nil
+			},
+		}
+		emailSender := &MockEmailSender{}
+		authService := NewAuthService(userRepo, emailSender)
+
+		resp, err := authService.RequestMagicLink(dto.MagicLinkRequest{Email: "user@example.com"}, dto.RequestMeta{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if resp.Message != magicLinkSentMessage {
+			t.Errorf("expected generic message, got %s", resp.Message)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if emailSender.Sent {
+			t.Errorf("expected no email once the limit is reached")
+		}
+	})
+}
+
+func TestAuthService_LoginWithMagicLink(t *testing.T) {
+	utils.Log = zap.NewNop()
+	os.Setenv("JWT_SECRET", "testsecret")
+
+	userRepo := &MockUserRepository{
+		ConsumeMagicLinkTokenFn: func(tokenHash string, now time.Time) (*model.User, error) {
+			if tokenHash != utils.HashToken("validtoken") {
+				return nil, gorm.ErrRecordNotFound
+			}
+			return &model.User{ID: 1, Username: "testuser", IsVerified: true}, nil
+		},
+	}
+	authService := NewAuthService(userRepo, &MockEmailSender{})
+
+	resp, err := authService.LoginWithMagicLink("validtoken", dto.RequestMeta{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.Token == "" || resp.User.Username != "testuser" {
+		t.Errorf("expected login response for testuser")
+	}
+
+	_, err = authService.LoginWithMagicLink("badtoken", dto.RequestMeta{})
+	if err == nil || err.Error() != "invalid or expired login link" {
+		t.Fatalf("expected invalid link error, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// durée pendant laquelle un rôle est gardé en mémoire avant relecture en base
+const authorizationCacheTTL = 30 * time.Second
+
+type cachedUser struct {
+	role           string
+	suspendedAt    *time.Time
+	suspendedUntil *time.Time
+	expiresAt      time.Time
+}
+
+type cachedRole struct {
+	role      *model.Role
+	expiresAt time.Time
+}
+
+// résout les permissions d'un user (user -> rôle -> permissions) et son état de
+// suspension, avec un petit cache pour éviter des requêtes SQL sur chaque route protégée
+type AuthorizationService struct {
+	userRepo repository.UserRepository
+	roleRepo *repository.RoleRepository
+
+	mu    sync.RWMutex
+	users map[uint]cachedUser
+	roles map[string]cachedRole
+	now   func() time.Time
+}
+
+func NewAuthorizationService(userRepo repository.UserRepository, roleRepo *repository.RoleRepository) *AuthorizationService {
+	return &AuthorizationService{
+		userRepo: userRepo,
+		roleRepo: roleRepo,
+		users:    make(map[uint]cachedUser),
+		roles:    make(map[string]cachedRole),
+		now:      time.Now,
+	}
+}
+
+// implémente middleware.PermissionChecker
+func (s *AuthorizationService) HasPermission(userID uint, permission string) (bool, error) {
+	user, err := s.user(userID)
+	if err != nil {
+		return false, err
+	}
+
+	role, err := s.role(user.role)
+	if err != nil {
+		return false, err
+	}
+	if role == nil {
+		return false, nil
+	}
+
+	return role.HasPermission(permission), nil
+}
+
+// implémente middleware.SuspensionChecker
+func (s *AuthorizationService) IsSuspended(userID uint) (bool, error) {
+	user, err := s.user(userID)
+	if err != nil {
+		return false, err
+	}
+
+	u := model.User{SuspendedAt: user.suspendedAt, SuspendedUntil: user.suspendedUntil}
+	return u.IsSuspended(s.now()), nil
+}
+
+// à appeler après un changement de rôle, une suspension ou une suppression d'un user
+func (s *AuthorizationService) InvalidateUser(userID uint) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	delete(s.users, userID)
+	s.mu.Unlock()
+}
+
+// à appeler après modification des permissions d'un rôle
+func (s *AuthorizationService) InvalidateRole(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	delete(s.roles, name)
+	s.mu.Unlock()
+}
+
+func (s *AuthorizationService) user(userID uint) (cachedUser, error) {
+	now := s.now()
+
+	s.mu.RLock()
+	entry, ok := s.users[userID]
+	s.mu.RUnlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry, nil
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return cachedUser{}, ErrUserNotFound
+	}
+
+	entry = cachedUser{
+		role:           user.Role,
+		suspendedAt:    user.SuspendedAt,
+		suspendedUntil: user.SuspendedUntil,
+		expiresAt:      now.Add(authorizationCacheTTL),
+	}
+
+	s.mu.Lock()
+	s.users[userID] = entry
+	s.mu.Unlock()
+
+	return entry, nil
+}
+
+// un rôle inexistant (supprimé entre-temps) n'accorde aucune permission
+func (s *AuthorizationService) role(name string) (*model.Role, error) {
+	now := s.now()
+
+	s.mu.RLock()
+	entry, ok := s.roles[name]
+	s.mu.RUnlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.role, nil
+	}
+
+	role, err := s.roleRepo.GetByName(name)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		role = nil
+	}
+
+	s.mu.Lock()
+	s.roles[name] = cachedRole{role: role, expiresAt: now.Add(authorizationCacheTTL)}
+	s.mu.Unlock()
+
+	return role, nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+)
+
+var (
+	ErrCannotBlockSelf = errors.New("you cannot block yourself")
+	ErrCannotMuteSelf  = errors.New("you cannot mute yourself")
+)
+
+// blocages et masquages entre utilisateurs. le filtrage du contenu est fait
+// dans les requêtes des repositories ; ce service gère les relations et sert
+// aux contrôles d'interaction des autres services
+type BlockService struct {
+	blockRepo *repository.BlockRepository
+	userRepo  repository.UserRepository
+}
+
+func NewBlockService(blockRepo *repository.BlockRepository, userRepo repository.UserRepository) *BlockService {
+	return &BlockService{
+		blockRepo: blockRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// bloque targetID ; les abonnements et collaborations entre eux sont supprimés
+func (s *BlockService) Block(userID, targetID uint) error {
+	if userID == targetID {
+		return ErrCannotBlockSelf
+	}
+	if _, err := s.userRepo.GetByID(targetID); err != nil {
+		return ErrUserNotFound
+	}
+
+	if err := s.blockRepo.Block(userID, targetID); err != nil {
+		return errors.New("failed to block user")
+	}
+	return nil
+}
+
+func (s *BlockService) Unblock(userID, targetID uint) error {
+	if err := s.blockRepo.Unblock(userID, targetID); err != nil {
+		return errors.New("failed to unblock user")
+	}
+	return nil
+}
+
+func (s *BlockService) Mute(userID, targetID uint) error {
+	if userID == targetID {
+		return ErrCannotMuteSelf
+	}
+	if _, err := s.userRepo.GetByID(targetID); err != nil {
+		return ErrUserNotFound
+	}
+
+	if err := s.blockRepo.Mute(userID, targetID); err != nil {
+		return errors.New("failed to mute user")
+	}
+	return nil
+}
+
+func (s *BlockService) Unmute(userID, targetID uint) error {
+	if err := s.blockRepo.Unmute(userID, targetID); err != nil {
+		return errors.New("failed to unmute user")
+	}
+	return nil
+}
+
+func (s *BlockService) GetBlockedUsers(userID uint, page, limit int) (*dto.PaginatedRestrictedUsersResponse, error) {
+	blocks, total, err := s.blockRepo.ListBlocked(userID, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to fetch blocked users")
+	}
+
+	response := newRestrictedUsersResponse(total, page, limit)
+	for _, block := range blocks {
+		response.Users = append(response.Users, toRestrictedUserResponse(&block.Blocked, block.CreatedAt))
+	}
+	return response, nil
+}
+
+func (s *BlockService) GetMutedUsers(userID uint, page, limit int) (*dto.PaginatedRestrictedUsersResponse, error) {
+	mutes, total, err := s.blockRepo.ListMuted(userID, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to fetch muted users")
+	}
+
+	response := newRestrictedUsersResponse(total, page, limit)
+	for _, mute := range mutes {
+		response.Users = append(response.Users, toRestrictedUserResponse(&mute.Muted, mute.CreatedAt))
+	}
+	return response, nil
+}
+
+// vrai si l'un des deux a bloqué l'autre ; un *BlockService nil ne bloque rien
+func (s *BlockService) IsBlocked(userID, otherID uint) (bool, error) {
+	if s == nil || userID == otherID {
+		return false, nil
+	}
+	return s.blockRepo.IsBlockedBetween(userID, otherID)
+}
+
+// utilisateurs à retirer des résultats partagés entre viewers (cache) ;
+// un *BlockService nil ne masque personne
+func (s *BlockService) HiddenUserIDs(viewerID uint) (map[uint]bool, error) {
+	if s == nil {
+		return map[uint]bool{}, nil
+	}
+	return s.blockRepo.HiddenUserIDs(viewerID)
+}
+
+func newRestrictedUsersResponse(total int64, page, limit int) *dto.PaginatedRestrictedUsersResponse {
+	return &dto.PaginatedRestrictedUsersResponse{
+		Users:      []dto.RestrictedUserResponse{},
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}
+}
+
+func toRestrictedUserResponse(user *model.User, createdAt time.Time) dto.RestrictedUserResponse {
+	return dto.RestrictedUserResponse{
+		User: dto.ReviewAuthorResponse{
+			ID:                user.ID,
+			Username:          user.Username,
+			ProfilePictureURL: user.ProfilePictureURL,
+		},
+		CreatedAt: createdAt,
+	}
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+)
+
+// alice, bob et carol ont chacun critiqué le même film
+func setupBlockServiceTest(t *testing.T) (serviceFixture, *model.User, *model.User, *model.User) {
+	f := setupServiceFixture(t)
+	users := f.createUsers("alice", "bob", "carol")
+	for _, user := range users {
+		f.db.Create(&model.Review{UserID: user.ID, MovieID: f.movie.ID, Content: "review by " + user.Username})
+	}
+	return f, users[0], users[1], users[2]
+}
+
+// auteurs des critiques du film visibles par viewerID
+func reviewAuthors(t *testing.T, f serviceFixture, viewerID uint) map[uint]bool {
+	t.Helper()
+	response := f.movieReviews(t, viewerID)
+	authors := make(map[uint]bool)
+	for _, review := range response.Reviews {
+		authors[review.Author.ID] = true
+	}
+	if int64(len(authors)) != response.Total {
+		t.Errorf("expected total to match visible reviews, got %d for %d", response.Total, len(authors))
+	}
+	return authors
+}
+
+func TestBlockService_BlockHidesContentBothWays(t *testing.T) {
+	f, alice, bob, carol := setupBlockServiceTest(t)
+	f.db.Create(&model.Follow{FollowerID: alice.ID, FollowedID: bob.ID})
+	f.db.Create(&model.Follow{FollowerID: bob.ID, FollowedID: alice.ID})
+	comment, err := f.comments.CreateComment(bob.ID, f.movie.TmdbID, carol.ID, dto.CreateCommentRequest{Content: "nice"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := f.blocks.Block(alice.ID, alice.ID); !errors.Is(err, ErrCannotBlockSelf) {
+		t.Errorf("expected ErrCannotBlockSelf, got %v", err)
+	}
+	if err := f.blocks.Block(alice.ID, 999); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+	if err := f.blocks.Block(alice.ID, bob.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// les abonnements dans les deux sens disparaissent
+	if following, followers := f.follows.Counts(alice.ID); following != 0 || followers != 0 {
+		t.Errorf("expected follows to be removed, got %d/%d", following, followers)
+	}
+
+	for viewer, hidden := range map[uint]uint{alice.ID: bob.ID, bob.ID: alice.ID} {
+		authors := reviewAuthors(t, f, viewer)
+		if authors[hidden] || !authors[carol.ID] {
+			t.Errorf("expected user %d not to see %d's review, got %v", viewer, hidden, authors)
+		}
+	}
+	if authors := reviewAuthors(t, f, carol.ID); len(authors) != 3 {
+		t.Errorf("expected a third party to see every review, got %v", authors)
+	}
+
+	thread, err := f.comments.GetComments(alice.ID, f.movie.TmdbID, carol.ID, 1, 20)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if thread.Total != 0 {
+		t.Errorf("expected bob's comment to be hidden from alice, got %+v", thread.Comments)
+	}
+
+	// aucune interaction possible, dans un sens comme dans l'autre
+	if err := f.follows.Follow(bob.ID, alice.ID); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("expected follow to be refused, got %v", err)
+	}
+	if _, err := f.reviews.LikeReview(bob.ID, f.movie.TmdbID, alice.ID); !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("expected like to be refused, got %v", err)
+	}
+	if _, err := f.comments.CreateComment(bob.ID, f.movie.TmdbID, alice.ID, dto.CreateCommentRequest{Content: "hey"}); !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("expected comment to be refused, got %v", err)
+	}
+	if _, err := f.comments.CreateComment(alice.ID, f.movie.TmdbID, carol.ID, dto.CreateCommentRequest{Content: "hey", ParentID: &comment.ID}); !errors.Is(err, ErrInvalidParentComment) {
+		t.Errorf("expected reply to a blocked user to be refused, got %v", err)
+	}
+
+	blocked, _ := f.blocks.GetBlockedUsers(alice.ID, 1, 20)
+	if blocked.Total != 1 || blocked.Users[0].User.ID != bob.ID {
+		t.Errorf("expected bob in alice's block list, got %+v", blocked)
+	}
+
+	if err := f.blocks.Unblock(alice.ID, bob.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if authors := reviewAuthors(t, f, bob.ID); !authors[alice.ID] {
+		t.Errorf("expected unblock to restore visibility, got %v", authors)
+	}
+}
+
+func TestBlockService_MuteFiltersOnlyTheMuter(t *testing.T) {
+	f, alice, bob, carol := setupBlockServiceTest(t)
+	for _, liker := range []*model.User{alice, carol} {
+		f.db.Create(&model.ReviewLike{UserID: liker.ID, ReviewUserID: bob.ID, MovieID: f.movie.ID})
+	}
+
+	if err := f.blocks.Mute(alice.ID, alice.ID); !errors.Is(err, ErrCannotMuteSelf) {
+		t.Errorf("expected ErrCannotMuteSelf, got %v", err)
+	}
+	if err := f.blocks.Mute(alice.ID, bob.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if authors := reviewAuthors(t, f, alice.ID); authors[bob.ID] {
+		t.Errorf("expected bob's review to be filtered for alice, got %v", authors)
+	}
+	// bob n'est pas affecté et peut toujours interagir
+	if authors := reviewAuthors(t, f, bob.ID); !authors[alice.ID] {
+		t.Errorf("expected a mute to be one-way, got %v", authors)
+	}
+	if _, err := f.reviews.LikeReview(bob.ID, f.movie.TmdbID, alice.ID); err != nil {
+		t.Errorf("expected muted user to still interact, got %v", err)
+	}
+
+	// le classement en cache est filtré par viewer
+	popular, err := f.reviews.GetPopularReviews(alice.ID, 10)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, review := range popular {
+		if review.Author.ID == bob.ID {
+			t.Errorf("expected muted author to be filtered from popular reviews")
+		}
+	}
+	if popular, _ := f.reviews.GetPopularReviews(carol.ID, 10); len(popular) == 0 || popular[0].Author.ID != bob.ID {
+		t.Errorf("expected bob's review to stay popular for others, got %+v", popular)
+	}
+
+	muted, _ := f.blocks.GetMutedUsers(alice.ID, 1, 20)
+	if muted.Total != 1 || muted.Users[0].User.ID != bob.ID {
+		t.Errorf("expected bob in alice's mute list, got %+v", muted)
+	}
+	f.blocks.Unmute(alice.ID, bob.ID)
+	if authors := reviewAuthors(t, f, alice.ID); !authors[bob.ID] {
+		t.Errorf("expected unmute to restore bob's review, got %v", authors)
+	}
+}
+
+func TestBlockService_ListsOfBlockedUsers(t *testing.T) {
+	f, alice, bob, carol := setupBlockServiceTest(t)
+
+	list, err := f.lists.CreateList(alice.ID, dto.CreateListRequest{Title: "Dreams"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := f.lists.InviteCollaborator(alice.ID, list.ID, dto.InviteListCollaboratorRequest{UserID: bob.ID, Role: model.ListRoleEditor}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	f.blocks.Block(bob.ID, alice.ID)
+
+	if _, err := f.lists.GetList(bob.ID, list.ID, 1, 20); !errors.Is(err, ErrListNotFound) {
+		t.Errorf("expected blocked user's list to be hidden, got %v", err)
+	}
+	if _, err := f.lists.GetUserLists(alice.ID, bob.ID, 1, 20); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("expected blocking user to be hidden, got %v", err)
+	}
+	if invitations, _ := f.lists.GetInvitations(bob.ID); len(invitations) != 0 {
+		t.Errorf("expected pending invitation to be removed, got %+v", invitations)
+	}
+	if _, err := f.lists.InviteCollaborator(alice.ID, list.ID, dto.InviteListCollaboratorRequest{UserID: bob.ID, Role: model.ListRoleViewer}); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("expected invitation to be refused, got %v", err)
+	}
+	if lists, _ := f.lists.GetUserLists(carol.ID, alice.ID, 1, 20); lists.Total != 1 {
+		t.Errorf("expected third party to still see alice's list, got %d", lists.Total)
+	}
+}
+
+func TestBlockService_MutedActorsLeaveInbox(t *testing.T) {
+	f, alice, bob, carol := setupBlockServiceTest(t)
+
+	payload := model.FollowedReviewPayload{TmdbID: f.movie.TmdbID, MovieTitle: f.movie.Title}
+	f.notifications.Notify([]uint{alice.ID}, &bob.ID, payload)
+	f.notifications.Notify([]uint{alice.ID}, &carol.ID, payload)
+	f.notifications.Notify([]uint{alice.ID}, nil, model.EmailVerifiedPayload{})
+
+	f.blocks.Mute(alice.ID, bob.ID)
+
+	inbox, _ := f.notifications.GetNotifications(alice.ID, false, 1, 20)
+	if inbox.Total != 2 || inbox.UnreadCount != 2 {
+		t.Fatalf("expected muted actor's notification to be filtered, got %+v", inbox)
+	}
+	for _, notification := range inbox.Notifications {
+		if notification.Actor != nil && notification.Actor.ID == bob.ID {
+			t.Errorf("unexpected notification from muted user: %+v", notification)
+		}
+	}
+}
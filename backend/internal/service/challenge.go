@@ -0,0 +1,650 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrGoalNotFound       = errors.New("no goal set for this year")
+	ErrChallengeNotFound  = errors.New("challenge not found")
+	ErrChallengeNotJoined = errors.New("you have not joined this challenge")
+	ErrChallengeEnded     = errors.New("challenge has ended")
+	ErrInvalidChallenge   = errors.New("invalid challenge")
+	countryCodePattern    = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+// objectifs annuels et défis. la progression des défis est tenue à jour de
+// façon incrémentale à chaque changement du suivi d'un film (TrackChanged) ;
+// un recalcul complet n'a lieu qu'à l'inscription ou quand le défi change
+type ChallengeService struct {
+	challengeRepo *repository.ChallengeRepository
+	userRepo      repository.UserRepository
+	movieRepo     *repository.MovieRepository
+	movies        *MovieService
+	dispatcher    *Dispatcher
+	audit         *AuditService
+}
+
+// movies sert à importer les métadonnées manquantes quand un critère porte
+// sur les genres, pays ou réalisateurs
+func NewChallengeService(challengeRepo *repository.ChallengeRepository, userRepo repository.UserRepository, movieRepo *repository.MovieRepository, movies *MovieService) *ChallengeService {
+	return &ChallengeService{
+		challengeRepo: challengeRepo,
+		userRepo:      userRepo,
+		movieRepo:     movieRepo,
+		movies:        movies,
+	}
+}
+
+func (s *ChallengeService) SetDispatcher(dispatcher *Dispatcher) {
+	s.dispatcher = dispatcher
+}
+
+func (s *ChallengeService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
+// OBJECTIFS
+
+// year 0 : année en cours dans le fuseau de l'utilisateur
+func (s *ChallengeService) GetGoal(userID uint, year int) (*dto.GoalProgress, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if year == 0 {
+		year = time.Now().In(user.Zone()).Year()
+	}
+
+	goal, err := s.challengeRepo.GetGoal(userID, year)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, errors.New("failed to fetch goal")
+	}
+	return s.goalProgress(user, goal)
+}
+
+func (s *ChallengeService) SetGoal(userID uint, input dto.SetGoalRequest) (*dto.GoalProgress, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	goal := &model.WatchGoal{UserID: userID, Year: time.Now().In(user.Zone()).Year(), Target: input.Target}
+	if input.Year != nil {
+		goal.Year = *input.Year
+	}
+	if err := s.challengeRepo.UpsertGoal(goal); err != nil {
+		return nil, errors.New("failed to save goal")
+	}
+	return s.goalProgress(user, goal)
+}
+
+func (s *ChallengeService) DeleteGoal(userID uint, year int) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if year == 0 {
+		year = time.Now().In(user.Zone()).Year()
+	}
+
+	if err := s.challengeRepo.DeleteGoal(userID, year); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrGoalNotFound
+		}
+		return errors.New("failed to delete goal")
+	}
+	return nil
+}
+
+// objectif de l'année en cours pour les stats du profil ; watched : films vus
+// cette année (déjà comptés par l'appelant). nil si aucun objectif
+func (s *ChallengeService) CurrentGoal(user *model.User, watched int64) *dto.GoalProgress {
+	if s == nil {
+		return nil
+	}
+	goal, err := s.challengeRepo.GetGoal(user.ID, time.Now().In(user.Zone()).Year())
+	if err != nil {
+		return nil
+	}
+	return toGoalProgress(goal, watched)
+}
+
+func (s *ChallengeService) goalProgress(user *model.User, goal *model.WatchGoal) (*dto.GoalProgress, error) {
+	from := time.Date(goal.Year, 1, 1, 0, 0, 0, 0, user.Zone())
+	watched, err := s.movieRepo.CountWatchedBetween(user.ID, from, from.AddDate(1, 0, 0))
+	if err != nil {
+		return nil, errors.New("failed to compute goal progress")
+	}
+	return toGoalProgress(goal, watched), nil
+}
+
+func toGoalProgress(goal *model.WatchGoal, watched int64) *dto.GoalProgress {
+	return &dto.GoalProgress{
+		Year:      goal.Year,
+		Target:    goal.Target,
+		Watched:   int(watched),
+		Completed: int(watched) >= goal.Target,
+	}
+}
+
+// DÉFIS (administration)
+
+func (s *ChallengeService) CreateChallenge(adminID uint, input dto.CreateChallengeRequest, meta dto.RequestMeta) (*dto.ChallengeResponse, error) {
+	challenge := &model.Challenge{
+		Title:       input.Title,
+		Description: input.Description,
+		Criteria:    input.Criteria,
+		CountBy:     input.CountBy,
+		Target:      input.Target,
+		StartsAt:    input.StartsAt,
+		EndsAt:      input.EndsAt,
+		CreatedByID: uintPtr(adminID),
+	}
+	if challenge.CountBy == "" {
+		challenge.CountBy = model.ChallengeCountFilms
+	}
+	if err := normalizeChallenge(challenge); err != nil {
+		return nil, err
+	}
+
+	if err := s.challengeRepo.Create(challenge); err != nil {
+		return nil, errors.New("failed to create challenge")
+	}
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditAdminChallengeCreated,
+		ActorID:  uintPtr(adminID),
+		Metadata: map[string]interface{}{"challenge_id": challenge.ID, "title": challenge.Title},
+	})
+
+	response := dto.ToChallengeResponse(challenge, 0, nil)
+	return &response, nil
+}
+
+// modifier les critères, le décompte, l'objectif ou la période recalcule la
+// progression de tous les participants
+func (s *ChallengeService) UpdateChallenge(adminID, challengeID uint, input dto.UpdateChallengeRequest, meta dto.RequestMeta) (*dto.ChallengeResponse, error) {
+	challenge, err := s.getChallenge(challengeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Title != nil {
+		challenge.Title = *input.Title
+	}
+	if input.Description != nil {
+		challenge.Description = *input.Description
+	}
+	rebuild := input.Criteria != nil || input.CountBy != nil || input.Target != nil || input.StartsAt != nil || input.EndsAt != nil
+	if input.Criteria != nil {
+		challenge.Criteria = *input.Criteria
+	}
+	if input.CountBy != nil {
+		challenge.CountBy = *input.CountBy
+	}
+	if input.Target != nil {
+		challenge.Target = *input.Target
+	}
+	if input.StartsAt != nil {
+		challenge.StartsAt = input.StartsAt
+	}
+	if input.EndsAt != nil {
+		challenge.EndsAt = input.EndsAt
+	}
+	if err := normalizeChallenge(challenge); err != nil {
+		return nil, err
+	}
+
+	if err := s.challengeRepo.Update(challenge); err != nil {
+		return nil, errors.New("failed to update challenge")
+	}
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditAdminChallengeUpdated,
+		ActorID:  uintPtr(adminID),
+		Metadata: map[string]interface{}{"challenge_id": challenge.ID, "title": challenge.Title},
+	})
+
+	if rebuild {
+		s.rebuildParticipants(*challenge)
+	}
+	return s.challengeResponse(0, challenge)
+}
+
+// les participations sont conservées : le défi disparaît des listes
+func (s *ChallengeService) DeleteChallenge(adminID, challengeID uint, meta dto.RequestMeta) error {
+	if err := s.challengeRepo.Delete(challengeID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrChallengeNotFound
+		}
+		return errors.New("failed to delete challenge")
+	}
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditAdminChallengeDeleted,
+		ActorID:  uintPtr(adminID),
+		Metadata: map[string]interface{}{"challenge_id": challengeID},
+	})
+	return nil
+}
+
+// vérifie la cohérence du défi et normalise les codes pays
+func normalizeChallenge(challenge *model.Challenge) error {
+	if !model.IsValidChallengeCountMode(challenge.CountBy) {
+		return fmt.Errorf("%w: unknown count mode %q", ErrInvalidChallenge, challenge.CountBy)
+	}
+	if challenge.Target < 1 {
+		return fmt.Errorf("%w: target must be at least 1", ErrInvalidChallenge)
+	}
+	if challenge.StartsAt != nil && challenge.EndsAt != nil && !challenge.EndsAt.After(*challenge.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidChallenge)
+	}
+
+	if err := normalizeCriteria(&challenge.Criteria); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidChallenge, err)
+	}
+	return nil
+}
+
+// critères cohérents, codes pays en majuscules ; partagé avec les règles de badges
+func normalizeCriteria(criteria *model.ChallengeCriteria) error {
+	if criteria.ReleaseYearMin != nil && criteria.ReleaseYearMax != nil && *criteria.ReleaseYearMin > *criteria.ReleaseYearMax {
+		return errors.New("release_year_min must not exceed release_year_max")
+	}
+	if (criteria.RuntimeMin != nil && *criteria.RuntimeMin < 0) || (criteria.RuntimeMax != nil && *criteria.RuntimeMax < 0) {
+		return errors.New("runtime must be positive")
+	}
+	if criteria.RuntimeMin != nil && criteria.RuntimeMax != nil && *criteria.RuntimeMin > *criteria.RuntimeMax {
+		return errors.New("runtime_min must not exceed runtime_max")
+	}
+	for i, code := range criteria.Countries {
+		criteria.Countries[i] = strings.ToUpper(strings.TrimSpace(code))
+		if !countryCodePattern.MatchString(criteria.Countries[i]) {
+			return fmt.Errorf("invalid country code %q", code)
+		}
+	}
+	return nil
+}
+
+// DÉFIS (membres)
+
+// défis en cours ou à venir, avec la participation de l'utilisateur
+func (s *ChallengeService) ListChallenges(userID uint, page, limit int) (*dto.PaginatedChallengesResponse, error) {
+	now := time.Now()
+	challenges, total, err := s.challengeRepo.List(&now, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to fetch challenges")
+	}
+
+	responses, err := s.challengeResponses(userID, challenges)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PaginatedChallengesResponse{
+		Challenges: responses,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, nil
+}
+
+// défis rejoints, terminés compris
+func (s *ChallengeService) GetMyChallenges(userID uint) ([]dto.ChallengeResponse, error) {
+	challenges, err := s.challengeRepo.Joined(userID)
+	if err != nil {
+		return nil, errors.New("failed to fetch challenges")
+	}
+	return s.challengeResponses(userID, challenges)
+}
+
+func (s *ChallengeService) GetChallenge(userID, challengeID uint) (*dto.ChallengeResponse, error) {
+	challenge, err := s.getChallenge(challengeID)
+	if err != nil {
+		return nil, err
+	}
+	return s.challengeResponse(userID, challenge)
+}
+
+// l'inscription prend en compte les films déjà vus pendant la période du défi
+func (s *ChallengeService) JoinChallenge(userID, challengeID uint) (*dto.ChallengeResponse, error) {
+	challenge, err := s.getChallenge(challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.EndsAt != nil && !challenge.EndsAt.After(time.Now()) {
+		return nil, ErrChallengeEnded
+	}
+
+	if _, err := s.challengeRepo.GetParticipant(challengeID, userID); err == nil {
+		return s.challengeResponse(userID, challenge)
+	}
+	if err := s.challengeRepo.Join(&model.ChallengeParticipant{ChallengeID: challengeID, UserID: userID}); err != nil {
+		return nil, errors.New("failed to join challenge")
+	}
+
+	s.dispatcher.Dispatch("challenges.backfill", func() error {
+		return s.rebuild(challenge, userID)
+	})
+	return s.challengeResponse(userID, challenge)
+}
+
+func (s *ChallengeService) LeaveChallenge(userID, challengeID uint) error {
+	if _, err := s.getChallenge(challengeID); err != nil {
+		return err
+	}
+	if err := s.challengeRepo.Leave(challengeID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrChallengeNotJoined
+		}
+		return errors.New("failed to leave challenge")
+	}
+	return nil
+}
+
+func (s *ChallengeService) getChallenge(challengeID uint) (*model.Challenge, error) {
+	challenge, err := s.challengeRepo.GetByID(challengeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrChallengeNotFound
+		}
+		return nil, errors.New("failed to fetch challenge")
+	}
+	return challenge, nil
+}
+
+func (s *ChallengeService) challengeResponse(userID uint, challenge *model.Challenge) (*dto.ChallengeResponse, error) {
+	responses, err := s.challengeResponses(userID, []model.Challenge{*challenge})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+func (s *ChallengeService) challengeResponses(userID uint, challenges []model.Challenge) ([]dto.ChallengeResponse, error) {
+	ids := make([]uint, len(challenges))
+	for i := range challenges {
+		ids[i] = challenges[i].ID
+	}
+	counts, err := s.challengeRepo.CountParticipants(ids)
+	if err != nil {
+		return nil, errors.New("failed to fetch challenges")
+	}
+	participations, err := s.challengeRepo.Participations(userID, ids)
+	if err != nil {
+		return nil, errors.New("failed to fetch challenges")
+	}
+
+	responses := make([]dto.ChallengeResponse, 0, len(challenges))
+	for i := range challenges {
+		var participant *model.ChallengeParticipant
+		if p, ok := participations[challenges[i].ID]; ok {
+			participant = &p
+		}
+		responses = append(responses, dto.ToChallengeResponse(&challenges[i], counts[challenges[i].ID], participant))
+	}
+	return responses, nil
+}
+
+// PROGRESSION
+
+// à appeler après chaque changement du suivi d'un film (vu, date de
+// visionnage, retiré) ; l'évaluation se fait en tâche de fond
+func (s *ChallengeService) TrackChanged(userID, movieID uint) {
+	if s == nil {
+		return
+	}
+	s.dispatcher.Dispatch("challenges.evaluate", func() error {
+		return s.evaluate(userID, movieID)
+	})
+}
+
+// met à jour les défis de l'utilisateur pour un seul film : ajout s'il compte
+// désormais, recalcul du défi s'il comptait et ne compte plus
+func (s *ChallengeService) evaluate(userID, movieID uint) error {
+	challenges, err := s.challengeRepo.Joined(userID)
+	if err != nil || len(challenges) == 0 {
+		return err
+	}
+	countedIn, err := s.challengeRepo.ChallengesWithMovie(userID, movieID)
+	if err != nil {
+		return err
+	}
+	counted := make(map[uint]bool, len(countedIn))
+	for _, id := range countedIn {
+		counted[id] = true
+	}
+
+	movies, err := s.watchedMovies(userID, &movieID, challenges)
+	if err != nil {
+		return err
+	}
+
+	for i := range challenges {
+		challenge := &challenges[i]
+		matches := len(movies) == 1 && challenge.Covers(movies[0].WatchedAt) && len(challengeKeys(challenge, movies[0])) > 0
+		switch {
+		case counted[challenge.ID] && !matches:
+			err = s.rebuild(challenge, userID)
+		case !counted[challenge.ID] && matches:
+			err = s.addMovie(challenge, userID, movies[0], nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recalcule entièrement la progression d'un participant
+func (s *ChallengeService) rebuild(challenge *model.Challenge, userID uint) error {
+	if err := s.challengeRepo.ResetEntries(challenge.ID, userID, challenge.Target); err != nil {
+		return err
+	}
+	movies, err := s.watchedMovies(userID, nil, []model.Challenge{*challenge})
+	if err != nil {
+		return err
+	}
+
+	covered := make(map[string]bool)
+	for _, movie := range movies {
+		if !challenge.Covers(movie.WatchedAt) {
+			continue
+		}
+		if err := s.addMovie(challenge, userID, movie, covered); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ChallengeService) rebuildParticipants(challenge model.Challenge) {
+	s.dispatcher.Dispatch("challenges.rebuild", func() error {
+		userIDs, err := s.challengeRepo.ParticipantIDs(challenge.ID)
+		if err != nil {
+			return err
+		}
+		for _, userID := range userIDs {
+			if err := s.rebuild(&challenge, userID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// compte le film pour la première valeur qu'il couvre et qui ne l'est pas encore ;
+// covered (clés déjà couvertes) est chargé si nil
+func (s *ChallengeService) addMovie(challenge *model.Challenge, userID uint, movie repository.ChallengeMovie, covered map[string]bool) error {
+	keys := challengeKeys(challenge, movie)
+	if len(keys) == 0 {
+		return nil
+	}
+	if covered == nil {
+		existing, err := s.challengeRepo.EntryKeys(challenge.ID, userID)
+		if err != nil {
+			return err
+		}
+		covered = make(map[string]bool, len(existing))
+		for _, key := range existing {
+			covered[key] = true
+		}
+	}
+
+	for _, key := range keys {
+		if covered[key] {
+			continue
+		}
+		covered[key] = true
+		_, err := s.challengeRepo.AddEntry(&model.ChallengeEntry{
+			ChallengeID: challenge.ID,
+			UserID:      userID,
+			Key:         key,
+			MovieID:     movie.MovieID,
+			WatchedAt:   movie.WatchedAt,
+		}, challenge.Target)
+		return err
+	}
+	return nil
+}
+
+// films vus avec leurs attributs ; importe au passage les métadonnées
+// manquantes si un des défis en a besoin
+func (s *ChallengeService) watchedMovies(userID uint, movieID *uint, challenges []model.Challenge) ([]repository.ChallengeMovie, error) {
+	needsMetadata := false
+	for i := range challenges {
+		needsMetadata = needsMetadata || challenges[i].NeedsMetadata()
+	}
+	return watchedWithMetadata(s.challengeRepo, s.movies, userID, movieID, needsMetadata)
+}
+
+// films vus par l'utilisateur (tous, ou seulement movieID) ; avec needsMetadata,
+// les métadonnées manquantes sont d'abord importées depuis TMDB
+func watchedWithMetadata(repo *repository.ChallengeRepository, movies *MovieService, userID uint, movieID *uint, needsMetadata bool) ([]repository.ChallengeMovie, error) {
+	watched, err := repo.WatchedMovies(userID, movieID)
+	if err != nil || movies == nil || !needsMetadata {
+		return watched, err
+	}
+
+	var unsynced []uint
+	for _, movie := range watched {
+		if movie.MetadataSynced == nil {
+			unsynced = append(unsynced, movie.MovieID)
+		}
+	}
+	if len(unsynced) == 0 {
+		return watched, nil
+	}
+
+	if err := movies.SyncMissingMetadata(unsynced); err != nil {
+		utils.Log.Warn("Metadata sync incomplete", zap.Uint("user_id", userID), zap.Error(err))
+	}
+	return repo.WatchedMovies(userID, movieID)
+}
+
+// valeurs que le film peut couvrir dans le défi ; vide s'il ne remplit pas les critères
+func challengeKeys(challenge *model.Challenge, movie repository.ChallengeMovie) []string {
+	criteria := challenge.Criteria
+	if !matchesCriteria(criteria, movie) {
+		return nil
+	}
+
+	switch challenge.CountBy {
+	case model.ChallengeCountCountries:
+		return restrictStrings(movie.Countries, criteria.Countries)
+	case model.ChallengeCountGenres:
+		return intKeys(restrictInts(movie.Genres, criteria.Genres))
+	case model.ChallengeCountDirectors:
+		return intKeys(restrictInts(movie.Directors, criteria.Directors))
+	case model.ChallengeCountDecades:
+		if movie.ReleaseYear == 0 {
+			return nil
+		}
+		return []string{strconv.Itoa(movie.ReleaseYear / 10 * 10)}
+	default:
+		return []string{strconv.FormatUint(uint64(movie.MovieID), 10)}
+	}
+}
+
+// année et durée inconnues (0) ne satisfont aucune borne
+func matchesCriteria(criteria model.ChallengeCriteria, movie repository.ChallengeMovie) bool {
+	if criteria.ReleaseYearMin != nil && (movie.ReleaseYear == 0 || movie.ReleaseYear < *criteria.ReleaseYearMin) {
+		return false
+	}
+	if criteria.ReleaseYearMax != nil && (movie.ReleaseYear == 0 || movie.ReleaseYear > *criteria.ReleaseYearMax) {
+		return false
+	}
+	if criteria.RuntimeMin != nil && (movie.Runtime == 0 || movie.Runtime < *criteria.RuntimeMin) {
+		return false
+	}
+	if criteria.RuntimeMax != nil && (movie.Runtime == 0 || movie.Runtime > *criteria.RuntimeMax) {
+		return false
+	}
+	if len(criteria.Genres) > 0 && len(restrictInts(movie.Genres, criteria.Genres)) == 0 {
+		return false
+	}
+	if len(criteria.Countries) > 0 && len(restrictStrings(movie.Countries, criteria.Countries)) == 0 {
+		return false
+	}
+	if len(criteria.Directors) > 0 && len(restrictInts(movie.Directors, criteria.Directors)) == 0 {
+		return false
+	}
+	return true
+}
+
+// values limitées à allowed (toutes si allowed est vide), dans l'ordre de values
+func restrictInts(values, allowed []int) []int {
+	if len(allowed) == 0 {
+		return values
+	}
+	var kept []int
+	for _, v := range values {
+		for _, a := range allowed {
+			if v == a {
+				kept = append(kept, v)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+func restrictStrings(values, allowed []string) []string {
+	if len(allowed) == 0 {
+		return values
+	}
+	var kept []string
+	for _, v := range values {
+		for _, a := range allowed {
+			if v == a {
+				kept = append(kept, v)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+func intKeys(values []int) []string {
+	keys := make([]string, len(values))
+	for i, v := range values {
+		keys[i] = strconv.Itoa(v)
+	}
+	return keys
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+)
+
+func TestChallengeService_GoalsAndChallenges(t *testing.T) {
+	thriller := dto.TMDBGenre{ID: 53, Name: "Thriller"}
+	catalog := map[int]dto.TMDBMovieDetails{
+		200: tmdbDetails(200, "Psycho", "1960-06-16", 109, []dto.TMDBGenre{thriller}, "US", nil, dto.TMDBCrewMember{ID: 2636, Name: "Alfred Hitchcock"}),
+		201: tmdbDetails(201, "Breathless", "1960-03-16", 90, nil, "FR", nil, dto.TMDBCrewMember{ID: 3776, Name: "Jean-Luc Godard"}),
+		202: tmdbDetails(202, "Heat", "1995-12-15", 170, []dto.TMDBGenre{thriller}, "US", nil, dto.TMDBCrewMember{ID: 638, Name: "Michael Mann"}),
+	}
+	db, tmdbService := setupCatalogTest(t, catalog, &model.WatchGoal{}, &model.Challenge{}, &model.ChallengeParticipant{}, &model.ChallengeEntry{})
+	movieRepo := repository.NewMovieRepository(db)
+	movieService := NewMovieService(movieRepo, tmdbService)
+	// dispatcher nil : la progression est évaluée immédiatement
+	challengeService := NewChallengeService(repository.NewChallengeRepository(db), repository.NewUserRepository(db), movieRepo, movieService)
+	movieService.SetChallengeService(challengeService)
+
+	admin := &model.User{Username: "admin", Email: "admin@example.com"}
+	user := &model.User{Username: "cinephile", Email: "cinephile@example.com"}
+	db.Create(admin)
+	db.Create(user)
+
+	// objectif annuel
+	if _, err := challengeService.GetGoal(user.ID, 0); !errors.Is(err, ErrGoalNotFound) {
+		t.Errorf("expected ErrGoalNotFound, got %v", err)
+	}
+	goal, err := challengeService.SetGoal(user.ID, dto.SetGoalRequest{Target: 2})
+	if err != nil || goal.Year != time.Now().In(user.Zone()).Year() || goal.Watched != 0 || goal.Completed {
+		t.Fatalf("unexpected goal: %+v, %v", goal, err)
+	}
+
+	// vu avant l'inscription : compté par le rattrapage
+	if err := movieService.LogMovie(user.ID, 200, dto.LogMovieRequest{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	maxYear := 1969
+	classics, err := challengeService.CreateChallenge(admin.ID, dto.CreateChallengeRequest{
+		Title:    "Before 1970",
+		Criteria: model.ChallengeCriteria{ReleaseYearMax: &maxYear},
+		Target:   2,
+	}, dto.RequestMeta{})
+	if err != nil || classics.CountBy != model.ChallengeCountFilms {
+		t.Fatalf("unexpected challenge: %+v, %v", classics, err)
+	}
+	countries, err := challengeService.CreateChallenge(admin.ID, dto.CreateChallengeRequest{
+		Title:   "One film per country",
+		CountBy: model.ChallengeCountCountries,
+		Target:  3,
+	}, dto.RequestMeta{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	joined, err := challengeService.JoinChallenge(user.ID, classics.ID)
+	if err != nil || !joined.Joined || joined.Progress.Progress != 1 || joined.Participants != 1 {
+		t.Fatalf("expected backfilled progress 1, got %+v, %v", joined.Progress, err)
+	}
+	if _, err := challengeService.JoinChallenge(user.ID, countries.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// progression incrémentale : TrackMovie et LogMovie
+	watched := true
+	if err := movieService.TrackMovie(user.ID, 201, dto.TrackMovieRequest{IsWatched: &watched}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := movieService.LogMovie(user.ID, 202, dto.LogMovieRequest{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// un revisionnage ne compte pas deux fois
+	if err := movieService.LogMovie(user.ID, 200, dto.LogMovieRequest{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	progress := func(challengeID uint) *dto.ChallengeProgress {
+		t.Helper()
+		response, err := challengeService.GetChallenge(user.ID, challengeID)
+		if err != nil || response.Progress == nil {
+			t.Fatalf("expected joined challenge, got %+v, %v", response, err)
+		}
+		return response.Progress
+	}
+	if p := progress(classics.ID); p.Progress != 2 || !p.Completed {
+		t.Errorf("expected classics completed with 2 films, got %+v", p)
+	}
+	// Psycho et Heat couvrent tous deux les US
+	if p := progress(countries.ID); p.Progress != 2 || p.Completed {
+		t.Errorf("expected 2 countries, got %+v", p)
+	}
+
+	// retirer un film le retire des défis
+	if err := movieService.DeleteTrack(user.ID, 201); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if p := progress(classics.ID); p.Progress != 1 || p.Completed {
+		t.Errorf("expected classics back to 1 film, got %+v", p)
+	}
+	if p := progress(countries.ID); p.Progress != 1 {
+		t.Errorf("expected 1 country, got %+v", p)
+	}
+
+	// objectif : 2 films vus cette année (le revisionnage ne compte pas)
+	if goal, _ := challengeService.GetGoal(user.ID, 0); goal.Watched != 2 || !goal.Completed {
+		t.Errorf("unexpected goal progress: %+v", goal)
+	}
+	if current := challengeService.CurrentGoal(user, 1); current == nil || current.Target != 2 || current.Completed {
+		t.Errorf("unexpected current goal: %+v", current)
+	}
+
+	// changer les critères recalcule la progression
+	genre := []int{53}
+	updated, err := challengeService.UpdateChallenge(admin.ID, classics.ID, dto.UpdateChallengeRequest{
+		Criteria: &model.ChallengeCriteria{Genres: genre},
+	}, dto.RequestMeta{})
+	if err != nil || len(updated.Criteria.Genres) != 1 {
+		t.Fatalf("unexpected update: %+v, %v", updated, err)
+	}
+	if p := progress(classics.ID); p.Progress != 2 || !p.Completed {
+		t.Errorf("expected 2 thrillers, got %+v", p)
+	}
+
+	mine, _ := challengeService.GetMyChallenges(user.ID)
+	if len(mine) != 2 {
+		t.Errorf("expected 2 joined challenges, got %d", len(mine))
+	}
+	if err := challengeService.LeaveChallenge(user.ID, countries.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := challengeService.LeaveChallenge(user.ID, countries.ID); !errors.Is(err, ErrChallengeNotJoined) {
+		t.Errorf("expected ErrChallengeNotJoined, got %v", err)
+	}
+}
+
+func TestChallengeService_Validation(t *testing.T) {
+	db := setupMovieServiceTestDB(t)
+	if err := db.AutoMigrate(&model.WatchGoal{}, &model.Challenge{}, &model.ChallengeParticipant{}, &model.ChallengeEntry{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	challengeService := NewChallengeService(repository.NewChallengeRepository(db), repository.NewUserRepository(db), repository.NewMovieRepository(db), nil)
+
+	user := &model.User{Username: "cinephile", Email: "cinephile@example.com"}
+	db.Create(user)
+
+	minYear, maxYear := 1990, 1980
+	past := time.Now().Add(-time.Hour)
+	invalid := []dto.CreateChallengeRequest{
+		{Title: "Years", Target: 1, Criteria: model.ChallengeCriteria{ReleaseYearMin: &minYear, ReleaseYearMax: &maxYear}},
+		{Title: "Countries", Target: 1, Criteria: model.ChallengeCriteria{Countries: []string{"France"}}},
+		{Title: "Mode", Target: 1, CountBy: "actors"},
+		{Title: "Window", Target: 1, StartsAt: &past, EndsAt: &past},
+	}
+	for _, input := range invalid {
+		if _, err := challengeService.CreateChallenge(1, input, dto.RequestMeta{}); !errors.Is(err, ErrInvalidChallenge) {
+			t.Errorf("%s: expected ErrInvalidChallenge, got %v", input.Title, err)
+		}
+	}
+
+	created, err := challengeService.CreateChallenge(1, dto.CreateChallengeRequest{
+		Title:    "French films",
+		Target:   5,
+		Criteria: model.ChallengeCriteria{Countries: []string{"fr"}},
+		EndsAt:   &past,
+	}, dto.RequestMeta{})
+	if err != nil || created.Criteria.Countries[0] != "FR" {
+		t.Fatalf("expected normalized country code, got %+v, %v", created, err)
+	}
+	if _, err := challengeService.JoinChallenge(user.ID, created.ID); !errors.Is(err, ErrChallengeEnded) {
+		t.Errorf("expected ErrChallengeEnded, got %v", err)
+	}
+	if list, _ := challengeService.ListChallenges(user.ID, 1, 20); list.Total != 0 {
+		t.Errorf("expected ended challenge to be hidden, got %d", list.Total)
+	}
+	if _, err := challengeService.GetChallenge(user.ID, 999); !errors.Is(err, ErrChallengeNotFound) {
+		t.Errorf("expected ErrChallengeNotFound, got %v", err)
+	}
+}
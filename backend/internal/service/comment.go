@@ -0,0 +1,285 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCommentNotFound      = errors.New("comment not found")
+	ErrCommentDeleted       = errors.New("comment has been deleted")
+	ErrCommentsLocked       = errors.New("comments are locked on this review")
+	ErrInvalidParentComment = errors.New("parent comment does not belong to this review")
+	ErrNotCommentAuthor     = errors.New("only the author can edit this comment")
+	ErrCannotDeleteComment  = errors.New("you cannot delete this comment")
+	ErrNotReviewAuthor      = errors.New("only the review author can lock comments")
+	ErrEmptyComment         = errors.New("comment cannot be empty")
+)
+
+type CommentService struct {
+	commentRepo *repository.CommentRepository
+	reviewRepo  *repository.ReviewRepository
+	movieRepo   *repository.MovieRepository
+	blocks      *BlockService
+	now         func() time.Time
+}
+
+func NewCommentService(commentRepo *repository.CommentRepository, reviewRepo *repository.ReviewRepository, movieRepo *repository.MovieRepository) *CommentService {
+	return &CommentService{
+		commentRepo: commentRepo,
+		reviewRepo:  reviewRepo,
+		movieRepo:   movieRepo,
+		now:         time.Now,
+	}
+}
+
+func (s *CommentService) SetBlockService(blocks *BlockService) {
+	s.blocks = blocks
+}
+
+// fils de commentaires d'une critique : pagination sur le premier niveau,
+// toutes les réponses de chaque fil sont renvoyées à plat sous leur racine
+func (s *CommentService) GetComments(viewerID uint, tmdbID int, reviewUserID uint, page, limit int) (*dto.PaginatedCommentsResponse, error) {
+	review, err := s.findReview(viewerID, tmdbID, reviewUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	roots, total, err := s.commentRepo.ListRoots(review.UserID, review.MovieID, viewerID, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to fetch comments")
+	}
+
+	rootIDs := make([]uint, 0, len(roots))
+	for _, root := range roots {
+		rootIDs = append(rootIDs, root.ID)
+	}
+	replies, err := s.commentRepo.ListReplies(rootIDs, viewerID)
+	if err != nil {
+		return nil, errors.New("failed to fetch comments")
+	}
+
+	repliesByRoot := make(map[uint][]dto.CommentResponse)
+	for i := range replies {
+		rootID := *replies[i].RootID
+		repliesByRoot[rootID] = append(repliesByRoot[rootID], toCommentResponse(&replies[i]))
+	}
+
+	response := &dto.PaginatedCommentsResponse{
+		Comments:       make([]dto.CommentResponse, 0, len(roots)),
+		CommentsLocked: review.CommentsLocked,
+		Total:          total,
+		Page:           page,
+		Limit:          limit,
+		TotalPages:     int((total + int64(limit) - 1) / int64(limit)),
+	}
+	for i := range roots {
+		item := toCommentResponse(&roots[i])
+		item.Replies = repliesByRoot[roots[i].ID]
+		response.Comments = append(response.Comments, item)
+	}
+
+	return response, nil
+}
+
+func (s *CommentService) CreateComment(userID uint, tmdbID int, reviewUserID uint, input dto.CreateCommentRequest) (*dto.CommentResponse, error) {
+	content := strings.TrimSpace(input.Content)
+	if content == "" {
+		return nil, ErrEmptyComment
+	}
+
+	review, err := s.findReview(userID, tmdbID, reviewUserID)
+	if err != nil {
+		return nil, err
+	}
+	if review.CommentsLocked {
+		return nil, ErrCommentsLocked
+	}
+
+	comment := &model.ReviewComment{
+		ReviewUserID: review.UserID,
+		MovieID:      review.MovieID,
+		AuthorID:     userID,
+		Content:      content,
+	}
+
+	if input.ParentID != nil {
+		parent, err := s.commentRepo.GetByID(*input.ParentID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrInvalidParentComment
+			}
+			return nil, errors.New("failed to fetch parent comment")
+		}
+		if parent.ReviewUserID != review.UserID || parent.MovieID != review.MovieID {
+			return nil, ErrInvalidParentComment
+		}
+		if parent.IsDeleted() {
+			return nil, ErrCommentDeleted
+		}
+		// on ne répond pas à un utilisateur avec qui on est en blocage
+		blocked, err := s.blocks.IsBlocked(userID, parent.AuthorID)
+		if err != nil {
+			return nil, errors.New("failed to fetch parent comment")
+		}
+		if blocked {
+			return nil, ErrInvalidParentComment
+		}
+
+		comment.ParentID = &parent.ID
+		comment.RootID = parent.RootID
+		if comment.RootID == nil {
+			comment.RootID = &parent.ID
+		}
+	}
+
+	if err := s.commentRepo.Create(comment); err != nil {
+		return nil, errors.New("failed to create comment")
+	}
+
+	return s.reload(comment.ID)
+}
+
+func (s *CommentService) UpdateComment(userID, commentID uint, input dto.UpdateCommentRequest) (*dto.CommentResponse, error) {
+	content := strings.TrimSpace(input.Content)
+	if content == "" {
+		return nil, ErrEmptyComment
+	}
+
+	comment, err := s.getComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != userID {
+		return nil, ErrNotCommentAuthor
+	}
+	if comment.IsDeleted() {
+		return nil, ErrCommentDeleted
+	}
+
+	review, err := s.reviewRepo.Get(comment.ReviewUserID, comment.MovieID)
+	if err != nil {
+		return nil, errors.New("failed to fetch review")
+	}
+	if review.CommentsLocked {
+		return nil, ErrCommentsLocked
+	}
+
+	now := s.now()
+	comment.Content = content
+	comment.EditedAt = &now
+	if err := s.commentRepo.Update(comment); err != nil {
+		return nil, errors.New("failed to update comment")
+	}
+
+	return s.reload(comment.ID)
+}
+
+// suppression douce : le contenu est effacé mais le commentaire reste en place
+// pour ne pas casser les fils de réponses ; possible pour l'auteur du
+// commentaire comme pour celui de la critique
+func (s *CommentService) DeleteComment(userID, commentID uint) error {
+	comment, err := s.getComment(commentID)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID != userID && comment.ReviewUserID != userID {
+		return ErrCannotDeleteComment
+	}
+	if comment.IsDeleted() {
+		return nil
+	}
+
+	now := s.now()
+	comment.Content = ""
+	comment.DeletedAt = &now
+	if err := s.commentRepo.Update(comment); err != nil {
+		return errors.New("failed to delete comment")
+	}
+	return nil
+}
+
+// verrouillage des commentaires, réservé à l'auteur de la critique
+func (s *CommentService) SetCommentsLocked(userID uint, tmdbID int, reviewUserID uint, locked bool) error {
+	if userID != reviewUserID {
+		return ErrNotReviewAuthor
+	}
+
+	review, err := s.findReview(userID, tmdbID, reviewUserID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.reviewRepo.SetCommentsLocked(review.UserID, review.MovieID, locked); err != nil {
+		return errors.New("failed to update review")
+	}
+	return nil
+}
+
+// introuvable si l'auteur de la critique et le viewer se bloquent
+func (s *CommentService) findReview(viewerID uint, tmdbID int, reviewUserID uint) (*model.Review, error) {
+	movie, err := s.movieRepo.GetMovieByTmdbID(tmdbID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, errors.New("failed to fetch movie")
+	}
+
+	review, err := s.reviewRepo.GetVisible(viewerID, reviewUserID, movie.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, errors.New("failed to fetch review")
+	}
+	return review, nil
+}
+
+func (s *CommentService) getComment(commentID uint) (*model.ReviewComment, error) {
+	comment, err := s.commentRepo.GetByID(commentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, errors.New("failed to fetch comment")
+	}
+	return comment, nil
+}
+
+func (s *CommentService) reload(commentID uint) (*dto.CommentResponse, error) {
+	comment, err := s.commentRepo.GetByIDWithAuthor(commentID)
+	if err != nil {
+		return nil, errors.New("failed to fetch comment")
+	}
+	response := toCommentResponse(comment)
+	return &response, nil
+}
+
+// auteur supprimé (soft delete) => Author non chargé, renvoyé à null
+func toCommentResponse(comment *model.ReviewComment) dto.CommentResponse {
+	response := dto.CommentResponse{
+		ID:        comment.ID,
+		ParentID:  comment.ParentID,
+		Content:   comment.Content,
+		IsEdited:  comment.EditedAt != nil,
+		IsDeleted: comment.IsDeleted(),
+		CreatedAt: comment.CreatedAt,
+	}
+
+	if comment.Author.ID != 0 && !response.IsDeleted {
+		response.Author = &dto.ReviewAuthorResponse{
+			ID:                comment.Author.ID,
+			Username:          comment.Author.Username,
+			ProfilePictureURL: comment.Author.ProfilePictureURL,
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+)
+
+// author a critiqué le film, commenter le commente
+func setupCommentServiceTest(t *testing.T) (serviceFixture, *model.User, *model.User) {
+	f := setupServiceFixture(t)
+	users := f.createUsers("author", "commenter")
+	f.db.Create(&model.Review{UserID: users[0].ID, MovieID: f.movie.ID, Content: "a dream within a dream"})
+	return f, users[0], users[1]
+}
+
+func TestCommentService_Threads(t *testing.T) {
+	f, author, commenter := setupCommentServiceTest(t)
+
+	root, err := f.comments.CreateComment(commenter.ID, f.movie.TmdbID, author.ID, dto.CreateCommentRequest{Content: "great take"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	reply, err := f.comments.CreateComment(author.ID, f.movie.TmdbID, author.ID, dto.CreateCommentRequest{Content: "thanks", ParentID: &root.ID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := f.comments.CreateComment(commenter.ID, f.movie.TmdbID, author.ID, dto.CreateCommentRequest{Content: "you're welcome", ParentID: &reply.ID}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := f.comments.CreateComment(author.ID, f.movie.TmdbID, author.ID, dto.CreateCommentRequest{Content: "second thread"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	resp, err := f.comments.GetComments(commenter.ID, f.movie.TmdbID, author.ID, 1, 20)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.Total != 2 || len(resp.Comments) != 2 {
+		t.Fatalf("expected 2 top-level comments, got total=%d len=%d", resp.Total, len(resp.Comments))
+	}
+
+	replies := resp.Comments[0].Replies
+	if len(replies) != 2 {
+		t.Fatalf("expected nested replies to be grouped under their root, got %d", len(replies))
+	}
+	if replies[1].ParentID == nil || *replies[1].ParentID != reply.ID {
+		t.Errorf("expected reply to keep its direct parent")
+	}
+	if resp.Comments[0].Author == nil || resp.Comments[0].Author.Username != "commenter" {
+		t.Errorf("expected author to be loaded, got %+v", resp.Comments[0].Author)
+	}
+
+	page2, err := f.comments.GetComments(commenter.ID, f.movie.TmdbID, author.ID, 2, 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(page2.Comments) != 1 || page2.Comments[0].Content != "second thread" || page2.TotalPages != 2 {
+		t.Errorf("unexpected second page: %+v", page2)
+	}
+}
+
+func TestCommentService_ParentFromAnotherReview(t *testing.T) {
+	f, author, commenter := setupCommentServiceTest(t)
+
+	other := &model.Movie{TmdbID: 155, Title: "The Dark Knight"}
+	f.db.Create(other)
+	f.db.Create(&model.Review{UserID: author.ID, MovieID: other.ID, Content: "why so serious"})
+
+	parent, err := f.comments.CreateComment(commenter.ID, other.TmdbID, author.ID, dto.CreateCommentRequest{Content: "agreed"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err = f.comments.CreateComment(commenter.ID, f.movie.TmdbID, author.ID, dto.CreateCommentRequest{Content: "hi", ParentID: &parent.ID})
+	if !errors.Is(err, ErrInvalidParentComment) {
+		t.Errorf("expected ErrInvalidParentComment, got %v", err)
+	}
+}
+
+func TestCommentService_EditAndDelete(t *testing.T) {
+	f, author, commenter := setupCommentServiceTest(t)
+
+	comment, _ := f.comments.CreateComment(commenter.ID, f.movie.TmdbID, author.ID, dto.CreateCommentRequest{Content: "first"})
+
+	if _, err := f.comments.UpdateComment(author.ID, comment.ID, dto.UpdateCommentRequest{Content: "hijacked"}); !errors.Is(err, ErrNotCommentAuthor) {
+		t.Errorf("expected ErrNotCommentAuthor, got %v", err)
+	}
+
+	updated, err := f.comments.UpdateComment(commenter.ID, comment.ID, dto.UpdateCommentRequest{Content: "edited"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Content != "edited" || !updated.IsEdited {
+		t.Errorf("expected edited comment, got %+v", updated)
+	}
+
+	outsider := f.createUsers("outsider")[0]
+	if err := f.comments.DeleteComment(outsider.ID, comment.ID); !errors.Is(err, ErrCannotDeleteComment) {
+		t.Errorf("expected ErrCannotDeleteComment, got %v", err)
+	}
+
+	// l'auteur de la critique peut modérer les commentaires
+	if err := f.comments.DeleteComment(author.ID, comment.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	resp, _ := f.comments.GetComments(commenter.ID, f.movie.TmdbID, author.ID, 1, 20)
+	if len(resp.Comments) != 1 {
+		t.Fatalf("expected tombstone to remain in the thread, got %d comments", len(resp.Comments))
+	}
+	tombstone := resp.Comments[0]
+	if !tombstone.IsDeleted || tombstone.Content != "" || tombstone.Author != nil {
+		t.Errorf("expected tombstone without content nor author, got %+v", tombstone)
+	}
+
+	if _, err := f.comments.CreateComment(author.ID, f.movie.TmdbID, author.ID, dto.CreateCommentRequest{Content: "reply", ParentID: &comment.ID}); !errors.Is(err, ErrCommentDeleted) {
+		t.Errorf("expected ErrCommentDeleted when replying to a tombstone, got %v", err)
+	}
+}
+
+func TestCommentService_Lock(t *testing.T) {
+	f, author, commenter := setupCommentServiceTest(t)
+
+	if err := f.comments.SetCommentsLocked(commenter.ID, f.movie.TmdbID, author.ID, true); !errors.Is(err, ErrNotReviewAuthor) {
+		t.Errorf("expected ErrNotReviewAuthor, got %v", err)
+	}
+	if err := f.comments.SetCommentsLocked(author.ID, f.movie.TmdbID, author.ID, true); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err := f.comments.CreateComment(commenter.ID, f.movie.TmdbID, author.ID, dto.CreateCommentRequest{Content: "too late"})
+	if !errors.Is(err, ErrCommentsLocked) {
+		t.Errorf("expected ErrCommentsLocked, got %v", err)
+	}
+
+	resp, _ := f.comments.GetComments(commenter.ID, f.movie.TmdbID, author.ID, 1, 20)
+	if !resp.CommentsLocked {
+		t.Error("expected comments_locked to be reported")
+	}
+
+	if err := f.comments.SetCommentsLocked(author.ID, f.movie.TmdbID, author.ID, false); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := f.comments.CreateComment(commenter.ID, f.movie.TmdbID, author.ID, dto.CreateCommentRequest{Content: "back open"}); err != nil {
+		t.Errorf("expected no error after unlock, got %v", err)
+	}
+}
+
+func TestCommentService_ReviewNotFound(t *testing.T) {
+	f, author, commenter := setupCommentServiceTest(t)
+
+	_, err := f.comments.CreateComment(author.ID, f.movie.TmdbID, commenter.ID, dto.CreateCommentRequest{Content: "hello"})
+	if !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("expected ErrReviewNotFound, got %v", err)
+	}
+}
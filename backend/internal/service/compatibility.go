@@ -0,0 +1,171 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+)
+
+var (
+	ErrCompareSelf        = errors.New("you cannot compare your taste with yourself")
+	ErrComparisonDisabled = errors.New("this user does not allow taste comparison")
+)
+
+const (
+	compatibilityMinShared    = 3   // films notés en commun en dessous desquels il n'y a pas de score
+	compatibilityShrinkage    = 5.0 // peu de films en commun : le score reste proche de 50
+	compatibilityLoved        = 4.0
+	compatibilityDisagreement = 2.0 // écart minimum entre les deux notes
+	compatibilityListSize     = 10
+)
+
+// compare les notes de viewerID et userID. userID doit avoir un profil visible,
+// des notes visibles et ne pas avoir refusé la comparaison (Comparable)
+func (s *UserService) GetTasteCompatibility(viewerID, userID uint) (*dto.TasteCompatibilityResponse, error) {
+	if viewerID == userID {
+		return nil, ErrCompareSelf
+	}
+	user, err := s.visibleProfile(viewerID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.Comparable || !user.ShowRatings {
+		return nil, ErrComparisonDisabled
+	}
+
+	shared, err := s.movieRepo.GetSharedRatings(viewerID, userID)
+	if err != nil {
+		return nil, errors.New("failed to compare ratings")
+	}
+
+	response := &dto.TasteCompatibilityResponse{
+		User: dto.ReviewAuthorResponse{
+			ID:                user.ID,
+			Username:          user.Username,
+			ProfilePictureURL: user.ProfilePictureURL,
+		},
+		SharedRatings:        len(shared),
+		Pearson:              pearsonCorrelation(shared),
+		Cosine:               ratingCosine(shared),
+		BothLoved:            []dto.CompatibilityMovie{},
+		Disagreements:        []dto.CompatibilityMovie{},
+		OnTheirWatchlist:     []dto.CompatibilityMovie{},
+		TheirWatchlistHidden: !user.ShowWatchlist,
+	}
+	if len(shared) >= compatibilityMinShared {
+		correlation := response.Pearson
+		if correlation == nil {
+			correlation = response.Cosine
+		}
+		if correlation != nil {
+			// corrélation atténuée selon le nombre de films en commun
+			n := float64(len(shared))
+			score := int(math.Round(50 + 50*(*correlation)*n/(n+compatibilityShrinkage)))
+			response.Score = &score
+		}
+	}
+
+	loved := make([]repository.SharedRating, 0)
+	disagreements := make([]repository.SharedRating, 0)
+	for _, rating := range shared {
+		if rating.Rating >= compatibilityLoved && rating.OtherRating >= compatibilityLoved {
+			loved = append(loved, rating)
+		}
+		if math.Abs(float64(rating.Rating-rating.OtherRating)) >= compatibilityDisagreement {
+			disagreements = append(disagreements, rating)
+		}
+	}
+	// les plus appréciés des deux d'abord, puis les plus grands écarts
+	sort.SliceStable(loved, func(i, j int) bool {
+		return min(loved[i].Rating, loved[i].OtherRating) > min(loved[j].Rating, loved[j].OtherRating)
+	})
+	sort.SliceStable(disagreements, func(i, j int) bool {
+		return math.Abs(float64(disagreements[i].Rating-disagreements[i].OtherRating)) >
+			math.Abs(float64(disagreements[j].Rating-disagreements[j].OtherRating))
+	})
+	for _, rating := range loved[:min(len(loved), compatibilityListSize)] {
+		response.BothLoved = append(response.BothLoved, compatibilityMovie(rating, true, true))
+	}
+	for _, rating := range disagreements[:min(len(disagreements), compatibilityListSize)] {
+		response.Disagreements = append(response.Disagreements, compatibilityMovie(rating, true, true))
+	}
+
+	theirPicks, err := s.movieRepo.GetRatedOnWatchlist(userID, viewerID, compatibilityLoved, compatibilityListSize)
+	if err != nil {
+		return nil, errors.New("failed to compare ratings")
+	}
+	response.OnYourWatchlist = make([]dto.CompatibilityMovie, 0, len(theirPicks))
+	for _, pick := range theirPicks {
+		pick.OtherRating = pick.Rating
+		response.OnYourWatchlist = append(response.OnYourWatchlist, compatibilityMovie(pick, false, true))
+	}
+
+	if user.ShowWatchlist {
+		yourPicks, err := s.movieRepo.GetRatedOnWatchlist(viewerID, userID, compatibilityLoved, compatibilityListSize)
+		if err != nil {
+			return nil, errors.New("failed to compare ratings")
+		}
+		for _, pick := range yourPicks {
+			response.OnTheirWatchlist = append(response.OnTheirWatchlist, compatibilityMovie(pick, true, false))
+		}
+	}
+	return response, nil
+}
+
+// Rating : note du viewer, OtherRating : celle de l'autre membre
+func compatibilityMovie(rating repository.SharedRating, yours, theirs bool) dto.CompatibilityMovie {
+	movie := dto.CompatibilityMovie{
+		TmdbID:      rating.TmdbID,
+		Title:       rating.Title,
+		ReleaseYear: rating.ReleaseYear,
+		PosterURL:   rating.PosterURL,
+	}
+	if yours {
+		movie.YourRating = &rating.Rating
+	}
+	if theirs {
+		movie.TheirRating = &rating.OtherRating
+	}
+	return movie
+}
+
+// corrélation de Pearson ; nil si moins de deux films ou si l'un note tout pareil
+func pearsonCorrelation(shared []repository.SharedRating) *float64 {
+	if len(shared) < 2 {
+		return nil
+	}
+	var sumA, sumB float64
+	for _, rating := range shared {
+		sumA += float64(rating.Rating)
+		sumB += float64(rating.OtherRating)
+	}
+	meanA, meanB := sumA/float64(len(shared)), sumB/float64(len(shared))
+	return centeredCosine(shared, meanA, meanB)
+}
+
+// similarité cosinus des notes centrées sur le milieu de l'échelle (2,5) :
+// deux notes du même côté rapprochent, quelle que soit la moyenne de chacun
+func ratingCosine(shared []repository.SharedRating) *float64 {
+	if len(shared) == 0 {
+		return nil
+	}
+	return centeredCosine(shared, 2.5, 2.5)
+}
+
+func centeredCosine(shared []repository.SharedRating, centerA, centerB float64) *float64 {
+	var dot, normA, normB float64
+	for _, rating := range shared {
+		a, b := float64(rating.Rating)-centerA, float64(rating.OtherRating)-centerB
+		dot += a * b
+		normA += a * a
+		normB += b * b
+	}
+	if normA == 0 || normB == 0 {
+		return nil
+	}
+	value := math.Round(dot/math.Sqrt(normA*normB)*1000) / 1000
+	return &value
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+)
+
+func TestUserService_TasteCompatibility(t *testing.T) {
+	f := setupServiceFixture(t)
+	users := f.createUsers("owner", "follower", "stranger")
+	owner, follower, stranger := users[0], users[1], users[2]
+
+	movie := func(tmdbID int, title string) *model.Movie {
+		m := &model.Movie{TmdbID: tmdbID, Title: title}
+		f.db.Create(m)
+		return m
+	}
+	heat := movie(949, "Heat")
+	cats := movie(2454, "Cats")
+	brazil := movie(68, "Brazil")
+	alien := movie(348, "Alien")
+	matrix := movie(603, "The Matrix")
+	rate := func(user *model.User, m *model.Movie, rating float32) {
+		f.db.Create(&model.Rate{UserID: user.ID, MovieID: m.ID, Rating: rating})
+	}
+	rate(owner, f.movie, 4.5)
+	rate(owner, heat, 5)
+	rate(owner, cats, 2)
+	rate(owner, brazil, 1)
+	rate(owner, alien, 4)
+	rate(stranger, f.movie, 5)
+	rate(stranger, heat, 4.5)
+	rate(stranger, cats, 1.5)
+	rate(stranger, brazil, 4.5)
+	rate(stranger, matrix, 5)
+	f.db.Create(&model.Track{UserID: stranger.ID, MovieID: alien.ID, IsWatchlist: true})
+	f.db.Create(&model.Track{UserID: owner.ID, MovieID: matrix.ID, IsWatchlist: true})
+
+	response, err := f.users.GetTasteCompatibility(stranger.ID, owner.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if response.SharedRatings != 4 || response.Pearson == nil || *response.Pearson != 0.438 {
+		t.Errorf("expected pearson 0.438 over 4 ratings, got %d %v", response.SharedRatings, response.Pearson)
+	}
+	if response.Score == nil || *response.Score != 60 {
+		t.Errorf("expected score 60, got %v", response.Score)
+	}
+	if len(response.BothLoved) != 2 || response.BothLoved[0].TmdbID != f.movie.TmdbID || response.BothLoved[1].TmdbID != heat.TmdbID {
+		t.Errorf("expected Inception and Heat both loved, got %+v", response.BothLoved)
+	}
+	if len(response.Disagreements) != 1 || response.Disagreements[0].TmdbID != brazil.TmdbID ||
+		*response.Disagreements[0].YourRating != 4.5 || *response.Disagreements[0].TheirRating != 1 {
+		t.Errorf("expected Brazil as the only disagreement, got %+v", response.Disagreements)
+	}
+	if len(response.OnYourWatchlist) != 1 || response.OnYourWatchlist[0].TmdbID != alien.TmdbID || response.OnYourWatchlist[0].YourRating != nil {
+		t.Errorf("expected Alien on the viewer's watchlist, got %+v", response.OnYourWatchlist)
+	}
+	if len(response.OnTheirWatchlist) != 1 || response.OnTheirWatchlist[0].TmdbID != matrix.TmdbID || *response.OnTheirWatchlist[0].YourRating != 5 {
+		t.Errorf("expected The Matrix on the owner's watchlist, got %+v", response.OnTheirWatchlist)
+	}
+
+	// trop peu de films en commun : pas de score
+	rate(follower, f.movie, 4)
+	rate(follower, heat, 3)
+	response, err = f.users.GetTasteCompatibility(follower.ID, owner.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if response.SharedRatings != 2 || response.Score != nil {
+		t.Errorf("expected no score with 2 shared ratings, got %+v", response)
+	}
+
+	if _, err := f.users.GetTasteCompatibility(owner.ID, owner.ID); !errors.Is(err, ErrCompareSelf) {
+		t.Errorf("expected ErrCompareSelf, got %v", err)
+	}
+
+	hidden := false
+	updatePrivacy(t, f, owner.ID, dto.UpdatePrivacySettingsRequest{ShowWatchlist: &hidden})
+	response, err = f.users.GetTasteCompatibility(stranger.ID, owner.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !response.TheirWatchlistHidden || len(response.OnTheirWatchlist) != 0 || len(response.OnYourWatchlist) != 1 {
+		t.Errorf("expected the owner's watchlist hidden, got %+v", response)
+	}
+
+	updatePrivacy(t, f, owner.ID, dto.UpdatePrivacySettingsRequest{Comparable: &hidden})
+	if _, err := f.users.GetTasteCompatibility(stranger.ID, owner.ID); !errors.Is(err, ErrComparisonDisabled) {
+		t.Errorf("expected ErrComparisonDisabled when opted out, got %v", err)
+	}
+	shown := true
+	updatePrivacy(t, f, owner.ID, dto.UpdatePrivacySettingsRequest{Comparable: &shown, ShowRatings: &hidden})
+	if _, err := f.users.GetTasteCompatibility(stranger.ID, owner.ID); !errors.Is(err, ErrComparisonDisabled) {
+		t.Errorf("expected ErrComparisonDisabled with hidden ratings, got %v", err)
+	}
+
+	private := model.ProfileVisibilityPrivate
+	updatePrivacy(t, f, owner.ID, dto.UpdatePrivacySettingsRequest{ShowRatings: &shown, ProfileVisibility: &private})
+	if _, err := f.users.GetTasteCompatibility(stranger.ID, owner.ID); !errors.Is(err, ErrProfilePrivate) {
+		t.Errorf("expected ErrProfilePrivate, got %v", err)
+	}
+}
@@ -0,0 +1,387 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// contenus soumis à la politique
+const (
+	ContentFieldUsername = "username"
+	ContentFieldBio      = "bio"
+	ContentFieldReview   = "review"
+)
+
+type PolicyVerdict int
+
+// du plus permissif au plus strict : le verdict final est le plus strict des règles
+const (
+	PolicyAllow PolicyVerdict = iota
+	PolicyFlag                // publié, mais envoyé en file de modération
+	PolicyBlock               // refusé
+)
+
+func (v PolicyVerdict) String() string {
+	switch v {
+	case PolicyFlag:
+		return "flag"
+	case PolicyBlock:
+		return "block"
+	default:
+		return "allow"
+	}
+}
+
+var ErrContentRejected = errors.New("content rejected by content policy")
+
+// refus détaillé ; errors.Is(err, ErrContentRejected) reste vrai
+type ContentViolationError struct {
+	Field string
+	Rules []string
+}
+
+func (e *ContentViolationError) Error() string {
+	return fmt.Sprintf("%s rejected by content policy (%s)", e.Field, strings.Join(e.Rules, ", "))
+}
+
+func (e *ContentViolationError) Unwrap() error {
+	return ErrContentRejected
+}
+
+// une règle de la politique ; field est l'un des ContentField*
+type ContentRule interface {
+	Name() string
+	Check(field, text string) PolicyVerdict
+}
+
+type PolicyResult struct {
+	Verdict PolicyVerdict
+	Rules   []string // règles déclenchées
+}
+
+// contenu signalé automatiquement
+type ContentTarget struct {
+	Type     string // model.ReportTarget*
+	UserID   uint
+	MovieID  *uint
+	Snapshot string
+}
+
+// reçoit le contenu signalé par la politique (file de modération)
+type ContentFlagger interface {
+	FlagContent(target ContentTarget, rules []string)
+}
+
+type ContentPolicy struct {
+	rules   []ContentRule
+	flagger ContentFlagger
+}
+
+func NewContentPolicy(rules ...ContentRule) *ContentPolicy {
+	return &ContentPolicy{rules: rules}
+}
+
+// règles par défaut, listes complétées par l'environnement (valeurs séparées
+// par des virgules) : CONTENT_BLOCKED_WORDS, CONTENT_FLAGGED_WORDS,
+// CONTENT_RESERVED_USERNAMES
+func NewContentPolicyFromEnv() *ContentPolicy {
+	return NewContentPolicy(
+		NewReservedUsernameRule(append(defaultReservedUsernames, envList("CONTENT_RESERVED_USERNAMES")...), defaultProtectedTerms),
+		NewWordListRule("blocked_words", envList("CONTENT_BLOCKED_WORDS"), PolicyBlock),
+		NewWordListRule("flagged_words", append(defaultSpamPhrases, envList("CONTENT_FLAGGED_WORDS")...), PolicyFlag),
+		NewLinkRule(map[string]int{ContentFieldBio: 1, ContentFieldReview: 3}),
+		NewCapsRule(),
+		NewRepeatedTextRule(),
+	)
+}
+
+func (p *ContentPolicy) AddRule(rule ContentRule) {
+	p.rules = append(p.rules, rule)
+}
+
+func (p *ContentPolicy) SetFlagger(flagger ContentFlagger) {
+	p.flagger = flagger
+}
+
+// évalue text ; si le verdict est block, renvoie une *ContentViolationError.
+// une *ContentPolicy nil accepte tout
+func (p *ContentPolicy) Check(field, text string) (PolicyResult, error) {
+	result := PolicyResult{Verdict: PolicyAllow}
+	if p == nil || strings.TrimSpace(text) == "" {
+		return result, nil
+	}
+
+	var blocking []string
+	for _, rule := range p.rules {
+		verdict := rule.Check(field, text)
+		if verdict == PolicyAllow {
+			continue
+		}
+		result.Rules = append(result.Rules, rule.Name())
+		if verdict == PolicyBlock {
+			blocking = append(blocking, rule.Name())
+		}
+		if verdict > result.Verdict {
+			result.Verdict = verdict
+		}
+	}
+
+	if result.Verdict == PolicyBlock {
+		return result, &ContentViolationError{Field: field, Rules: blocking}
+	}
+	return result, nil
+}
+
+// à appeler une fois le contenu enregistré : envoie en modération ce qui a
+// été marqué flag, ignore le reste
+func (p *ContentPolicy) Flag(result PolicyResult, target ContentTarget) {
+	if p == nil || p.flagger == nil || result.Verdict != PolicyFlag {
+		return
+	}
+	p.flagger.FlagContent(target, result.Rules)
+}
+
+// RÈGLES
+
+var defaultReservedUsernames = []string{
+	"admin", "administrator", "root", "system", "moderator", "mod", "staff", "support",
+	"help", "security", "official", "framerate", "api", "me", "settings", "null", "undefined",
+}
+
+// interdits aussi en début ou fin de nom (usurpation : "framerate_team", "the-admin") ;
+// pas au milieu, pour ne pas bloquer "badminton"
+var defaultProtectedTerms = []string{"admin", "moderator", "framerate"}
+
+var defaultSpamPhrases = []string{
+	"buy followers", "free followers", "click here", "promo code", "crypto giveaway", "dm for promo",
+}
+
+type reservedUsernameRule struct {
+	reserved  map[string]bool
+	protected []string
+}
+
+// bloque les noms réservés et ceux qui commencent ou finissent par un terme
+// protégé (comparés en minuscules, sans _ ni -, chiffres déguisés remplacés)
+func NewReservedUsernameRule(reserved, protected []string) ContentRule {
+	rule := &reservedUsernameRule{reserved: make(map[string]bool, len(reserved))}
+	for _, name := range reserved {
+		rule.reserved[normalizeUsername(name)] = true
+	}
+	for _, term := range protected {
+		rule.protected = append(rule.protected, normalizeUsername(term))
+	}
+	return rule
+}
+
+func (r *reservedUsernameRule) Name() string { return "reserved_username" }
+
+func (r *reservedUsernameRule) Check(field, text string) PolicyVerdict {
+	if field != ContentFieldUsername {
+		return PolicyAllow
+	}
+	name := normalizeUsername(text)
+	if r.reserved[name] {
+		return PolicyBlock
+	}
+	for _, term := range r.protected {
+		if strings.HasPrefix(name, term) || strings.HasSuffix(name, term) {
+			return PolicyBlock
+		}
+	}
+	return PolicyAllow
+}
+
+type wordListRule struct {
+	name    string
+	words   []string
+	verdict PolicyVerdict
+}
+
+// mots ou expressions, insensibles à la casse ; dans un nom d'utilisateur,
+// recherchés aussi à l'intérieur du nom
+func NewWordListRule(name string, words []string, verdict PolicyVerdict) ContentRule {
+	rule := &wordListRule{name: name, verdict: verdict}
+	for _, word := range words {
+		if normalized := strings.Join(tokenize(word), " "); normalized != "" {
+			rule.words = append(rule.words, normalized)
+		}
+	}
+	return rule
+}
+
+func (r *wordListRule) Name() string { return r.name }
+
+func (r *wordListRule) Check(field, text string) PolicyVerdict {
+	if len(r.words) == 0 {
+		return PolicyAllow
+	}
+
+	if field == ContentFieldUsername {
+		name := normalizeUsername(text)
+		for _, word := range r.words {
+			if strings.Contains(name, normalizeUsername(strings.ReplaceAll(word, " ", ""))) {
+				return r.verdict
+			}
+		}
+		return PolicyAllow
+	}
+
+	// bornes d'espaces : seuls des mots entiers correspondent
+	padded := " " + strings.Join(tokenize(text), " ") + " "
+	for _, word := range r.words {
+		if strings.Contains(padded, " "+word+" ") {
+			return r.verdict
+		}
+	}
+	return PolicyAllow
+}
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+|\b[a-z0-9-]+\.(?:com|net|org|io|ru|xyz|info|biz|co|me|ly|gg|link|shop)\b`)
+
+type linkRule struct {
+	limits map[string]int
+}
+
+// signale un contenu qui contient plus de liens que la limite de son champ
+// (champ absent : règle ignorée)
+func NewLinkRule(limits map[string]int) ContentRule {
+	return &linkRule{limits: limits}
+}
+
+func (r *linkRule) Name() string { return "links" }
+
+func (r *linkRule) Check(field, text string) PolicyVerdict {
+	limit, ok := r.limits[field]
+	if !ok {
+		return PolicyAllow
+	}
+	if len(linkPattern.FindAllStringIndex(text, -1)) > limit {
+		return PolicyFlag
+	}
+	return PolicyAllow
+}
+
+const (
+	capsMinLetters = 20
+	capsMaxRatio   = 0.7
+)
+
+type capsRule struct{}
+
+// signale un texte écrit en majuscules (au-delà de capsMinLetters lettres)
+func NewCapsRule() ContentRule {
+	return capsRule{}
+}
+
+func (capsRule) Name() string { return "excessive_caps" }
+
+func (capsRule) Check(field, text string) PolicyVerdict {
+	if field == ContentFieldUsername {
+		return PolicyAllow
+	}
+	letters, upper := 0, 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	if letters >= capsMinLetters && float64(upper)/float64(letters) > capsMaxRatio {
+		return PolicyFlag
+	}
+	return PolicyAllow
+}
+
+const (
+	repeatedCharRun   = 10   // "!!!!!!!!!!", "aaaaaaaaaa"
+	repeatedWordRun   = 5    // même mot 5 fois de suite
+	repeatedMinWords  = 30   // en dessous, pas de mesure de diversité
+	repeatedMinUnique = 0.25 // part minimale de mots distincts
+)
+
+type repeatedTextRule struct{}
+
+// signale le texte de remplissage : caractère ou mot répété en boucle,
+// texte long fait de quelques mots
+func NewRepeatedTextRule() ContentRule {
+	return repeatedTextRule{}
+}
+
+func (repeatedTextRule) Name() string { return "repeated_text" }
+
+func (repeatedTextRule) Check(field, text string) PolicyVerdict {
+	if field == ContentFieldUsername {
+		return PolicyAllow
+	}
+
+	run, last := 0, rune(0)
+	for _, r := range text {
+		if r == last && !unicode.IsSpace(r) {
+			run++
+		} else {
+			run, last = 1, r
+		}
+		if run >= repeatedCharRun {
+			return PolicyFlag
+		}
+	}
+
+	words := tokenize(text)
+	run = 0
+	for i := range words {
+		if i > 0 && words[i] == words[i-1] {
+			run++
+		} else {
+			run = 1
+		}
+		if run >= repeatedWordRun {
+			return PolicyFlag
+		}
+	}
+
+	if len(words) >= repeatedMinWords {
+		unique := make(map[string]bool, len(words))
+		for _, word := range words {
+			unique[word] = true
+		}
+		if float64(len(unique))/float64(len(words)) < repeatedMinUnique {
+			return PolicyFlag
+		}
+	}
+	return PolicyAllow
+}
+
+// OUTILS
+
+// chiffres et symboles courants utilisés pour contourner les listes
+var leetReplacer = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s")
+
+// mots en minuscules, sans ponctuation
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func normalizeUsername(name string) string {
+	name = strings.ToLower(name)
+	name = strings.NewReplacer("_", "", "-", "", ".", "").Replace(name)
+	return leetReplacer.Replace(name)
+}
+
+func envList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+)
+
+func TestContentPolicy_Check(t *testing.T) {
+	policy := NewContentPolicy(
+		NewReservedUsernameRule(defaultReservedUsernames, defaultProtectedTerms),
+		NewWordListRule("blocked_words", []string{"slur"}, PolicyBlock),
+		NewWordListRule("flagged_words", defaultSpamPhrases, PolicyFlag),
+		NewLinkRule(map[string]int{ContentFieldBio: 1, ContentFieldReview: 3}),
+		NewCapsRule(),
+		NewRepeatedTextRule(),
+	)
+
+	tests := []struct {
+		name    string
+		field   string
+		text    string
+		verdict PolicyVerdict
+	}{
+		{"regular username", ContentFieldUsername, "cinephile_42", PolicyAllow},
+		{"reserved username", ContentFieldUsername, "Admin", PolicyBlock},
+		{"disguised reserved username", ContentFieldUsername, "m0derator", PolicyBlock},
+		{"protected prefix", ContentFieldUsername, "framerate_team", PolicyBlock},
+		{"protected term inside a word", ContentFieldUsername, "badminton_fan", PolicyAllow},
+		{"blocked word in username", ContentFieldUsername, "xx_SLUR_xx", PolicyBlock},
+		{"blocked word", ContentFieldReview, "what a slur.", PolicyBlock},
+		{"blocked word inside another word", ContentFieldReview, "slurp", PolicyAllow},
+		{"spam phrase", ContentFieldBio, "Click   HERE for more", PolicyFlag},
+		{"one link in bio", ContentFieldBio, "my blog: https://example.com", PolicyAllow},
+		{"links in bio", ContentFieldBio, "example.com and www.other.net", PolicyFlag},
+		{"caps", ContentFieldReview, "THIS MOVIE IS THE WORST THING EVER MADE", PolicyFlag},
+		{"short caps", ContentFieldReview, "WOW", PolicyAllow},
+		{"repeated characters", ContentFieldReview, "so good!!!!!!!!!!", PolicyFlag},
+		{"repeated word", ContentFieldReview, "boring boring boring boring boring", PolicyFlag},
+		{"regular review", ContentFieldReview, "A slow burn, but the last act is worth it.", PolicyAllow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := policy.Check(tt.field, tt.text)
+			if result.Verdict != tt.verdict {
+				t.Errorf("expected %s, got %s (rules %v)", tt.verdict, result.Verdict, result.Rules)
+			}
+			if (err != nil) != (tt.verdict == PolicyBlock) {
+				t.Errorf("unexpected error for verdict %s: %v", result.Verdict, err)
+			}
+			if err != nil && !errors.Is(err, ErrContentRejected) {
+				t.Errorf("expected ErrContentRejected, got %v", err)
+			}
+		})
+	}
+
+	var nilPolicy *ContentPolicy
+	if result, err := nilPolicy.Check(ContentFieldUsername, "admin"); err != nil || result.Verdict != PolicyAllow {
+		t.Errorf("expected nil policy to allow everything, got %v, %v", result, err)
+	}
+}
+
+func TestContentPolicy_AppliedToReviews(t *testing.T) {
+	f, _, reporter, _ := setupModerationServiceTest(t)
+	policy := NewContentPolicy(NewWordListRule("blocked_words", []string{"slur"}, PolicyBlock), NewCapsRule())
+	policy.SetFlagger(f.moderation)
+	f.movies.SetContentPolicy(policy)
+
+	blocked := "what a slur"
+	err := f.movies.LogMovie(reporter.ID, f.movie.TmdbID, dto.LogMovieRequest{ReviewText: &blocked})
+	var violation *ContentViolationError
+	if !errors.As(err, &violation) || violation.Field != ContentFieldReview {
+		t.Fatalf("expected a review violation, got %v", err)
+	}
+	var tracks int64
+	f.db.Model(&model.Track{}).Where("user_id = ?", reporter.ID).Count(&tracks)
+	if tracks != 0 {
+		t.Error("expected nothing to be saved when the review is rejected")
+	}
+
+	shouting := strings.ToUpper("this movie is the worst thing ever made")
+	for i := 0; i < 2; i++ {
+		if err := f.movies.LogMovie(reporter.ID, f.movie.TmdbID, dto.LogMovieRequest{ReviewText: &shouting}); err != nil {
+			t.Fatalf("expected flagged review to be saved, got %v", err)
+		}
+	}
+
+	queue, err := f.moderation.GetReports(repository.ReportFilter{Reason: model.ReportReasonContentPolicy}, 1, 20)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if queue.Total != 1 {
+		t.Fatalf("expected a single automatic report, got %d", queue.Total)
+	}
+	report := queue.Reports[0]
+	if report.Reporter != nil || report.TargetUser.ID != reporter.ID || report.Snapshot != shouting {
+		t.Errorf("unexpected automatic report: %+v", report)
+	}
+}
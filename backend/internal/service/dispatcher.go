@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+type dispatchTask struct {
+	name string
+	run  func() error
+}
+
+// file de tâches traitées par un pool de workers, hors du chemin de la requête
+// (envoi d'emails, fan-out des notifications...)
+type Dispatcher struct {
+	tasks   chan dispatchTask
+	workers int
+	wg      sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func NewDispatcher(workers, queueSize int) *Dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Dispatcher{
+		tasks:   make(chan dispatchTask, queueSize),
+		workers: workers,
+	}
+}
+
+func (d *Dispatcher) Start() {
+	for i := 0; i < d.workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+}
+
+// ajoute une tâche à la file sans jamais bloquer l'appelant : file pleine ou
+// dispatcher arrêté => la tâche est abandonnée et loggée (retourne false).
+// un *Dispatcher nil (tests, services non câblés) exécute la tâche immédiatement
+func (d *Dispatcher) Dispatch(name string, run func() error) bool {
+	if d == nil {
+		runTask(dispatchTask{name: name, run: run})
+		return true
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		utils.Log.Warn("Dispatcher stopped, task dropped", zap.String("task", name))
+		return false
+	}
+
+	select {
+	case d.tasks <- dispatchTask{name: name, run: run}:
+		return true
+	default:
+		utils.Log.Error("Dispatcher queue full, task dropped", zap.String("task", name))
+		return false
+	}
+}
+
+// ferme la file et attend la fin des tâches déjà acceptées (ou l'expiration de ctx)
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.tasks)
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("dispatcher stop: %w", ctx.Err())
+	}
+}
+
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for task := range d.tasks {
+		runTask(task)
+	}
+}
+
+// une tâche en échec (ou qui panique) est loggée sans arrêter le worker
+func runTask(task dispatchTask) {
+	defer func() {
+		if r := recover(); r != nil {
+			utils.Log.Error("Dispatched task panicked",
+				zap.String("task", task.name),
+				zap.Any("panic", r),
+			)
+		}
+	}()
+
+	if err := task.run(); err != nil {
+		utils.Log.Error("Dispatched task failed",
+			zap.String("task", task.name),
+			zap.Error(err),
+		)
+	}
+}
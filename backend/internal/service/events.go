@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// types d'événements temps réel
+const (
+	EventInteractionUpdated = "interaction.updated"
+	EventProfileUpdated     = "profile.updated"
+	// des événements ont pu être perdus : le client doit recharger son état
+	EventResync = "resync"
+)
+
+const (
+	eventChannel      = "events"       // canal pub/sub partagé par les réplicas
+	eventStreamPrefix = "events:user:" // historique par utilisateur (stream Redis)
+	eventHistorySize  = 100
+	eventHistoryTTL   = time.Hour
+	eventPublishTTL   = 2 * time.Second
+	subscriberBuffer  = 64
+)
+
+var ErrEventBrokerClosed = errors.New("event stream is shutting down")
+
+// diffuse les événements d'un utilisateur vers ses connexions SSE.
+// avec Redis : historique dans un stream par utilisateur (reprise via
+// Last-Event-ID) et fan-out pub/sub entre réplicas ; sans Redis, historique
+// et diffusion en mémoire (instance unique)
+type EventBroker struct {
+	rdb *redis.Client
+
+	mu          sync.Mutex
+	subscribers map[uint]map[*Subscription]struct{}
+	closed      bool
+
+	// mode mémoire uniquement
+	history map[uint][]dto.StreamEvent
+	lastID  eventID
+}
+
+func NewEventBroker(rdb *redis.Client) *EventBroker {
+	return &EventBroker{
+		rdb:         rdb,
+		subscribers: make(map[uint]map[*Subscription]struct{}),
+		history:     make(map[uint][]dto.StreamEvent),
+	}
+}
+
+// connexion SSE d'un utilisateur
+type Subscription struct {
+	broker *EventBroker
+	userID uint
+	events chan dto.StreamEvent
+	done   chan struct{}
+	once   sync.Once
+}
+
+func (s *Subscription) Events() <-chan dto.StreamEvent {
+	return s.events
+}
+
+// fermé quand l'abonnement se termine (client trop lent, arrêt du serveur)
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	s.broker.remove(s)
+	s.broker.mu.Unlock()
+}
+
+// écoute le canal pub/sub jusqu'à l'annulation de ctx ; sans Redis, ne fait rien
+func (b *EventBroker) Run(ctx context.Context) {
+	if b.rdb == nil {
+		return
+	}
+
+	pubsub := b.rdb.Subscribe(ctx, eventChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			var envelope eventEnvelope
+			if err := json.Unmarshal([]byte(message.Payload), &envelope); err != nil {
+				utils.Log.Warn("Invalid event on pub/sub channel", zap.Error(err))
+				continue
+			}
+			b.deliver(envelope.UserID, envelope.Event)
+		}
+	}
+}
+
+type eventEnvelope struct {
+	UserID uint            `json:"user_id"`
+	Event  dto.StreamEvent `json:"event"`
+}
+
+// publie un événement pour toutes les connexions de userID ; un échec est
+// loggé sans bloquer l'appelant. un *EventBroker nil ne publie rien
+func (b *EventBroker) Publish(userID uint, eventType string, data interface{}) {
+	if b == nil {
+		return
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		utils.Log.Error("Failed to encode event", zap.String("type", eventType), zap.Error(err))
+		return
+	}
+	event := dto.StreamEvent{Type: eventType, Data: encoded}
+
+	if b.rdb == nil {
+		b.mu.Lock()
+		b.lastID = b.lastID.next(time.Now())
+		event.ID = b.lastID.String()
+		history := append(b.history[userID], event)
+		if len(history) > eventHistorySize {
+			history = history[len(history)-eventHistorySize:]
+		}
+		b.history[userID] = history
+		b.mu.Unlock()
+
+		b.deliver(userID, event)
+		return
+	}
+
+	if err := b.publishRedis(userID, event); err != nil {
+		utils.Log.Error("Failed to publish event",
+			zap.Uint("user_id", userID),
+			zap.String("type", eventType),
+			zap.Error(err),
+		)
+	}
+}
+
+func (b *EventBroker) publishRedis(userID uint, event dto.StreamEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), eventPublishTTL)
+	defer cancel()
+
+	key := eventStreamKey(userID)
+	id, err := b.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: eventHistorySize,
+		Approx: true,
+		Values: map[string]interface{}{"type": event.Type, "data": string(event.Data)},
+	}).Result()
+	if err != nil {
+		return err
+	}
+	b.rdb.Expire(ctx, key, eventHistoryTTL)
+
+	event.ID = id
+	envelope, err := json.Marshal(eventEnvelope{UserID: userID, Event: event})
+	if err != nil {
+		return err
+	}
+	return b.rdb.Publish(ctx, eventChannel, envelope).Err()
+}
+
+// ouvre un abonnement ; avec lastEventID, renvoie aussi les événements
+// manqués depuis (précédés d'un resync si l'historique ne remonte pas assez loin)
+func (b *EventBroker) Subscribe(userID uint, lastEventID string) (*Subscription, []dto.StreamEvent, error) {
+	sub := &Subscription{
+		broker: b,
+		userID: userID,
+		events: make(chan dto.StreamEvent, subscriberBuffer),
+		done:   make(chan struct{}),
+	}
+
+	// inscrit avant de lire l'historique : rien ne se perd entre les deux,
+	// les doublons éventuels sont écartés avec IsNewerEvent
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil, nil, ErrEventBrokerClosed
+	}
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[*Subscription]struct{})
+	}
+	b.subscribers[userID][sub] = struct{}{}
+	b.mu.Unlock()
+
+	if lastEventID == "" {
+		return sub, nil, nil
+	}
+
+	backlog, err := b.since(userID, lastEventID)
+	if err != nil {
+		utils.Log.Warn("Failed to replay missed events", zap.Uint("user_id", userID), zap.Error(err))
+		backlog = []dto.StreamEvent{resyncEvent()}
+	}
+	return sub, backlog, nil
+}
+
+// ferme tous les abonnements (arrêt du serveur)
+func (b *EventBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for _, subs := range b.subscribers {
+		for sub := range subs {
+			b.remove(sub)
+		}
+	}
+}
+
+func (b *EventBroker) since(userID uint, lastEventID string) ([]dto.StreamEvent, error) {
+	last, ok := parseEventID(lastEventID)
+	if !ok {
+		return []dto.StreamEvent{resyncEvent()}, nil
+	}
+
+	var retained []dto.StreamEvent
+	if b.rdb == nil {
+		b.mu.Lock()
+		retained = append(retained, b.history[userID]...)
+		b.mu.Unlock()
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), eventPublishTTL)
+		defer cancel()
+
+		messages, err := b.rdb.XRange(ctx, eventStreamKey(userID), "-", "+").Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, message := range messages {
+			eventType, _ := message.Values["type"].(string)
+			data, _ := message.Values["data"].(string)
+			retained = append(retained, dto.StreamEvent{ID: message.ID, Type: eventType, Data: json.RawMessage(data)})
+		}
+	}
+
+	// historique vide ou qui commence après lastEventID : trou possible
+	var backlog []dto.StreamEvent
+	if len(retained) == 0 || last.before(mustParseEventID(retained[0].ID)) {
+		backlog = append(backlog, resyncEvent())
+	}
+	for _, event := range retained {
+		if last.before(mustParseEventID(event.ID)) {
+			backlog = append(backlog, event)
+		}
+	}
+	return backlog, nil
+}
+
+func (b *EventBroker) deliver(userID uint, event dto.StreamEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers[userID] {
+		select {
+		case sub.events <- event:
+		default:
+			// client trop lent : on coupe, il se reconnectera avec Last-Event-ID
+			b.remove(sub)
+		}
+	}
+}
+
+// à appeler avec b.mu verrouillé
+func (b *EventBroker) remove(sub *Subscription) {
+	if subs := b.subscribers[sub.userID]; subs != nil {
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(b.subscribers, sub.userID)
+		}
+	}
+	sub.once.Do(func() { close(sub.done) })
+}
+
+func eventStreamKey(userID uint) string {
+	return eventStreamPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+func resyncEvent() dto.StreamEvent {
+	return dto.StreamEvent{Type: EventResync, Data: json.RawMessage("{}")}
+}
+
+// IsNewerEvent indique si l'id a est postérieur à b (ids au format "<ms>-<seq>")
+func IsNewerEvent(a, b string) bool {
+	idA, okA := parseEventID(a)
+	idB, okB := parseEventID(b)
+	if !okA || !okB {
+		return true
+	}
+	return idB.before(idA)
+}
+
+// même format que les ids de stream Redis
+type eventID struct {
+	ms  uint64
+	seq uint64
+}
+
+func (id eventID) String() string {
+	return fmt.Sprintf("%d-%d", id.ms, id.seq)
+}
+
+func (id eventID) before(other eventID) bool {
+	return id.ms < other.ms || (id.ms == other.ms && id.seq < other.seq)
+}
+
+// id suivant, strictement croissant même si l'horloge recule
+func (id eventID) next(now time.Time) eventID {
+	ms := uint64(now.UnixMilli())
+	if ms <= id.ms {
+		return eventID{ms: id.ms, seq: id.seq + 1}
+	}
+	return eventID{ms: ms}
+}
+
+func parseEventID(raw string) (eventID, bool) {
+	msPart, seqPart, found := strings.Cut(raw, "-")
+	if !found {
+		return eventID{}, false
+	}
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return eventID{}, false
+	}
+	seq, err := strconv.ParseUint(seqPart, 10, 64)
+	if err != nil {
+		return eventID{}, false
+	}
+	return eventID{ms: ms, seq: seq}, true
+}
+
+func mustParseEventID(raw string) eventID {
+	id, _ := parseEventID(raw)
+	return id
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+func receiveEvent(t *testing.T, sub *Subscription) dto.StreamEvent {
+	t.Helper()
+	select {
+	case event := <-sub.Events():
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("expected an event")
+		return dto.StreamEvent{}
+	}
+}
+
+func TestEventBroker_LiveDeliveryPerUser(t *testing.T) {
+	utils.Log = zap.NewNop()
+	broker := NewEventBroker(nil)
+
+	phone, _, _ := broker.Subscribe(1, "")
+	laptop, _, _ := broker.Subscribe(1, "")
+	stranger, _, _ := broker.Subscribe(2, "")
+	defer phone.Close()
+	defer laptop.Close()
+	defer stranger.Close()
+
+	broker.Publish(1, EventProfileUpdated, map[string]string{"username": "neo"})
+
+	for _, sub := range []*Subscription{phone, laptop} {
+		event := receiveEvent(t, sub)
+		if event.Type != EventProfileUpdated || string(event.Data) != `{"username":"neo"}` || event.ID == "" {
+			t.Errorf("unexpected event: %+v", event)
+		}
+	}
+	select {
+	case event := <-stranger.Events():
+		t.Errorf("expected other users not to receive the event, got %+v", event)
+	default:
+	}
+}
+
+func TestEventBroker_ResumeFromLastEventID(t *testing.T) {
+	utils.Log = zap.NewNop()
+	broker := NewEventBroker(nil)
+
+	for i := 0; i < 3; i++ {
+		broker.Publish(1, EventInteractionUpdated, map[string]int{"n": i})
+	}
+	_, all, _ := broker.Subscribe(1, "0-0")
+	if len(all) != 4 || all[0].Type != EventResync {
+		t.Fatalf("expected resync then 3 events for an id older than the history, got %+v", all)
+	}
+	if !IsNewerEvent(all[2].ID, all[1].ID) || !IsNewerEvent(all[3].ID, all[2].ID) {
+		t.Errorf("expected strictly increasing ids, got %s %s %s", all[1].ID, all[2].ID, all[3].ID)
+	}
+
+	// reprise après le premier événement : seuls les deux suivants sont rejoués
+	sub, backlog, err := broker.Subscribe(1, all[1].ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer sub.Close()
+	if len(backlog) != 2 || backlog[0].ID != all[2].ID || backlog[1].ID != all[3].ID {
+		t.Errorf("expected the two missed events, got %+v", backlog)
+	}
+
+	// déjà à jour : rien à rejouer
+	if _, backlog, _ := broker.Subscribe(1, all[3].ID); len(backlog) != 0 {
+		t.Errorf("expected no backlog, got %+v", backlog)
+	}
+	if _, backlog, _ := broker.Subscribe(1, "garbage"); len(backlog) != 1 || backlog[0].Type != EventResync {
+		t.Errorf("expected resync for an invalid id, got %+v", backlog)
+	}
+	if _, backlog, _ := broker.Subscribe(2, all[1].ID); len(backlog) != 1 || backlog[0].Type != EventResync {
+		t.Errorf("expected resync when no history is left, got %+v", backlog)
+	}
+}
+
+func TestEventBroker_SlowConsumerAndClose(t *testing.T) {
+	utils.Log = zap.NewNop()
+	broker := NewEventBroker(nil)
+
+	slow, _, _ := broker.Subscribe(1, "")
+	for i := 0; i <= subscriberBuffer; i++ {
+		broker.Publish(1, EventInteractionUpdated, i)
+	}
+	select {
+	case <-slow.Done():
+	default:
+		t.Error("expected a client that stopped reading to be disconnected")
+	}
+
+	sub, _, _ := broker.Subscribe(1, "")
+	broker.Close()
+	select {
+	case <-sub.Done():
+	default:
+		t.Error("expected subscriptions to end on close")
+	}
+	if _, _, err := broker.Subscribe(1, ""); err != ErrEventBrokerClosed {
+		t.Errorf("expected ErrEventBrokerClosed, got %v", err)
+	}
+	sub.Close() // idempotent
+}
+
+func TestMovieService_PublishesInteractionUpdates(t *testing.T) {
+	utils.Log = zap.NewNop()
+	db := setupMovieServiceTestDB(t)
+	movieRepo := repository.NewMovieRepository(db)
+	movieRepo.UpsertMovie(&model.Movie{TmdbID: 603, Title: "The Matrix"})
+
+	broker := NewEventBroker(nil)
+	movieService := NewMovieService(movieRepo, NewTMDBService(nil))
+	movieService.SetEventBroker(broker)
+
+	sub, _, _ := broker.Subscribe(1, "")
+	defer sub.Close()
+
+	if err := movieService.RateMovie(1, 603, dto.RateMovieRequest{Rating: 4.5}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	event := receiveEvent(t, sub)
+	if event.Type != EventInteractionUpdated {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	if string(event.Data[:14]) != `{"tmdb_id":603` {
+		t.Errorf("unexpected payload: %s", event.Data)
+	}
+
+	if err := movieService.DeleteRating(1, 603); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	receiveEvent(t, sub)
+
+	// une écriture refusée ne publie rien
+	movieService.DeleteRating(1, 603)
+	select {
+	case event := <-sub.Events():
+		t.Errorf("expected no event for a failed write, got %+v", event)
+	default:
+	}
+}
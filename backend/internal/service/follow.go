@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+)
+
+var ErrCannotFollowSelf = errors.New("you cannot follow yourself")
+
+type FollowService struct {
+	followRepo *repository.FollowRepository
+	userRepo   repository.UserRepository
+	blocks     *BlockService
+}
+
+func NewFollowService(followRepo *repository.FollowRepository, userRepo repository.UserRepository) *FollowService {
+	return &FollowService{
+		followRepo: followRepo,
+		userRepo:   userRepo,
+	}
+}
+
+func (s *FollowService) SetBlockService(blocks *BlockService) {
+	s.blocks = blocks
+}
+
+// un utilisateur en blocage avec followerID est introuvable pour lui
+func (s *FollowService) Follow(followerID, followedID uint) error {
+	if followerID == followedID {
+		return ErrCannotFollowSelf
+	}
+	if _, err := s.userRepo.GetByID(followedID); err != nil {
+		return ErrUserNotFound
+	}
+	blocked, err := s.blocks.IsBlocked(followerID, followedID)
+	if err != nil {
+		return errors.New("failed to follow user")
+	}
+	if blocked {
+		return ErrUserNotFound
+	}
+
+	if err := s.followRepo.Create(followerID, followedID); err != nil {
+		return errors.New("failed to follow user")
+	}
+	return nil
+}
+
+func (s *FollowService) Unfollow(followerID, followedID uint) error {
+	if err := s.followRepo.Delete(followerID, followedID); err != nil {
+		return errors.New("failed to unfollow user")
+	}
+	return nil
+}
+
+// nombre d'abonnements et d'abonnés ; un *FollowService nil renvoie 0, 0
+func (s *FollowService) Counts(userID uint) (following, followers int64) {
+	if s == nil {
+		return 0, 0
+	}
+	following, _ = s.followRepo.CountFollowing(userID)
+	followers, _ = s.followRepo.CountFollowers(userID)
+	return following, followers
+}
+
+// followerID suit-il followedID ? un *FollowService nil renvoie false
+func (s *FollowService) IsFollowing(followerID, followedID uint) bool {
+	if s == nil {
+		return false
+	}
+	following, err := s.followRepo.IsFollowing(followerID, followedID)
+	return err == nil && following
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"testing"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// services sociaux câblés comme dans router/routes.go (blocages partagés),
+// avec un film déjà en base : pas d'appel TMDB
+type serviceFixture struct {
+	db            *gorm.DB
+	users         *UserService
+	blocks        *BlockService
+	follows       *FollowService
+	reviews       *ReviewService
+	comments      *CommentService
+	lists         *ListService
+	notifications *NotificationService
+	movies        *MovieService
+	moderation    *ModerationService
+	movie         *model.Movie
+}
+
+func setupServiceFixture(t *testing.T) serviceFixture {
+	t.Helper()
+	utils.Log = zap.NewNop()
+	db := setupMovieServiceTestDB(t)
+	if err := db.AutoMigrate(&model.UserList{}, &model.UserListEntry{}, &model.ListCollaborator{}, &model.Notification{}, &model.NotificationPreference{},
+		&model.Role{}, &model.AuditEvent{}, &model.Report{}, &model.ReportAction{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	for _, role := range model.DefaultRoles() {
+		db.Create(&role)
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	movieRepo := repository.NewMovieRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
+	blockService := NewBlockService(repository.NewBlockRepository(db), userRepo)
+	followService := NewFollowService(repository.NewFollowRepository(db), userRepo)
+	followService.SetBlockService(blockService)
+
+	userService := NewUserService(userRepo, movieRepo)
+	userService.SetFollowService(followService)
+	userService.SetBlockService(blockService)
+	reviewService := NewReviewService(reviewRepo, movieRepo, nil)
+	reviewService.SetBlockService(blockService)
+	commentService := NewCommentService(repository.NewCommentRepository(db), reviewRepo, movieRepo)
+	commentService.SetBlockService(blockService)
+	movieService := NewMovieService(movieRepo, NewTMDBService(nil))
+	listService := NewListService(repository.NewListRepository(db), userRepo, movieService)
+	listService.SetBlockService(blockService)
+	notificationService := NewNotificationService(repository.NewNotificationRepository(db), repository.NewFollowRepository(db), nil)
+	userService.SetNotificationService(notificationService)
+
+	authz := NewAuthorizationService(userRepo, repository.NewRoleRepository(db))
+	userService.SetAuthorizationService(authz)
+	reviewService.SetAuthorizationService(authz)
+	moderationService := NewModerationService(repository.NewReportRepository(db), reviewRepo, movieRepo, userRepo, userService, nil)
+	moderationService.SetAuthorizationService(authz)
+	moderationService.SetNotificationService(notificationService)
+	moderationService.SetAuditService(NewAuditService(repository.NewAuditRepository(db)))
+
+	f := serviceFixture{
+		db:            db,
+		users:         userService,
+		blocks:        blockService,
+		follows:       followService,
+		reviews:       reviewService,
+		comments:      commentService,
+		lists:         listService,
+		notifications: notificationService,
+		movies:        movieService,
+		moderation:    moderationService,
+		movie:         &model.Movie{TmdbID: 27205, Title: "Inception"},
+	}
+	db.Create(f.movie)
+	return f
+}
+
+// crée les utilisateurs <name> / <name>@example.com, dans l'ordre
+func (f serviceFixture) createUsers(names ...string) []*model.User {
+	users := make([]*model.User, 0, len(names))
+	for _, name := range names {
+		user := &model.User{Username: name, Email: name + "@example.com"}
+		f.db.Create(user)
+		users = append(users, user)
+	}
+	return users
+}
+
+// critiques du film telles que viewerID les voit
+func (f serviceFixture) movieReviews(t *testing.T, viewerID uint) *dto.PaginatedMovieReviewsResponse {
+	t.Helper()
+	response, err := f.reviews.GetMovieReviews(viewerID, f.movie.TmdbID, repository.MovieReviewFilter{Sort: repository.ReviewSortRecent}, true, 1, 20)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return response
+}
+
+// base avec les tables de métadonnées (et models en plus) ; le TMDB simulé
+// sert les fiches de catalog, indexées par identifiant TMDB
+func setupCatalogTest(t *testing.T, catalog map[int]dto.TMDBMovieDetails, models ...interface{}) (*gorm.DB, *TMDBService) {
+	t.Helper()
+	utils.Log = zap.NewNop()
+	db := setupMovieServiceTestDB(t)
+	models = append([]interface{}{&model.Genre{}, &model.Country{}, &model.Person{}, &model.MovieCast{}, &model.MovieCrew{}}, models...)
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	tmdbService := NewTMDBService(nil)
+	tmdbService.client = mockTMDBClient(func(req *http.Request) *http.Response {
+		id, _ := strconv.Atoi(path.Base(req.URL.Path))
+		body, _ := json.Marshal(catalog[id])
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewBuffer(body)), Header: make(http.Header)}
+	})
+	return db, tmdbService
+}
+
+func tmdbDetails(id int, title, releaseDate string, runtime int, genres []dto.TMDBGenre, country string, cast []dto.TMDBCastMember, director dto.TMDBCrewMember) dto.TMDBMovieDetails {
+	director.Job, director.Department = "Director", "Directing"
+	return dto.TMDBMovieDetails{
+		ID:                  id,
+		Title:               title,
+		ReleaseDate:         releaseDate,
+		Runtime:             runtime,
+		Genres:              genres,
+		ProductionCountries: []dto.TMDBCountry{{Code: country, Name: country}},
+		Credits:             &dto.TMDBCredits{ID: id, Cast: cast, Crew: []dto.TMDBCrewMember{director, {ID: 9, Name: "Grip", Job: "Key Grip"}}},
+	}
+}
@@ -0,0 +1,644 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrListNotFound       = errors.New("list not found")
+	ErrListForbidden      = errors.New("you cannot edit this list")
+	ErrListEntryNotFound  = errors.New("list entry not found")
+	ErrMovieAlreadyInList = errors.New("movie is already in this list")
+	ErrInvalidListOrder   = errors.New("entry_ids must contain every entry of the list exactly once")
+	ErrListTitleEmpty     = errors.New("list title cannot be empty")
+
+	ErrListVersionConflict       = errors.New("list was modified by someone else, reload it and try again")
+	ErrInvalidListRole           = errors.New("role must be editor or viewer")
+	ErrCannotInviteSelf          = errors.New("you cannot invite yourself to your own list")
+	ErrAlreadyCollaborator       = errors.New("user is already invited to this list")
+	ErrCollaboratorNotFound      = errors.New("collaborator not found")
+	ErrListInvitationNotFound    = errors.New("invitation not found")
+	ErrListCollaboratorForbidden = errors.New("only the list owner can manage collaborators")
+)
+
+type ListService struct {
+	listRepo     *repository.ListRepository
+	userRepo     repository.UserRepository
+	movieService *MovieService
+	blocks       *BlockService
+}
+
+func NewListService(listRepo *repository.ListRepository, userRepo repository.UserRepository, movieService *MovieService) *ListService {
+	return &ListService{
+		listRepo:     listRepo,
+		userRepo:     userRepo,
+		movieService: movieService,
+	}
+}
+
+func (s *ListService) SetBlockService(blocks *BlockService) {
+	s.blocks = blocks
+}
+
+func (s *ListService) CreateList(userID uint, input dto.CreateListRequest) (*dto.ListResponse, error) {
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return nil, ErrListTitleEmpty
+	}
+
+	list := &model.UserList{
+		UserID:      userID,
+		Title:       title,
+		Description: input.Description,
+		IsRanked:    input.IsRanked,
+	}
+
+	// is_public a une valeur par défaut en base (true) : GORM ignore un false
+	// à la création, il faut donc le réécrire ensuite
+	if err := s.listRepo.Create(list); err != nil {
+		return nil, errors.New("failed to create list")
+	}
+	if input.IsPublic != nil && !*input.IsPublic {
+		list.IsPublic = false
+		if err := s.listRepo.Update(list, nil); err != nil {
+			return nil, errors.New("failed to create list")
+		}
+	}
+
+	return s.listResponse(list.ID, model.ListRoleOwner)
+}
+
+// listes d'un utilisateur ; les privées ne sont visibles que par lui et ses collaborateurs
+func (s *ListService) GetUserLists(viewerID, ownerID uint, page, limit int) (*dto.PaginatedListsResponse, error) {
+	if _, err := s.visibleUser(viewerID, ownerID); err != nil {
+		return nil, err
+	}
+
+	lists, total, err := s.listRepo.ListByUser(ownerID, viewerID, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to fetch lists")
+	}
+	return toPaginatedListsResponse(lists, total, page, limit), nil
+}
+
+// listes d'autres utilisateurs dont userID a accepté l'invitation
+func (s *ListService) GetSharedLists(userID uint, page, limit int) (*dto.PaginatedListsResponse, error) {
+	lists, total, err := s.listRepo.ListSharedWith(userID, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to fetch lists")
+	}
+	return toPaginatedListsResponse(lists, total, page, limit), nil
+}
+
+func toPaginatedListsResponse(lists []repository.ListSummary, total int64, page, limit int) *dto.PaginatedListsResponse {
+	response := &dto.PaginatedListsResponse{
+		Lists:      make([]dto.ListResponse, 0, len(lists)),
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}
+	for _, summary := range lists {
+		response.Lists = append(response.Lists, toListResponse(&summary.UserList, summary.EntryCount))
+	}
+	return response
+}
+
+// détail d'une liste avec ses films, paginés dans l'ordre de la liste
+func (s *ListService) GetList(viewerID, listID uint, page, limit int) (*dto.ListDetailResponse, error) {
+	list, role, err := s.listAccess(viewerID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, total, err := s.listRepo.ListEntries(list.ID, list.UserID, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to fetch list entries")
+	}
+
+	response := &dto.ListDetailResponse{
+		ListResponse: toListResponse(list, total),
+		Entries:      make([]dto.ListEntryResponse, 0, len(entries)),
+		Page:         page,
+		Limit:        limit,
+		TotalPages:   int((total + int64(limit) - 1) / int64(limit)),
+	}
+	response.MyRole = role
+	// statut du propriétaire filtré selon ses réglages de confidentialité
+	owner := viewerID == list.UserID
+	for _, entry := range entries {
+		item := toListEntryResponse(entry, list.IsRanked)
+		if !owner && !list.User.ShowRatings {
+			item.OwnerStatus.Rating = nil
+		}
+		if !owner && !list.User.ShowWatchlist {
+			item.OwnerStatus.IsWatchlist = false
+		}
+		response.Entries = append(response.Entries, item)
+	}
+
+	return response, nil
+}
+
+func (s *ListService) UpdateList(userID, listID uint, input dto.UpdateListRequest) (*dto.ListResponse, error) {
+	list, err := s.ownedList(userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Title != nil {
+		title := strings.TrimSpace(*input.Title)
+		if title == "" {
+			return nil, ErrListTitleEmpty
+		}
+		list.Title = title
+	}
+	if input.Description != nil {
+		list.Description = *input.Description
+	}
+	if input.IsRanked != nil {
+		list.IsRanked = *input.IsRanked
+	}
+	if input.IsPublic != nil {
+		list.IsPublic = *input.IsPublic
+	}
+
+	if err := s.listRepo.Update(list, input.Version); err != nil {
+		return nil, versionError(err, "failed to update list")
+	}
+
+	return s.listResponse(list.ID, model.ListRoleOwner)
+}
+
+func (s *ListService) DeleteList(userID, listID uint) error {
+	if _, err := s.ownedList(userID, listID); err != nil {
+		return err
+	}
+
+	if err := s.listRepo.Delete(listID); err != nil {
+		return errors.New("failed to delete list")
+	}
+	return nil
+}
+
+// ajoute un film en fin de liste ; le film est importé depuis TMDB si besoin
+func (s *ListService) AddEntry(userID, listID uint, input dto.AddListEntryRequest) (*dto.ListResponse, error) {
+	list, role, err := s.editableList(userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	movie, err := s.movieService.EnsureMovieExists(input.TmdbID)
+	if err != nil {
+		return nil, errors.New("failed to fetch movie")
+	}
+
+	exists, err := s.listRepo.HasMovie(list.ID, movie.ID)
+	if err != nil {
+		return nil, errors.New("failed to add movie to list")
+	}
+	if exists {
+		return nil, ErrMovieAlreadyInList
+	}
+
+	entry := &model.UserListEntry{
+		ListID:    list.ID,
+		MovieID:   movie.ID,
+		Note:      input.Note,
+		AddedByID: &userID,
+	}
+	if err := s.listRepo.AddEntry(entry); err != nil {
+		return nil, errors.New("failed to add movie to list")
+	}
+
+	return s.listResponse(list.ID, role)
+}
+
+func (s *ListService) UpdateEntry(userID, listID, entryID uint, input dto.UpdateListEntryRequest) (*dto.ListResponse, error) {
+	entry, role, err := s.editableEntry(userID, listID, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Note = input.Note
+	if err := s.listRepo.UpdateEntryNote(entry, input.Version); err != nil {
+		return nil, versionError(err, "failed to update list entry")
+	}
+	return s.listResponse(listID, role)
+}
+
+func (s *ListService) RemoveEntry(userID, listID, entryID uint) (*dto.ListResponse, error) {
+	entry, role, err := s.editableEntry(userID, listID, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.listRepo.RemoveEntry(entry); err != nil {
+		return nil, errors.New("failed to remove list entry")
+	}
+	return s.listResponse(listID, role)
+}
+
+// nouvel ordre complet : chaque film de la liste doit apparaître une seule fois ;
+// refusé si la liste a changé depuis la version envoyée par le client
+func (s *ListService) ReorderList(userID, listID uint, input dto.ReorderListRequest) (*dto.ListResponse, error) {
+	list, role, err := s.editableList(userID, listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.Version != input.Version {
+		return nil, ErrListVersionConflict
+	}
+
+	current, err := s.listRepo.EntryIDs(list.ID)
+	if err != nil {
+		return nil, errors.New("failed to fetch list entries")
+	}
+	if !samePermutation(current, input.EntryIDs) {
+		return nil, ErrInvalidListOrder
+	}
+
+	// la version est revérifiée dans la transaction : un ajout ou un retrait
+	// concurrent depuis la lecture des entrées fait échouer le réordonnancement
+	if err := s.listRepo.Reorder(list.ID, input.EntryIDs, input.Version); err != nil {
+		return nil, versionError(err, "failed to reorder list")
+	}
+	return s.listResponse(list.ID, role)
+}
+
+// COLLABORATEURS
+
+// invite un utilisateur comme éditeur ou lecteur ; il doit accepter l'invitation
+func (s *ListService) InviteCollaborator(ownerID, listID uint, input dto.InviteListCollaboratorRequest) (*dto.ListCollaboratorResponse, error) {
+	list, err := s.ownedList(ownerID, listID)
+	if err != nil {
+		return nil, err
+	}
+	if !model.IsValidListRole(input.Role) {
+		return nil, ErrInvalidListRole
+	}
+	if input.UserID == ownerID {
+		return nil, ErrCannotInviteSelf
+	}
+
+	user, err := s.visibleUser(ownerID, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.listRepo.GetCollaborator(list.ID, user.ID); err == nil {
+		return nil, ErrAlreadyCollaborator
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("failed to invite collaborator")
+	}
+
+	collaborator := &model.ListCollaborator{
+		ListID:      list.ID,
+		UserID:      user.ID,
+		Role:        input.Role,
+		Status:      model.ListInvitationPending,
+		InvitedByID: ownerID,
+	}
+	if err := s.listRepo.CreateCollaborator(collaborator); err != nil {
+		return nil, errors.New("failed to invite collaborator")
+	}
+
+	collaborator.User = *user
+	response := toListCollaboratorResponse(collaborator)
+	return &response, nil
+}
+
+// les invitations en attente ne sont montrées qu'au propriétaire
+func (s *ListService) GetCollaborators(userID, listID uint) ([]dto.ListCollaboratorResponse, error) {
+	_, role, err := s.listAccess(userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	collaborators, err := s.listRepo.ListCollaborators(listID, role == model.ListRoleOwner)
+	if err != nil {
+		return nil, errors.New("failed to fetch collaborators")
+	}
+
+	response := make([]dto.ListCollaboratorResponse, 0, len(collaborators))
+	for i := range collaborators {
+		response = append(response, toListCollaboratorResponse(&collaborators[i]))
+	}
+	return response, nil
+}
+
+func (s *ListService) UpdateCollaboratorRole(ownerID, listID, collaboratorID uint, input dto.UpdateListCollaboratorRequest) (*dto.ListCollaboratorResponse, error) {
+	if _, err := s.ownedList(ownerID, listID); err != nil {
+		return nil, err
+	}
+	if !model.IsValidListRole(input.Role) {
+		return nil, ErrInvalidListRole
+	}
+
+	collaborator, err := s.collaborator(listID, collaboratorID)
+	if err != nil {
+		return nil, err
+	}
+
+	collaborator.Role = input.Role
+	if err := s.listRepo.UpdateCollaborator(collaborator); err != nil {
+		return nil, errors.New("failed to update collaborator")
+	}
+
+	user, err := s.userRepo.GetByID(collaboratorID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	collaborator.User = *user
+	response := toListCollaboratorResponse(collaborator)
+	return &response, nil
+}
+
+// le propriétaire retire un collaborateur (ou annule une invitation),
+// un collaborateur peut aussi quitter la liste de lui-même
+func (s *ListService) RemoveCollaborator(userID, listID, collaboratorID uint) error {
+	_, role, err := s.listAccess(userID, listID)
+	if err != nil {
+		return err
+	}
+	if role != model.ListRoleOwner && userID != collaboratorID {
+		return ErrListCollaboratorForbidden
+	}
+
+	if err := s.listRepo.DeleteCollaborator(listID, collaboratorID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCollaboratorNotFound
+		}
+		return errors.New("failed to remove collaborator")
+	}
+	return nil
+}
+
+func (s *ListService) GetInvitations(userID uint) ([]dto.ListInvitationResponse, error) {
+	invitations, err := s.listRepo.ListInvitations(userID)
+	if err != nil {
+		return nil, errors.New("failed to fetch invitations")
+	}
+
+	response := make([]dto.ListInvitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		count, err := s.listRepo.CountEntries(invitation.ListID)
+		if err != nil {
+			return nil, errors.New("failed to fetch invitations")
+		}
+		response = append(response, dto.ListInvitationResponse{
+			List:      toListResponse(&invitation.List, count),
+			Role:      invitation.Role,
+			InvitedAt: invitation.CreatedAt,
+		})
+	}
+	return response, nil
+}
+
+func (s *ListService) AcceptInvitation(userID, listID uint) (*dto.ListResponse, error) {
+	invitation, err := s.pendingInvitation(userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation.Status = model.ListInvitationAccepted
+	invitation.RespondedAt = &now
+	if err := s.listRepo.UpdateCollaborator(invitation); err != nil {
+		return nil, errors.New("failed to accept invitation")
+	}
+
+	return s.listResponse(listID, invitation.Role)
+}
+
+// une invitation refusée est supprimée : le propriétaire pourra réinviter
+func (s *ListService) DeclineInvitation(userID, listID uint) error {
+	if _, err := s.pendingInvitation(userID, listID); err != nil {
+		return err
+	}
+
+	if err := s.listRepo.DeleteCollaborator(listID, userID); err != nil {
+		return errors.New("failed to decline invitation")
+	}
+	return nil
+}
+
+// rôle de userID sur la liste : owner, editor, viewer, ou "" pour un simple
+// lecteur d'une liste publique ; une liste privée, ou celle d'un utilisateur
+// en blocage avec userID, est introuvable pour les autres
+func (s *ListService) listAccess(userID, listID uint) (*model.UserList, string, error) {
+	list, err := s.listRepo.GetVisible(listID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrListNotFound
+		}
+		return nil, "", errors.New("failed to fetch list")
+	}
+	if list.UserID == userID {
+		return list, model.ListRoleOwner, nil
+	}
+
+	role := ""
+	collaborator, err := s.listRepo.GetCollaborator(listID, userID)
+	if err == nil && collaborator.Status == model.ListInvitationAccepted {
+		role = collaborator.Role
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", errors.New("failed to fetch list")
+	}
+
+	if !list.IsPublic && role == "" {
+		return nil, "", ErrListNotFound
+	}
+	return list, role, nil
+}
+
+// un utilisateur en blocage avec viewerID est introuvable pour lui
+func (s *ListService) visibleUser(viewerID, userID uint) (*model.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	blocked, err := s.blocks.IsBlocked(viewerID, userID)
+	if err != nil {
+		return nil, errors.New("failed to fetch user")
+	}
+	if blocked {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// contenu modifiable par le propriétaire et les éditeurs
+func (s *ListService) editableList(userID, listID uint) (*model.UserList, string, error) {
+	list, role, err := s.listAccess(userID, listID)
+	if err != nil {
+		return nil, "", err
+	}
+	if role != model.ListRoleOwner && role != model.ListRoleEditor {
+		return nil, "", ErrListForbidden
+	}
+	return list, role, nil
+}
+
+// réglages de la liste et collaborateurs : propriétaire uniquement
+func (s *ListService) ownedList(userID, listID uint) (*model.UserList, error) {
+	list, role, err := s.listAccess(userID, listID)
+	if err != nil {
+		return nil, err
+	}
+	if role != model.ListRoleOwner {
+		return nil, ErrListForbidden
+	}
+	return list, nil
+}
+
+func (s *ListService) editableEntry(userID, listID, entryID uint) (*model.UserListEntry, string, error) {
+	_, role, err := s.editableList(userID, listID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	entry, err := s.listRepo.GetEntry(listID, entryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrListEntryNotFound
+		}
+		return nil, "", errors.New("failed to fetch list entry")
+	}
+	return entry, role, nil
+}
+
+func (s *ListService) collaborator(listID, userID uint) (*model.ListCollaborator, error) {
+	collaborator, err := s.listRepo.GetCollaborator(listID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCollaboratorNotFound
+		}
+		return nil, errors.New("failed to fetch collaborator")
+	}
+	return collaborator, nil
+}
+
+func (s *ListService) pendingInvitation(userID, listID uint) (*model.ListCollaborator, error) {
+	invitation, err := s.listRepo.GetCollaborator(listID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrListInvitationNotFound
+		}
+		return nil, errors.New("failed to fetch invitation")
+	}
+	if invitation.Status != model.ListInvitationPending {
+		return nil, ErrListInvitationNotFound
+	}
+	return invitation, nil
+}
+
+func versionError(err error, message string) error {
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return ErrListVersionConflict
+	}
+	return errors.New(message)
+}
+
+func (s *ListService) listResponse(listID uint, role string) (*dto.ListResponse, error) {
+	list, err := s.listRepo.GetByID(listID)
+	if err != nil {
+		return nil, errors.New("failed to fetch list")
+	}
+	count, err := s.listRepo.CountEntries(listID)
+	if err != nil {
+		return nil, errors.New("failed to fetch list")
+	}
+
+	response := toListResponse(list, count)
+	response.MyRole = role
+	return &response, nil
+}
+
+func samePermutation(current, proposed []uint) bool {
+	if len(current) != len(proposed) {
+		return false
+	}
+
+	remaining := make(map[uint]bool, len(current))
+	for _, id := range current {
+		remaining[id] = true
+	}
+	for _, id := range proposed {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+	return true
+}
+
+func toListResponse(list *model.UserList, entryCount int64) dto.ListResponse {
+	return dto.ListResponse{
+		ID: list.ID,
+		Owner: dto.ReviewAuthorResponse{
+			ID:                list.User.ID,
+			Username:          list.User.Username,
+			ProfilePictureURL: list.User.ProfilePictureURL,
+		},
+		Title:       list.Title,
+		Description: list.Description,
+		IsRanked:    list.IsRanked,
+		IsPublic:    list.IsPublic,
+		Version:     list.Version,
+		EntryCount:  entryCount,
+		CreatedAt:   list.CreatedAt,
+		UpdatedAt:   list.UpdatedAt,
+	}
+}
+
+func toListEntryResponse(entry repository.ListEntryResult, ranked bool) dto.ListEntryResponse {
+	response := dto.ListEntryResponse{
+		ID:          entry.EntryID,
+		Note:        entry.Note,
+		AddedAt:     entry.AddedAt,
+		TmdbID:      entry.TmdbID,
+		Title:       entry.Title,
+		ReleaseYear: entry.ReleaseYear,
+		PosterURL:   entry.PosterURL,
+		OwnerStatus: dto.ListEntryOwnerStatus{
+			IsWatched:   entry.IsWatched,
+			IsWatchlist: entry.IsWatchlist,
+			Rating:      entry.UserRating,
+			HasReview:   entry.HasReview,
+		},
+	}
+	if entry.AddedByID != nil && entry.AddedByUsername != nil {
+		response.AddedBy = &dto.ReviewAuthorResponse{
+			ID:                *entry.AddedByID,
+			Username:          *entry.AddedByUsername,
+			ProfilePictureURL: entry.AddedByPictureURL,
+		}
+	}
+	if ranked {
+		rank := entry.Position
+		response.Rank = &rank
+	}
+	return response
+}
+
+func toListCollaboratorResponse(collaborator *model.ListCollaborator) dto.ListCollaboratorResponse {
+	return dto.ListCollaboratorResponse{
+		User: dto.ReviewAuthorResponse{
+			ID:                collaborator.User.ID,
+			Username:          collaborator.User.Username,
+			ProfilePictureURL: collaborator.User.ProfilePictureURL,
+		},
+		Role:        collaborator.Role,
+		Status:      collaborator.Status,
+		InvitedAt:   collaborator.CreatedAt,
+		RespondedAt: collaborator.RespondedAt,
+	}
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func setupListServiceTest(t *testing.T) (*gorm.DB, *ListService, *model.User, *model.User) {
+	utils.Log = zap.NewNop()
+	db := setupMovieServiceTestDB(t)
+	if err := db.AutoMigrate(&model.UserList{}, &model.UserListEntry{}, &model.ListCollaborator{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	movieRepo := repository.NewMovieRepository(db)
+	movieService := NewMovieService(movieRepo, NewTMDBService(nil))
+	listService := NewListService(repository.NewListRepository(db), repository.NewUserRepository(db), movieService)
+
+	// films déjà en base : pas d'appel TMDB
+	for i, title := range []string{"Heat", "Collateral", "Thief"} {
+		movieRepo.UpsertMovie(&model.Movie{TmdbID: 900 + i, Title: title})
+	}
+
+	owner := &model.User{Username: "owner", Email: "owner@example.com"}
+	other := &model.User{Username: "other", Email: "other@example.com"}
+	db.Create(owner)
+	db.Create(other)
+
+	return db, listService, owner, other
+}
+
+func TestListService_RankedListLifecycle(t *testing.T) {
+	db, listService, owner, other := setupListServiceTest(t)
+
+	list, err := listService.CreateList(owner.ID, dto.CreateListRequest{Title: "Mann ranked", IsRanked: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !list.IsPublic || list.Owner.Username != "owner" {
+		t.Errorf("unexpected list: %+v", list)
+	}
+
+	for _, tmdbID := range []int{900, 901, 902} {
+		if _, err := listService.AddEntry(owner.ID, list.ID, dto.AddListEntryRequest{TmdbID: tmdbID, Note: "note"}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if _, err := listService.AddEntry(owner.ID, list.ID, dto.AddListEntryRequest{TmdbID: 900}); !errors.Is(err, ErrMovieAlreadyInList) {
+		t.Errorf("expected ErrMovieAlreadyInList, got %v", err)
+	}
+	if _, err := listService.AddEntry(other.ID, list.ID, dto.AddListEntryRequest{TmdbID: 900}); !errors.Is(err, ErrListForbidden) {
+		t.Errorf("expected ErrListForbidden, got %v", err)
+	}
+
+	// statut du propriétaire sur chaque film
+	var heat model.Movie
+	db.Where("tmdb_id = ?", 900).First(&heat)
+	db.Create(&model.Track{UserID: owner.ID, MovieID: heat.ID, IsWatched: true})
+	db.Create(&model.Rate{UserID: owner.ID, MovieID: heat.ID, Rating: 5})
+
+	detail, err := listService.GetList(other.ID, list.ID, 1, 50)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if detail.EntryCount != 3 || len(detail.Entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(detail.Entries))
+	}
+	first := detail.Entries[0]
+	if first.Title != "Heat" || first.Rank == nil || *first.Rank != 1 {
+		t.Errorf("unexpected first entry: %+v", first)
+	}
+	if !first.OwnerStatus.IsWatched || first.OwnerStatus.Rating == nil || *first.OwnerStatus.Rating != 5 {
+		t.Errorf("expected owner's watched/rated status, got %+v", first.OwnerStatus)
+	}
+	if detail.Entries[1].OwnerStatus.IsWatched {
+		t.Errorf("expected unwatched entry, got %+v", detail.Entries[1].OwnerStatus)
+	}
+
+	ids := []uint{detail.Entries[2].ID, detail.Entries[0].ID, detail.Entries[1].ID}
+	if _, err := listService.ReorderList(owner.ID, list.ID, dto.ReorderListRequest{EntryIDs: ids[:2], Version: detail.Version}); !errors.Is(err, ErrInvalidListOrder) {
+		t.Errorf("expected ErrInvalidListOrder for a partial order, got %v", err)
+	}
+	if _, err := listService.ReorderList(owner.ID, list.ID, dto.ReorderListRequest{EntryIDs: ids, Version: detail.Version}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// retirer "Thief" (désormais 1er) referme les positions
+	if _, err := listService.RemoveEntry(owner.ID, list.ID, ids[0]); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	detail, _ = listService.GetList(owner.ID, list.ID, 1, 50)
+	if len(detail.Entries) != 2 || detail.Entries[0].Title != "Heat" || *detail.Entries[0].Rank != 1 || *detail.Entries[1].Rank != 2 {
+		t.Errorf("unexpected order after removal: %+v", detail.Entries)
+	}
+}
+
+func TestListService_Visibility(t *testing.T) {
+	_, listService, owner, other := setupListServiceTest(t)
+
+	private := false
+	secret, _ := listService.CreateList(owner.ID, dto.CreateListRequest{Title: "Guilty pleasures", IsPublic: &private})
+	listService.CreateList(owner.ID, dto.CreateListRequest{Title: "Public picks"})
+
+	if secret.IsPublic {
+		t.Fatal("expected list to be created private")
+	}
+	if _, err := listService.GetList(other.ID, secret.ID, 1, 50); !errors.Is(err, ErrListNotFound) {
+		t.Errorf("expected private list to be hidden, got %v", err)
+	}
+	if _, err := listService.GetList(owner.ID, secret.ID, 1, 50); err != nil {
+		t.Errorf("expected owner to see their private list, got %v", err)
+	}
+
+	mine, _ := listService.GetUserLists(owner.ID, owner.ID, 1, 20)
+	theirs, _ := listService.GetUserLists(other.ID, owner.ID, 1, 20)
+	if mine.Total != 2 || theirs.Total != 1 || theirs.Lists[0].Title != "Public picks" {
+		t.Errorf("expected private lists to be filtered for others, got mine=%d theirs=%+v", mine.Total, theirs.Lists)
+	}
+
+	public := true
+	if _, err := listService.UpdateList(owner.ID, secret.ID, dto.UpdateListRequest{IsPublic: &public}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := listService.GetList(other.ID, secret.ID, 1, 50); err != nil {
+		t.Errorf("expected list to be visible once public, got %v", err)
+	}
+
+	if err := listService.DeleteList(other.ID, secret.ID); !errors.Is(err, ErrListForbidden) {
+		t.Errorf("expected ErrListForbidden, got %v", err)
+	}
+	if err := listService.DeleteList(owner.ID, secret.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := listService.GetList(owner.ID, secret.ID, 1, 50); !errors.Is(err, ErrListNotFound) {
+		t.Errorf("expected deleted list to be gone, got %v", err)
+	}
+}
+
+func TestListService_Collaborators(t *testing.T) {
+	db, listService, owner, editor := setupListServiceTest(t)
+	viewer := &model.User{Username: "viewer", Email: "viewer@example.com"}
+	db.Create(viewer)
+
+	private := false
+	list, _ := listService.CreateList(owner.ID, dto.CreateListRequest{Title: "Club picks", IsPublic: &private})
+	listService.AddEntry(owner.ID, list.ID, dto.AddListEntryRequest{TmdbID: 900})
+
+	if _, err := listService.InviteCollaborator(owner.ID, list.ID, dto.InviteListCollaboratorRequest{UserID: owner.ID, Role: model.ListRoleEditor}); !errors.Is(err, ErrCannotInviteSelf) {
+		t.Errorf("expected ErrCannotInviteSelf, got %v", err)
+	}
+	if _, err := listService.InviteCollaborator(editor.ID, list.ID, dto.InviteListCollaboratorRequest{UserID: viewer.ID, Role: model.ListRoleViewer}); !errors.Is(err, ErrListNotFound) {
+		t.Errorf("expected private list to stay hidden from a stranger, got %v", err)
+	}
+	if _, err := listService.InviteCollaborator(owner.ID, list.ID, dto.InviteListCollaboratorRequest{UserID: editor.ID, Role: model.ListRoleEditor}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := listService.InviteCollaborator(owner.ID, list.ID, dto.InviteListCollaboratorRequest{UserID: editor.ID, Role: model.ListRoleViewer}); !errors.Is(err, ErrAlreadyCollaborator) {
+		t.Errorf("expected ErrAlreadyCollaborator, got %v", err)
+	}
+	listService.InviteCollaborator(owner.ID, list.ID, dto.InviteListCollaboratorRequest{UserID: viewer.ID, Role: model.ListRoleViewer})
+
+	// une invitation en attente ne donne aucun droit
+	if _, err := listService.GetList(editor.ID, list.ID, 1, 50); !errors.Is(err, ErrListNotFound) {
+		t.Errorf("expected pending invitee not to see the list, got %v", err)
+	}
+	invitations, _ := listService.GetInvitations(editor.ID)
+	if len(invitations) != 1 || invitations[0].List.Title != "Club picks" || invitations[0].Role != model.ListRoleEditor {
+		t.Fatalf("unexpected invitations: %+v", invitations)
+	}
+
+	if _, err := listService.AcceptInvitation(editor.ID, list.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := listService.AcceptInvitation(editor.ID, list.ID); !errors.Is(err, ErrListInvitationNotFound) {
+		t.Errorf("expected ErrListInvitationNotFound once accepted, got %v", err)
+	}
+	if _, err := listService.AcceptInvitation(viewer.ID, list.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// l'éditeur ajoute un film, le lecteur ne peut que consulter
+	if _, err := listService.AddEntry(editor.ID, list.ID, dto.AddListEntryRequest{TmdbID: 901}); err != nil {
+		t.Fatalf("expected editor to add entries, got %v", err)
+	}
+	if _, err := listService.AddEntry(viewer.ID, list.ID, dto.AddListEntryRequest{TmdbID: 902}); !errors.Is(err, ErrListForbidden) {
+		t.Errorf("expected ErrListForbidden for a viewer, got %v", err)
+	}
+	if _, err := listService.UpdateList(editor.ID, list.ID, dto.UpdateListRequest{Description: &list.Title}); !errors.Is(err, ErrListForbidden) {
+		t.Errorf("expected editor not to change list settings, got %v", err)
+	}
+
+	detail, err := listService.GetList(viewer.ID, list.ID, 1, 50)
+	if err != nil {
+		t.Fatalf("expected viewer to see the private list, got %v", err)
+	}
+	if detail.MyRole != model.ListRoleViewer || len(detail.Entries) != 2 {
+		t.Fatalf("unexpected detail: %+v", detail)
+	}
+	if detail.Entries[0].AddedBy == nil || detail.Entries[0].AddedBy.Username != "owner" ||
+		detail.Entries[1].AddedBy == nil || detail.Entries[1].AddedBy.Username != "other" {
+		t.Errorf("expected entries to record who added them, got %+v / %+v", detail.Entries[0].AddedBy, detail.Entries[1].AddedBy)
+	}
+
+	shared, _ := listService.GetSharedLists(editor.ID, 1, 20)
+	theirs, _ := listService.GetUserLists(editor.ID, owner.ID, 1, 20)
+	if shared.Total != 1 || shared.Lists[0].Owner.Username != "owner" || theirs.Total != 1 {
+		t.Errorf("expected shared private list to be listed for collaborators, got shared=%+v theirs=%d", shared.Lists, theirs.Total)
+	}
+
+	// seul le propriétaire voit les invitations en attente et gère les rôles
+	if _, err := listService.UpdateCollaboratorRole(editor.ID, list.ID, viewer.ID, dto.UpdateListCollaboratorRequest{Role: model.ListRoleEditor}); !errors.Is(err, ErrListForbidden) {
+		t.Errorf("expected ErrListForbidden, got %v", err)
+	}
+	if err := listService.RemoveCollaborator(editor.ID, list.ID, viewer.ID); !errors.Is(err, ErrListCollaboratorForbidden) {
+		t.Errorf("expected ErrListCollaboratorForbidden, got %v", err)
+	}
+	if err := listService.RemoveCollaborator(viewer.ID, list.ID, viewer.ID); err != nil {
+		t.Fatalf("expected viewer to leave the list, got %v", err)
+	}
+	if _, err := listService.GetList(viewer.ID, list.ID, 1, 50); !errors.Is(err, ErrListNotFound) {
+		t.Errorf("expected former viewer to lose access, got %v", err)
+	}
+
+	collaborators, _ := listService.GetCollaborators(owner.ID, list.ID)
+	if len(collaborators) != 1 || collaborators[0].User.ID != editor.ID || collaborators[0].Status != model.ListInvitationAccepted {
+		t.Errorf("unexpected collaborators: %+v", collaborators)
+	}
+}
+
+func TestListService_DeclineInvitation(t *testing.T) {
+	_, listService, owner, other := setupListServiceTest(t)
+	list, _ := listService.CreateList(owner.ID, dto.CreateListRequest{Title: "Club picks"})
+
+	listService.InviteCollaborator(owner.ID, list.ID, dto.InviteListCollaboratorRequest{UserID: other.ID, Role: model.ListRoleEditor})
+	if err := listService.DeclineInvitation(other.ID, list.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := listService.AddEntry(other.ID, list.ID, dto.AddListEntryRequest{TmdbID: 900}); !errors.Is(err, ErrListForbidden) {
+		t.Errorf("expected declined invitee to have no rights, got %v", err)
+	}
+
+	// refusée, l'invitation peut être renvoyée
+	if _, err := listService.InviteCollaborator(owner.ID, list.ID, dto.InviteListCollaboratorRequest{UserID: other.ID, Role: model.ListRoleViewer}); err != nil {
+		t.Errorf("expected owner to re-invite after a decline, got %v", err)
+	}
+}
+
+func TestListService_ConcurrentReorder(t *testing.T) {
+	db, listService, owner, editor := setupListServiceTest(t)
+	list, _ := listService.CreateList(owner.ID, dto.CreateListRequest{Title: "Club picks", IsRanked: true})
+	listService.InviteCollaborator(owner.ID, list.ID, dto.InviteListCollaboratorRequest{UserID: editor.ID, Role: model.ListRoleEditor})
+	listService.AcceptInvitation(editor.ID, list.ID)
+	listService.AddEntry(owner.ID, list.ID, dto.AddListEntryRequest{TmdbID: 900})
+	listService.AddEntry(owner.ID, list.ID, dto.AddListEntryRequest{TmdbID: 901})
+
+	// les deux membres lisent la même version puis réordonnent chacun de leur côté
+	snapshot, _ := listService.GetList(owner.ID, list.ID, 1, 50)
+	a, b := snapshot.Entries[0].ID, snapshot.Entries[1].ID
+
+	updated, err := listService.ReorderList(editor.ID, list.ID, dto.ReorderListRequest{EntryIDs: []uint{b, a}, Version: snapshot.Version})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Version != snapshot.Version+1 {
+		t.Errorf("expected version to be bumped, got %d", updated.Version)
+	}
+	if _, err := listService.ReorderList(owner.ID, list.ID, dto.ReorderListRequest{EntryIDs: []uint{a, b}, Version: snapshot.Version}); !errors.Is(err, ErrListVersionConflict) {
+		t.Fatalf("expected ErrListVersionConflict for a stale reorder, got %v", err)
+	}
+
+	detail, _ := listService.GetList(owner.ID, list.ID, 1, 50)
+	if detail.Entries[0].ID != b {
+		t.Errorf("expected the first reorder to be kept, got %+v", detail.Entries)
+	}
+
+	// un ajout concurrent invalide aussi une version lue avant lui
+	listService.AddEntry(editor.ID, list.ID, dto.AddListEntryRequest{TmdbID: 902})
+	if _, err := listService.ReorderList(owner.ID, list.ID, dto.ReorderListRequest{EntryIDs: []uint{a, b}, Version: detail.Version}); !errors.Is(err, ErrListVersionConflict) {
+		t.Errorf("expected ErrListVersionConflict after a concurrent add, got %v", err)
+	}
+
+	// contrôle dans la transaction : la version change entre la lecture et l'écriture
+	db.Model(&model.UserList{}).Where("id = ?", list.ID).UpdateColumn("version", gorm.Expr("version + 1"))
+	if err := repository.NewListRepository(db).Reorder(list.ID, []uint{a, b}, detail.Version+1); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Errorf("expected repository to reject a stale version, got %v", err)
+	}
+
+	stale := detail.Version
+	if _, err := listService.UpdateEntry(owner.ID, list.ID, a, dto.UpdateListEntryRequest{Note: "x", Version: &stale}); !errors.Is(err, ErrListVersionConflict) {
+		t.Errorf("expected ErrListVersionConflict for a stale note edit, got %v", err)
+	}
+}
@@ -0,0 +1,432 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidReportReason      = errors.New("invalid reason for this kind of report")
+	ErrReportTargetNotFound     = errors.New("reported content not found")
+	ErrCannotReportSelf         = errors.New("you cannot report your own content")
+	ErrAlreadyReported          = errors.New("you already reported this content")
+	ErrReportNotFound           = errors.New("report not found")
+	ErrInvalidModerationAction  = errors.New("invalid action for this kind of report")
+	ErrReportClosed             = errors.New("report is already closed")
+	ErrReportNotClosed          = errors.New("report is still open")
+	ErrModerationActionDenied   = errors.New("you are not allowed to suspend accounts")
+	ErrModerationTargetRequired = errors.New("reported content no longer exists")
+)
+
+type ModerationService struct {
+	reportRepo  *repository.ReportRepository
+	reviewRepo  *repository.ReviewRepository
+	movieRepo   *repository.MovieRepository
+	userRepo    repository.UserRepository
+	userService *UserService
+	cache       *CacheService
+	authz       *AuthorizationService
+	notifier    *NotificationService
+	audit       *AuditService
+	now         func() time.Time
+}
+
+func NewModerationService(reportRepo *repository.ReportRepository, reviewRepo *repository.ReviewRepository, movieRepo *repository.MovieRepository, userRepo repository.UserRepository, userService *UserService, cache *CacheService) *ModerationService {
+	return &ModerationService{
+		reportRepo:  reportRepo,
+		reviewRepo:  reviewRepo,
+		movieRepo:   movieRepo,
+		userRepo:    userRepo,
+		userService: userService,
+		cache:       cache,
+		now:         time.Now,
+	}
+}
+
+// la suspension depuis un signalement exige en plus users:manage
+func (s *ModerationService) SetAuthorizationService(authz *AuthorizationService) {
+	s.authz = authz
+}
+
+func (s *ModerationService) SetNotificationService(notifier *NotificationService) {
+	s.notifier = notifier
+}
+
+func (s *ModerationService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
+// SIGNALEMENTS
+
+// signale une critique, un profil ou un avatar ; le contenu est copié tel
+// quel pour que la modération voie ce qui a été signalé
+func (s *ModerationService) CreateReport(reporterID uint, input dto.CreateReportRequest) (*dto.ReportResponse, error) {
+	if !model.IsValidReportReason(input.TargetType, input.Reason) {
+		return nil, ErrInvalidReportReason
+	}
+	if input.UserID == reporterID {
+		return nil, ErrCannotReportSelf
+	}
+
+	user, err := s.userRepo.GetByID(input.UserID)
+	if err != nil {
+		return nil, ErrReportTargetNotFound
+	}
+
+	report := &model.Report{
+		ReporterID:   &reporterID,
+		TargetType:   input.TargetType,
+		TargetUserID: user.ID,
+		Reason:       input.Reason,
+		Details:      input.Details,
+		Status:       model.ReportStatusOpen,
+	}
+
+	switch input.TargetType {
+	case model.ReportTargetReview:
+		if input.TmdbID == nil {
+			return nil, ErrReportTargetNotFound
+		}
+		movie, err := s.movieRepo.GetMovieByTmdbID(*input.TmdbID)
+		if err != nil {
+			return nil, ErrReportTargetNotFound
+		}
+		review, err := s.reviewRepo.GetVisible(reporterID, user.ID, movie.ID)
+		if err != nil {
+			return nil, ErrReportTargetNotFound
+		}
+		report.MovieID = &movie.ID
+		report.Snapshot = review.Content
+	case model.ReportTargetProfile:
+		report.Snapshot = user.Username
+		if user.Bio != nil {
+			report.Snapshot += "\n" + *user.Bio
+		}
+	case model.ReportTargetAvatar:
+		if user.ProfilePictureURL == nil {
+			return nil, ErrReportTargetNotFound
+		}
+		report.Snapshot = *user.ProfilePictureURL
+	}
+
+	duplicate, err := s.reportRepo.HasOpenReport(report.ReporterID, report.TargetType, report.TargetUserID, report.MovieID)
+	if err != nil {
+		return nil, errors.New("failed to create report")
+	}
+	if duplicate {
+		return nil, ErrAlreadyReported
+	}
+
+	if err := s.reportRepo.Create(report); err != nil {
+		return nil, errors.New("failed to create report")
+	}
+
+	created, err := s.reportRepo.GetByID(report.ID)
+	if err != nil {
+		return nil, errors.New("failed to fetch report")
+	}
+	response := toReportResponse(created)
+	return &response, nil
+}
+
+// signalement automatique d'un contenu marqué par la politique de contenu ;
+// un seul signalement ouvert par contenu, les échecs sont seulement loggés
+func (s *ModerationService) FlagContent(target ContentTarget, rules []string) {
+	duplicate, err := s.reportRepo.HasOpenReport(nil, target.Type, target.UserID, target.MovieID)
+	if err == nil && duplicate {
+		return
+	}
+
+	report := &model.Report{
+		TargetType:   target.Type,
+		TargetUserID: target.UserID,
+		MovieID:      target.MovieID,
+		Reason:       model.ReportReasonContentPolicy,
+		Details:      "rules: " + strings.Join(rules, ", "),
+		Snapshot:     target.Snapshot,
+		Status:       model.ReportStatusOpen,
+	}
+	if err == nil {
+		err = s.reportRepo.Create(report)
+	}
+	if err != nil {
+		utils.Log.Error("Failed to flag content for moderation",
+			zap.Uint("user_id", target.UserID),
+			zap.String("target_type", target.Type),
+			zap.Error(err),
+		)
+	}
+}
+
+// FILE DE MODÉRATION
+
+func (s *ModerationService) GetReports(filter repository.ReportFilter, page, limit int) (*dto.PaginatedReportsResponse, error) {
+	reports, total, err := s.reportRepo.List(filter, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to fetch reports")
+	}
+
+	response := &dto.PaginatedReportsResponse{
+		Reports:    make([]dto.ReportResponse, 0, len(reports)),
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}
+	for i := range reports {
+		response.Reports = append(response.Reports, toReportResponse(&reports[i]))
+	}
+	return response, nil
+}
+
+// signalement avec l'état actuel du contenu et l'historique des mesures
+func (s *ModerationService) GetReport(reportID uint) (*dto.ReportDetailResponse, error) {
+	report, err := s.getReport(reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	actions, err := s.reportRepo.ListActions(report.ID)
+	if err != nil {
+		return nil, errors.New("failed to fetch report history")
+	}
+
+	response := &dto.ReportDetailResponse{
+		ReportResponse: toReportResponse(report),
+		Current:        s.targetState(&report.Report),
+		Actions:        make([]dto.ReportActionResponse, 0, len(actions)),
+	}
+	for _, action := range actions {
+		response.Actions = append(response.Actions, dto.ReportActionResponse{
+			ID:     action.ID,
+			Action: action.Action,
+			Note:   action.Note,
+			Moderator: dto.ReviewAuthorResponse{
+				ID:                action.Moderator.ID,
+				Username:          action.Moderator.Username,
+				ProfilePictureURL: action.Moderator.ProfilePictureURL,
+			},
+			CreatedAt: action.CreatedAt,
+		})
+	}
+	return response, nil
+}
+
+// applique une mesure à partir d'un signalement et l'ajoute à son historique.
+// toute mesure sur le contenu ou l'auteur résout le signalement ; dismiss le
+// classe sans suite, reopen le rouvre
+func (s *ModerationService) ApplyAction(moderatorID, reportID uint, input dto.ModerationActionRequest, meta dto.RequestMeta) (*dto.ReportDetailResponse, error) {
+	summary, err := s.getReport(reportID)
+	if err != nil {
+		return nil, err
+	}
+	report := &summary.Report
+
+	if !model.IsValidModerationAction(report.TargetType, input.Action) {
+		return nil, ErrInvalidModerationAction
+	}
+	if report.TargetUserID == moderatorID {
+		return nil, ErrCannotModerateSelf
+	}
+
+	now := s.now()
+	switch input.Action {
+	case model.ModerationDismiss:
+		if report.Status != model.ReportStatusOpen {
+			return nil, ErrReportClosed
+		}
+		report.Status = model.ReportStatusDismissed
+	case model.ModerationReopen:
+		if report.Status == model.ReportStatusOpen {
+			return nil, ErrReportNotClosed
+		}
+		report.Status = model.ReportStatusOpen
+	default:
+		if err := s.enforce(moderatorID, report, input, meta); err != nil {
+			return nil, err
+		}
+		report.Status = model.ReportStatusResolved
+	}
+
+	if report.Status == model.ReportStatusOpen {
+		report.ResolvedByID = nil
+		report.ResolvedAt = nil
+	} else if report.ResolvedAt == nil {
+		report.ResolvedByID = &moderatorID
+		report.ResolvedAt = &now
+	}
+
+	action := &model.ReportAction{ModeratorID: moderatorID, Action: input.Action, Note: input.Note}
+	if err := s.reportRepo.AddAction(report, action); err != nil {
+		return nil, errors.New("failed to record moderation action")
+	}
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditModerationAction,
+		ActorID:  uintPtr(moderatorID),
+		TargetID: uintPtr(report.TargetUserID),
+		Metadata: map[string]interface{}{"report_id": report.ID, "action": input.Action},
+	})
+	s.notifyTarget(summary, input)
+
+	return s.GetReport(report.ID)
+}
+
+// mesure effective sur le contenu ou le compte signalé
+func (s *ModerationService) enforce(moderatorID uint, report *model.Report, input dto.ModerationActionRequest, meta dto.RequestMeta) error {
+	var err error
+	switch input.Action {
+	case model.ModerationHideReview:
+		now := s.now()
+		err = s.reviewRepo.SetHidden(report.TargetUserID, *report.MovieID, &now)
+		s.invalidatePopularReviews()
+	case model.ModerationUnhideReview:
+		err = s.reviewRepo.SetHidden(report.TargetUserID, *report.MovieID, nil)
+		s.invalidatePopularReviews()
+	case model.ModerationForceSpoiler:
+		err = s.reviewRepo.ForceSpoiler(report.TargetUserID, *report.MovieID)
+	case model.ModerationClearBio:
+		err = s.userRepo.UpdateFields(report.TargetUserID, map[string]interface{}{"bio": nil})
+	case model.ModerationClearAvatar:
+		err = s.userRepo.UpdateFields(report.TargetUserID, map[string]interface{}{"profile_picture_url": nil})
+	case model.ModerationWarn:
+		// l'avertissement est la notification envoyée à l'auteur
+		return nil
+	case model.ModerationSuspend:
+		return s.suspend(moderatorID, report, input, meta)
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrModerationTargetRequired
+	}
+	if err != nil {
+		return errors.New("failed to apply moderation action")
+	}
+	return nil
+}
+
+func (s *ModerationService) suspend(moderatorID uint, report *model.Report, input dto.ModerationActionRequest, meta dto.RequestMeta) error {
+	allowed := false
+	if s.authz != nil {
+		var err error
+		if allowed, err = s.authz.HasPermission(moderatorID, model.PermUsersManage); err != nil {
+			return errors.New("failed to check permissions")
+		}
+	}
+	if !allowed {
+		return ErrModerationActionDenied
+	}
+
+	reason := fmt.Sprintf("report #%d: %s", report.ID, report.Reason)
+	if input.Note != "" {
+		reason += " - " + input.Note
+	}
+	if len(reason) > 500 {
+		reason = reason[:500]
+	}
+	_, err := s.userService.SuspendUser(moderatorID, report.TargetUserID, dto.SuspendUserRequest{Reason: reason, Until: input.Until}, meta)
+	return err
+}
+
+// prévient l'auteur du contenu ; ni le classement ni la réouverture ne le concernent
+func (s *ModerationService) notifyTarget(report *repository.ReportSummary, input dto.ModerationActionRequest) {
+	switch input.Action {
+	case model.ModerationDismiss, model.ModerationReopen, model.ModerationUnhideReview:
+		return
+	}
+
+	payload := model.ModerationNoticePayload{
+		Action: input.Action,
+		Reason: report.Reason,
+		Note:   input.Note,
+	}
+	if report.Movie != nil {
+		payload.TmdbID = report.Movie.TmdbID
+		payload.MovieTitle = report.Movie.Title
+	}
+	s.notifier.Notify([]uint{report.TargetUserID}, nil, payload)
+}
+
+func (s *ModerationService) invalidatePopularReviews() {
+	if s.cache != nil {
+		_ = s.cache.Delete(context.Background(), popularReviewsCacheKey)
+	}
+}
+
+func (s *ModerationService) getReport(reportID uint) (*repository.ReportSummary, error) {
+	report, err := s.reportRepo.GetByID(reportID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReportNotFound
+		}
+		return nil, errors.New("failed to fetch report")
+	}
+	return report, nil
+}
+
+// nil si le contenu a disparu (critique ou compte supprimés)
+func (s *ModerationService) targetState(report *model.Report) *dto.ReportTargetStateResponse {
+	user, err := s.userRepo.GetByID(report.TargetUserID)
+	if err != nil {
+		return nil
+	}
+	state := &dto.ReportTargetStateResponse{IsSuspended: user.IsSuspended(s.now())}
+
+	switch report.TargetType {
+	case model.ReportTargetReview:
+		review, err := s.reviewRepo.Get(report.TargetUserID, *report.MovieID)
+		if err != nil {
+			return nil
+		}
+		state.Content = &review.Content
+		state.IsSpoiler = review.IsSpoiler
+		state.SpoilerForced = review.SpoilerForced
+		state.IsHidden = review.HiddenAt != nil
+	case model.ReportTargetProfile:
+		state.Bio = user.Bio
+		state.ProfilePictureURL = user.ProfilePictureURL
+	case model.ReportTargetAvatar:
+		state.ProfilePictureURL = user.ProfilePictureURL
+	}
+	return state
+}
+
+func toReportResponse(report *repository.ReportSummary) dto.ReportResponse {
+	response := dto.ReportResponse{
+		ID:         report.ID,
+		TargetType: report.TargetType,
+		TargetUser: dto.ReviewAuthorResponse{
+			ID:                report.TargetUser.ID,
+			Username:          report.TargetUser.Username,
+			ProfilePictureURL: report.TargetUser.ProfilePictureURL,
+		},
+		Reason:            report.Reason,
+		Details:           report.Details,
+		Snapshot:          report.Snapshot,
+		Status:            report.Status,
+		TargetReportCount: report.TargetReportCount,
+		ResolvedAt:        report.ResolvedAt,
+		CreatedAt:         report.CreatedAt,
+	}
+	if report.Reporter != nil {
+		response.Reporter = &dto.ReviewAuthorResponse{
+			ID:                report.Reporter.ID,
+			Username:          report.Reporter.Username,
+			ProfilePictureURL: report.Reporter.ProfilePictureURL,
+		}
+	}
+	if report.Movie != nil {
+		response.Movie = &dto.ReportMovieResponse{TmdbID: report.Movie.TmdbID, Title: report.Movie.Title}
+	}
+	return response
+}
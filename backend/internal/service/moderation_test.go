@@ -0,0 +1,199 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+)
+
+// author a critiqué le film, reporter le signale
+func setupModerationServiceTest(t *testing.T) (serviceFixture, *model.User, *model.User, *model.User) {
+	f := setupServiceFixture(t)
+	users := f.createUsers("author", "reporter", "moderator")
+	f.db.Model(users[2]).Update("role", model.RoleModerator)
+	f.db.Create(&model.Review{UserID: users[0].ID, MovieID: f.movie.ID, Content: "the ending is a dream"})
+	return f, users[0], users[1], users[2]
+}
+
+func reportReview(t *testing.T, f serviceFixture, reporter, author *model.User, reason string) *dto.ReportResponse {
+	t.Helper()
+	tmdbID := f.movie.TmdbID
+	report, err := f.moderation.CreateReport(reporter.ID, dto.CreateReportRequest{
+		TargetType: model.ReportTargetReview,
+		UserID:     author.ID,
+		TmdbID:     &tmdbID,
+		Reason:     reason,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return report
+}
+
+func TestModerationService_CreateReport(t *testing.T) {
+	f, author, reporter, _ := setupModerationServiceTest(t)
+	tmdbID := f.movie.TmdbID
+
+	if _, err := f.moderation.CreateReport(reporter.ID, dto.CreateReportRequest{
+		TargetType: model.ReportTargetAvatar, UserID: author.ID, Reason: model.ReportReasonSpoiler,
+	}); !errors.Is(err, ErrInvalidReportReason) {
+		t.Errorf("expected ErrInvalidReportReason, got %v", err)
+	}
+	if _, err := f.moderation.CreateReport(author.ID, dto.CreateReportRequest{
+		TargetType: model.ReportTargetReview, UserID: author.ID, TmdbID: &tmdbID, Reason: model.ReportReasonSpam,
+	}); !errors.Is(err, ErrCannotReportSelf) {
+		t.Errorf("expected ErrCannotReportSelf, got %v", err)
+	}
+	if _, err := f.moderation.CreateReport(reporter.ID, dto.CreateReportRequest{
+		TargetType: model.ReportTargetAvatar, UserID: author.ID, Reason: model.ReportReasonHarassment,
+	}); !errors.Is(err, ErrReportTargetNotFound) {
+		t.Errorf("expected ErrReportTargetNotFound without an avatar, got %v", err)
+	}
+
+	report := reportReview(t, f, reporter, author, model.ReportReasonSpoiler)
+	if report.Snapshot != "the ending is a dream" || report.Status != model.ReportStatusOpen || report.TargetReportCount != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if report.Movie == nil || report.Movie.TmdbID != tmdbID {
+		t.Errorf("expected report to reference the movie, got %+v", report.Movie)
+	}
+
+	if _, err := f.moderation.CreateReport(reporter.ID, dto.CreateReportRequest{
+		TargetType: model.ReportTargetReview, UserID: author.ID, TmdbID: &tmdbID, Reason: model.ReportReasonSpam,
+	}); !errors.Is(err, ErrAlreadyReported) {
+		t.Errorf("expected ErrAlreadyReported, got %v", err)
+	}
+
+	queue, err := f.moderation.GetReports(repository.ReportFilter{Status: model.ReportStatusOpen}, 1, 20)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if queue.Total != 1 {
+		t.Errorf("expected 1 open report, got %d", queue.Total)
+	}
+}
+
+func TestModerationService_HideReview(t *testing.T) {
+	f, author, reporter, moderator := setupModerationServiceTest(t)
+	report := reportReview(t, f, reporter, author, model.ReportReasonHarassment)
+
+	if _, err := f.moderation.ApplyAction(moderator.ID, report.ID, dto.ModerationActionRequest{Action: model.ModerationClearBio}, dto.RequestMeta{}); !errors.Is(err, ErrInvalidModerationAction) {
+		t.Errorf("expected ErrInvalidModerationAction, got %v", err)
+	}
+
+	detail, err := f.moderation.ApplyAction(moderator.ID, report.ID, dto.ModerationActionRequest{Action: model.ModerationHideReview, Note: "abusive"}, dto.RequestMeta{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if detail.Status != model.ReportStatusResolved || detail.Current == nil || !detail.Current.IsHidden {
+		t.Errorf("expected resolved report with hidden review, got %+v", detail)
+	}
+
+	if f.movieReviews(t, reporter.ID).Total != 0 {
+		t.Error("expected hidden review to be invisible to other users")
+	}
+	if f.movieReviews(t, author.ID).Total != 1 {
+		t.Error("expected hidden review to stay visible to its author")
+	}
+
+	var notices int64
+	f.db.Model(&model.Notification{}).Where("user_id = ? AND type = ?", author.ID, model.NotificationModerationNotice).Count(&notices)
+	if notices != 1 {
+		t.Errorf("expected the author to be notified once, got %d", notices)
+	}
+
+	if _, err := f.moderation.ApplyAction(moderator.ID, report.ID, dto.ModerationActionRequest{Action: model.ModerationDismiss}, dto.RequestMeta{}); !errors.Is(err, ErrReportClosed) {
+		t.Errorf("expected ErrReportClosed, got %v", err)
+	}
+	if _, err := f.moderation.ApplyAction(moderator.ID, report.ID, dto.ModerationActionRequest{Action: model.ModerationReopen}, dto.RequestMeta{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	detail, err = f.moderation.ApplyAction(moderator.ID, report.ID, dto.ModerationActionRequest{Action: model.ModerationUnhideReview}, dto.RequestMeta{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if f.movieReviews(t, reporter.ID).Total != 1 {
+		t.Error("expected unhidden review to be visible again")
+	}
+
+	// historique complet, dans l'ordre
+	expected := []string{model.ModerationHideReview, model.ModerationReopen, model.ModerationUnhideReview}
+	if len(detail.Actions) != len(expected) {
+		t.Fatalf("expected %d actions, got %d", len(expected), len(detail.Actions))
+	}
+	for i, action := range detail.Actions {
+		if action.Action != expected[i] || action.Moderator.ID != moderator.ID {
+			t.Errorf("unexpected action %d: %+v", i, action)
+		}
+	}
+	if detail.Actions[0].Note != "abusive" {
+		t.Errorf("expected note to be kept, got %q", detail.Actions[0].Note)
+	}
+}
+
+func TestModerationService_ForcedSpoilerSurvivesEdit(t *testing.T) {
+	f, author, reporter, moderator := setupModerationServiceTest(t)
+	report := reportReview(t, f, reporter, author, model.ReportReasonSpoiler)
+
+	if _, err := f.moderation.ApplyAction(moderator.ID, report.ID, dto.ModerationActionRequest{Action: model.ModerationForceSpoiler}, dto.RequestMeta{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	notSpoiler := false
+	if err := f.movies.UpdateReview(author.ID, f.movie.TmdbID, dto.UpdateReviewRequest{Content: "edited", IsSpoiler: &notSpoiler}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var review model.Review
+	f.db.Where("user_id = ? AND movie_id = ?", author.ID, f.movie.ID).First(&review)
+	if !review.IsSpoiler || !review.SpoilerForced || review.Content != "edited" {
+		t.Errorf("expected forced spoiler flag to survive the edit, got %+v", review)
+	}
+}
+
+func TestModerationService_ProfileActions(t *testing.T) {
+	f, author, reporter, moderator := setupModerationServiceTest(t)
+	bio := "buy followers"
+	f.db.Model(author).Update("bio", bio)
+
+	report, err := f.moderation.CreateReport(reporter.ID, dto.CreateReportRequest{
+		TargetType: model.ReportTargetProfile, UserID: author.ID, Reason: model.ReportReasonSpam,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, err := f.moderation.ApplyAction(moderator.ID, report.ID, dto.ModerationActionRequest{Action: model.ModerationClearBio}, dto.RequestMeta{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var updated model.User
+	f.db.First(&updated, author.ID)
+	if updated.Bio != nil {
+		t.Errorf("expected bio to be cleared, got %q", *updated.Bio)
+	}
+
+	// la suspension exige users:manage, que le rôle modérateur n'a pas
+	if _, err := f.moderation.ApplyAction(moderator.ID, report.ID, dto.ModerationActionRequest{Action: model.ModerationSuspend}, dto.RequestMeta{}); !errors.Is(err, ErrModerationActionDenied) {
+		t.Errorf("expected ErrModerationActionDenied, got %v", err)
+	}
+	admin := f.createUsers("admin")[0]
+	f.db.Model(admin).Update("role", model.RoleAdmin)
+	if _, err := f.moderation.ApplyAction(admin.ID, report.ID, dto.ModerationActionRequest{Action: model.ModerationSuspend, Note: "repeat offender"}, dto.RequestMeta{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	f.db.First(&updated, author.ID)
+	if updated.SuspendedAt == nil {
+		t.Error("expected author to be suspended")
+	}
+
+	detail, err := f.moderation.GetReport(report.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(detail.Actions) != 2 || detail.Current == nil || !detail.Current.IsSuspended {
+		t.Errorf("expected two actions and a suspended target, got %+v", detail)
+	}
+}
@@ -3,17 +3,32 @@ package service
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Nowap83/FrameRate/backend/internal/dto"
 	"github.com/Nowap83/FrameRate/backend/internal/model"
 	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrTrackNotFound     = errors.New("movie is not tracked")
+	ErrRatingNotFound    = errors.New("rating not found")
+	ErrInvalidRatingStep = errors.New("rating must be in increments of 0.5")
+)
+
 type MovieService struct {
-	movieRepo   *repository.MovieRepository
-	tmdbService *TMDBService
+	movieRepo    *repository.MovieRepository
+	tmdbService  *TMDBService
+	notifier     *NotificationService
+	events       *EventBroker
+	policy       *ContentPolicy
+	challenges   *ChallengeService
+	achievements *AchievementService
+	recommender  *RecommendationService
 }
 
 func NewMovieService(movieRepo *repository.MovieRepository, tmdbService *TMDBService) *MovieService {
@@ -23,7 +38,35 @@ func NewMovieService(movieRepo *repository.MovieRepository, tmdbService *TMDBSer
 	}
 }
 
-func (s *MovieService) ensureMovieExists(tmdbID int) (*model.Movie, error) {
+func (s *MovieService) SetNotificationService(notifier *NotificationService) {
+	s.notifier = notifier
+}
+
+func (s *MovieService) SetEventBroker(events *EventBroker) {
+	s.events = events
+}
+
+func (s *MovieService) SetContentPolicy(policy *ContentPolicy) {
+	s.policy = policy
+}
+
+// la progression des défis suit chaque changement du suivi d'un film
+func (s *MovieService) SetChallengeService(challenges *ChallengeService) {
+	s.challenges = challenges
+}
+
+// badges réévalués après chaque événement du journal ou des critiques
+func (s *MovieService) SetAchievementService(achievements *AchievementService) {
+	s.achievements = achievements
+}
+
+// recommandations à recalculer après chaque changement du suivi ou d'une note
+func (s *MovieService) SetRecommendationService(recommender *RecommendationService) {
+	s.recommender = recommender
+}
+
+// récupère le film en base, ou l'importe depuis TMDB à la première rencontre
+func (s *MovieService) EnsureMovieExists(tmdbID int) (*model.Movie, error) {
 	movie, err := s.movieRepo.GetMovieByTmdbID(tmdbID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -62,6 +105,10 @@ func (s *MovieService) ensureMovieExists(tmdbID int) (*model.Movie, error) {
 			if err != nil {
 				return nil, err
 			}
+			// métadonnées manquantes : rattrapées plus tard par SyncMetadata
+			if err := s.saveMetadata(movie, tmdbMovie); err != nil {
+				utils.Log.Warn("Failed to save movie metadata", zap.Int("tmdb_id", tmdbID), zap.Error(err))
+			}
 			return movie, nil
 		}
 		return nil, err
@@ -69,8 +116,98 @@ func (s *MovieService) ensureMovieExists(tmdbID int) (*model.Movie, error) {
 	return movie, nil
 }
 
+// importe (ou réimporte) genres, pays et générique d'un film déjà en base
+func (s *MovieService) SyncMetadata(movie *model.Movie) error {
+	details, err := s.tmdbService.GetMovieDetails(movie.TmdbID, "fr-FR")
+	if err != nil {
+		return err
+	}
+	return s.saveMetadata(movie, details)
+}
+
+// importe les métadonnées des films de movieIDs qui ne les ont pas encore
+func (s *MovieService) SyncMissingMetadata(movieIDs []uint) error {
+	movies, err := s.movieRepo.GetMoviesWithoutMetadata(movieIDs)
+	if err != nil {
+		return err
+	}
+	var failed int
+	for i := range movies {
+		if err := s.SyncMetadata(&movies[i]); err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to sync metadata for %d movies", failed)
+	}
+	return nil
+}
+
+// postes du générique conservés, en plus de la distribution
+var keptCrewJobs = map[string]bool{
+	"Director":                true,
+	"Screenplay":              true,
+	"Writer":                  true,
+	"Director of Photography": true,
+	"Original Music Composer": true,
+	"Editor":                  true,
+}
+
+const keptCastMembers = 15
+
+func (s *MovieService) saveMetadata(movie *model.Movie, details *dto.TMDBMovieDetails) error {
+	genres := make([]model.Genre, 0, len(details.Genres))
+	for _, genre := range details.Genres {
+		genres = append(genres, model.Genre{ID: genre.ID, Name: genre.Name})
+	}
+	countries := make([]model.Country, 0, len(details.ProductionCountries))
+	for _, country := range details.ProductionCountries {
+		if len(country.Code) == 2 {
+			countries = append(countries, model.Country{Code: country.Code, Name: country.Name})
+		}
+	}
+
+	var cast []model.MovieCast
+	var crew []model.MovieCrew
+	if details.Credits != nil {
+		for _, member := range details.Credits.Cast {
+			if member.Order >= keptCastMembers {
+				continue
+			}
+			cast = append(cast, model.MovieCast{
+				CharacterName: member.Character,
+				CastOrder:     member.Order,
+				Person:        tmdbPerson(member.ID, member.Name, member.ProfilePath, member.Gender),
+			})
+		}
+		for _, member := range details.Credits.Crew {
+			if !keptCrewJobs[member.Job] {
+				continue
+			}
+			crew = append(crew, model.MovieCrew{
+				Job:        member.Job,
+				Department: member.Department,
+				Person:     tmdbPerson(member.ID, member.Name, member.ProfilePath, member.Gender),
+			})
+		}
+	}
+
+	return s.movieRepo.SaveMetadata(movie, genres, countries, cast, crew)
+}
+
+func tmdbPerson(tmdbID int, name string, profilePath *string, gender int) model.Person {
+	person := model.Person{TmdbID: tmdbID, Name: name, Gender: model.Gender(gender)}
+	if !person.Gender.IsValid() {
+		person.Gender = model.GenderNotSet
+	}
+	if profilePath != nil {
+		person.ProfilePictureURL = *profilePath
+	}
+	return person
+}
+
 func (s *MovieService) TrackMovie(userID uint, tmdbID int, req dto.TrackMovieRequest) error {
-	movie, err := s.ensureMovieExists(tmdbID)
+	movie, err := s.EnsureMovieExists(tmdbID)
 	if err != nil {
 		return err
 	}
@@ -97,14 +234,36 @@ func (s *MovieService) TrackMovie(userID uint, tmdbID int, req dto.TrackMovieReq
 		track.IsWatchlist = *req.IsWatchlist
 	}
 	if req.WatchedDate != nil {
-		track.WatchedDate = req.WatchedDate
+		track.WatchedDate = s.watchedDay(userID, req.WatchedDate)
+	}
+
+	if err := s.movieRepo.UpsertTrack(track); err != nil {
+		return err
 	}
+	s.trackChanged(userID, movie.ID)
+	s.publishInteraction(userID, tmdbID)
+	return nil
+}
 
-	return s.movieRepo.UpsertTrack(track)
+// le front envoie la date de visionnage sans heure, à minuit UTC : c'est un
+// jour du calendrier de l'utilisateur, enregistré à minuit dans son fuseau.
+// sinon, à l'ouest d'UTC, stats, séries et défis le comptent la veille
+func (s *MovieService) watchedDay(userID uint, date *time.Time) *time.Time {
+	utc := date.UTC()
+	if utc.Hour() != 0 || utc.Minute() != 0 || utc.Second() != 0 || utc.Nanosecond() != 0 {
+		return date
+	}
+	timeZone, err := s.movieRepo.UserTimeZone(userID)
+	if err != nil {
+		return date
+	}
+	user := model.User{TimeZone: timeZone}
+	day := time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, user.Zone())
+	return &day
 }
 
 func (s *MovieService) RateMovie(userID uint, tmdbID int, req dto.RateMovieRequest) error {
-	movie, err := s.ensureMovieExists(tmdbID)
+	movie, err := s.EnsureMovieExists(tmdbID)
 	if err != nil {
 		return err
 	}
@@ -133,11 +292,25 @@ func (s *MovieService) RateMovie(userID uint, tmdbID int, req dto.RateMovieReque
 		IsWatched:   true,
 		WatchedDate: &now,
 	}
-	return s.movieRepo.UpsertTrack(track)
+	if err := s.movieRepo.UpsertTrack(track); err != nil {
+		return err
+	}
+	s.trackChanged(userID, movie.ID)
+	s.publishInteraction(userID, tmdbID)
+	return nil
 }
 
 func (s *MovieService) LogMovie(userID uint, tmdbID int, req dto.LogMovieRequest) error {
-	movie, err := s.ensureMovieExists(tmdbID)
+	// critique refusée : rien n'est enregistré, pas même le visionnage
+	var verdict PolicyResult
+	if req.ReviewText != nil {
+		var err error
+		if verdict, err = s.policy.Check(ContentFieldReview, *req.ReviewText); err != nil {
+			return err
+		}
+	}
+
+	movie, err := s.EnsureMovieExists(tmdbID)
 	if err != nil {
 		return err
 	}
@@ -145,7 +318,7 @@ func (s *MovieService) LogMovie(userID uint, tmdbID int, req dto.LogMovieRequest
 	now := time.Now()
 	watchedDate := &now
 	if req.WatchedDate != nil {
-		watchedDate = req.WatchedDate
+		watchedDate = s.watchedDay(userID, req.WatchedDate)
 	}
 
 	// watched automatically
@@ -159,6 +332,9 @@ func (s *MovieService) LogMovie(userID uint, tmdbID int, req dto.LogMovieRequest
 	if err := s.movieRepo.UpsertTrack(track); err != nil {
 		return fmt.Errorf("failed to tracking movie: %w", err)
 	}
+	if err := s.movieRepo.IncrementWatchCount(userID, movie.ID); err != nil {
+		return fmt.Errorf("failed to tracking movie: %w", err)
+	}
 
 	// rating if provided
 	if req.Rating != nil && *req.Rating >= 0 {
@@ -181,20 +357,204 @@ func (s *MovieService) LogMovie(userID uint, tmdbID int, req dto.LogMovieRequest
 		if req.IsSpoiler != nil {
 			isSpoiler = *req.IsSpoiler
 		}
+
+		// seule une première critique est annoncée aux abonnés, pas ses réécritures
+		_, _, existing, err := s.movieRepo.GetUserInteraction(userID, movie.ID)
+		if err != nil {
+			return fmt.Errorf("failed to review movie: %w", err)
+		}
+		isNew := existing == nil || existing.Content == ""
+
 		review := &model.Review{
-			UserID:    userID,
-			MovieID:   movie.ID,
-			Content:   *req.ReviewText,
-			IsSpoiler: isSpoiler,
+			UserID:      userID,
+			MovieID:     movie.ID,
+			Content:     *req.ReviewText,
+			ContentHTML: utils.RenderMarkdown(*req.ReviewText),
+			IsSpoiler:   isSpoiler,
 		}
 		if err := s.movieRepo.UpsertReview(review); err != nil {
 			return fmt.Errorf("failed to review movie: %w", err)
 		}
+		s.policy.Flag(verdict, ContentTarget{Type: model.ReportTargetReview, UserID: userID, MovieID: &movie.ID, Snapshot: review.Content})
+
+		if isNew {
+			s.notifier.NotifyFollowers(userID, model.FollowedReviewPayload{
+				TmdbID:     movie.TmdbID,
+				MovieTitle: movie.Title,
+				IsSpoiler:  isSpoiler,
+			})
+		}
+	}
+
+	s.trackChanged(userID, movie.ID)
+	s.publishInteraction(userID, tmdbID)
+	return nil
+}
+
+// les Update* ne modifient que des entrées existantes, sans effet de bord :
+// contrairement à RateMovie, corriger une note ne marque pas le film comme vu
+
+func (s *MovieService) UpdateTrack(userID uint, tmdbID int, req dto.TrackMovieRequest) error {
+	movieID, err := s.existingMovieID(tmdbID, ErrTrackNotFound)
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{}
+	if req.IsWatched != nil {
+		updates["is_watched"] = *req.IsWatched
+		if !*req.IsWatched {
+			updates["watched_date"] = nil
+		}
+	}
+	if req.IsFavorite != nil {
+		updates["is_favorite"] = *req.IsFavorite
+	}
+	if req.IsWatchlist != nil {
+		updates["is_watchlist"] = *req.IsWatchlist
+	}
+	if req.WatchedDate != nil {
+		updates["watched_date"] = s.watchedDay(userID, req.WatchedDate)
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	if err := notFoundAs(s.movieRepo.UpdateTrack(userID, movieID, updates), ErrTrackNotFound); err != nil {
+		return err
+	}
+	s.trackChanged(userID, movieID)
+	s.publishInteraction(userID, tmdbID)
+	return nil
+}
+
+// retirer un film du journal supprime aussi la note et la critique associées
+// (likes et commentaires compris)
+func (s *MovieService) DeleteTrack(userID uint, tmdbID int) error {
+	movieID, err := s.existingMovieID(tmdbID, ErrTrackNotFound)
+	if err != nil {
+		return err
+	}
+	if err := notFoundAs(s.movieRepo.DeleteTrack(userID, movieID), ErrTrackNotFound); err != nil {
+		return err
+	}
+	s.trackChanged(userID, movieID)
+	s.publishInteraction(userID, tmdbID)
+	return nil
+}
+
+func (s *MovieService) UpdateRating(userID uint, tmdbID int, req dto.RateMovieRequest) error {
+	if !isHalfStep(req.Rating) {
+		return ErrInvalidRatingStep
+	}
+
+	movieID, err := s.existingMovieID(tmdbID, ErrRatingNotFound)
+	if err != nil {
+		return err
+	}
+	if err := notFoundAs(s.movieRepo.UpdateRate(userID, movieID, req.Rating), ErrRatingNotFound); err != nil {
+		return err
+	}
+	s.recommender.HistoryChanged(userID)
+	s.publishInteraction(userID, tmdbID)
+	return nil
+}
+
+// le film reste marqué comme vu : seule la note disparaît
+func (s *MovieService) DeleteRating(userID uint, tmdbID int) error {
+	movieID, err := s.existingMovieID(tmdbID, ErrRatingNotFound)
+	if err != nil {
+		return err
+	}
+	if err := notFoundAs(s.movieRepo.DeleteRate(userID, movieID), ErrRatingNotFound); err != nil {
+		return err
+	}
+	s.recommender.HistoryChanged(userID)
+	s.publishInteraction(userID, tmdbID)
+	return nil
+}
+
+func (s *MovieService) UpdateReview(userID uint, tmdbID int, req dto.UpdateReviewRequest) error {
+	if strings.TrimSpace(req.Content) == "" {
+		return ErrReviewEmpty
+	}
+	verdict, err := s.policy.Check(ContentFieldReview, req.Content)
+	if err != nil {
+		return err
+	}
+
+	movieID, err := s.existingMovieID(tmdbID, ErrReviewNotFound)
+	if err != nil {
+		return err
 	}
 
+	if err := notFoundAs(s.movieRepo.UpdateReview(userID, movieID, req.Content, utils.RenderMarkdown(req.Content), req.IsSpoiler), ErrReviewNotFound); err != nil {
+		return err
+	}
+	s.policy.Flag(verdict, ContentTarget{Type: model.ReportTargetReview, UserID: userID, MovieID: &movieID, Snapshot: req.Content})
+	s.publishInteraction(userID, tmdbID)
+	return nil
+}
+
+// supprime la critique ainsi que ses likes et commentaires ; note et suivi sont conservés
+func (s *MovieService) DeleteReview(userID uint, tmdbID int) error {
+	movieID, err := s.existingMovieID(tmdbID, ErrReviewNotFound)
+	if err != nil {
+		return err
+	}
+	if err := notFoundAs(s.movieRepo.DeleteReview(userID, movieID), ErrReviewNotFound); err != nil {
+		return err
+	}
+	s.publishInteraction(userID, tmdbID)
 	return nil
 }
 
+// film inconnu en base => l'utilisateur n'a forcément aucune entrée dessus
+func (s *MovieService) existingMovieID(tmdbID int, notFound error) (uint, error) {
+	movie, err := s.movieRepo.GetMovieByTmdbID(tmdbID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, notFound
+		}
+		return 0, err
+	}
+	return movie.ID, nil
+}
+
+func notFoundAs(err, notFound error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return notFound
+	}
+	return err
+}
+
+func isHalfStep(rating float32) bool {
+	return float64(rating*2) == float64(int(rating*2))
+}
+
+// suivi d'un film modifié (vu, noté, date, retiré) : défis, badges et recommandations
+func (s *MovieService) trackChanged(userID, movieID uint) {
+	s.challenges.TrackChanged(userID, movieID)
+	s.achievements.ActivityChanged(userID)
+	s.recommender.HistoryChanged(userID)
+}
+
+// pousse le nouvel état vers les autres appareils de l'utilisateur (SSE)
+func (s *MovieService) publishInteraction(userID uint, tmdbID int) {
+	if s.events == nil {
+		return
+	}
+
+	interaction, err := s.GetMovieInteraction(userID, tmdbID)
+	if err != nil {
+		return
+	}
+	s.events.Publish(userID, EventInteractionUpdated, dto.InteractionEvent{
+		TmdbID:      tmdbID,
+		Interaction: interaction,
+	})
+}
+
 func (s *MovieService) GetMovieInteraction(userID uint, tmdbID int) (*dto.UserInteractionResponse, error) {
 	movie, err := s.movieRepo.GetMovieByTmdbID(tmdbID)
 	if err != nil {
@@ -227,10 +587,15 @@ func (s *MovieService) GetMovieInteraction(userID uint, tmdbID int) (*dto.UserIn
 	}
 
 	if review != nil && review.Content != "" {
+		likes, _ := s.movieRepo.CountReviewLikes(userID, movie.ID)
 		response.UserReview = &dto.ReviewResponse{
-			Content:   review.Content,
-			IsSpoiler: review.IsSpoiler,
-			CreatedAt: review.CreatedAt,
+			Content:     review.Content,
+			ContentHTML: review.ContentHTML,
+			IsSpoiler:   review.IsSpoiler,
+			LikeCount:   likes,
+			IsEdited:    review.EditedAt != nil,
+			EditedAt:    review.EditedAt,
+			CreatedAt:   review.CreatedAt,
 		}
 	}
 
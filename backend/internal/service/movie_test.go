@@ -3,6 +3,7 @@ package service
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"testing"
@@ -30,7 +31,7 @@ func setupMovieServiceTestDB(t *testing.T) *gorm.DB {
 		t.Fatalf("Failed to open test database: %v", err)
 	}
 
-	err = db.AutoMigrate(&model.User{}, &model.Movie{}, &model.Track{}, &model.Rate{}, &model.Review{})
+	err = db.AutoMigrate(&model.User{}, &model.Movie{}, &model.Track{}, &model.Rate{}, &model.Review{}, &model.ReviewLike{}, &model.ReviewComment{}, &model.ReviewRevision{}, &model.Block{}, &model.Mute{}, &model.Follow{})
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
@@ -102,7 +103,7 @@ func TestMovieService_RateMovie(t *testing.T) {
 
 	tmdbService := NewTMDBService(nil)
 	// Mock returning existing movie, so it won't hit TMDB.
-	// But ensureMovieExists will still hit TMDB if not found, so we seed the DB.
+	// But EnsureMovieExists will still hit TMDB if not found, so we seed the DB.
 	repo.UpsertMovie(&model.Movie{TmdbID: 456, Title: "Interstellar"})
 
 	movieService := NewMovieService(repo, tmdbService)
@@ -125,3 +126,120 @@ func TestMovieService_RateMovie(t *testing.T) {
 	}
 }
 
+func TestMovieService_UpdateAndDeleteEntries(t *testing.T) {
+	utils.Log = zap.NewNop()
+	db := setupMovieServiceTestDB(t)
+	repo := repository.NewMovieRepository(db)
+	movieService := NewMovieService(repo, NewTMDBService(nil))
+
+	movie := &model.Movie{TmdbID: 789, Title: "Arrival"}
+	repo.UpsertMovie(movie)
+
+	user := &model.User{Username: "test3", Email: "test3@example.com"}
+	other := &model.User{Username: "other", Email: "other@example.com"}
+	db.Create(user)
+	db.Create(other)
+
+	// aucune entrée : les PUT/DELETE renvoient une erreur "not found"
+	if err := movieService.UpdateRating(user.ID, 789, dto.RateMovieRequest{Rating: 3}); !errors.Is(err, ErrRatingNotFound) {
+		t.Errorf("expected ErrRatingNotFound, got %v", err)
+	}
+	if err := movieService.DeleteTrack(user.ID, 999); !errors.Is(err, ErrTrackNotFound) {
+		t.Errorf("expected ErrTrackNotFound for unknown movie, got %v", err)
+	}
+
+	reviewText := "heptapods"
+	rating := float32(4)
+	if err := movieService.LogMovie(user.ID, 789, dto.LogMovieRequest{Rating: &rating, ReviewText: &reviewText}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := movieService.UpdateRating(user.ID, 789, dto.RateMovieRequest{Rating: 4.7}); !errors.Is(err, ErrInvalidRatingStep) {
+		t.Errorf("expected ErrInvalidRatingStep, got %v", err)
+	}
+	if err := movieService.UpdateRating(user.ID, 789, dto.RateMovieRequest{Rating: 5}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	spoiler := true
+	if err := movieService.UpdateReview(user.ID, 789, dto.UpdateReviewRequest{Content: "the ending", IsSpoiler: &spoiler}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	interaction, _ := movieService.GetMovieInteraction(user.ID, 789)
+	if interaction.UserRating == nil || *interaction.UserRating != 5 {
+		t.Errorf("expected rating 5, got %v", interaction.UserRating)
+	}
+	if interaction.UserReview == nil || interaction.UserReview.Content != "the ending" || !interaction.UserReview.IsSpoiler {
+		t.Errorf("expected updated review, got %+v", interaction.UserReview)
+	}
+
+	// un false explicite doit être écrit (UpsertTrack l'ignorerait)
+	watched := false
+	if err := movieService.UpdateTrack(user.ID, 789, dto.TrackMovieRequest{IsWatched: &watched}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	interaction, _ = movieService.GetMovieInteraction(user.ID, 789)
+	if interaction.IsWatched || interaction.WatchedDate != nil {
+		t.Errorf("expected movie to be unmarked as watched, got %+v", interaction)
+	}
+
+	// supprimer la note ne touche ni au suivi ni à la critique
+	if err := movieService.DeleteRating(user.ID, 789); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	interaction, _ = movieService.GetMovieInteraction(user.ID, 789)
+	if interaction.UserRating != nil || interaction.UserReview == nil {
+		t.Errorf("expected only the rating to be removed, got %+v", interaction)
+	}
+
+	// la suppression de la critique emporte ses likes et commentaires
+	db.Create(&model.ReviewLike{UserID: other.ID, ReviewUserID: user.ID, MovieID: movie.ID})
+	db.Create(&model.ReviewComment{ReviewUserID: user.ID, MovieID: movie.ID, AuthorID: other.ID, Content: "nice"})
+	if err := movieService.DeleteReview(user.ID, 789); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var likes, comments int64
+	db.Model(&model.ReviewLike{}).Count(&likes)
+	db.Model(&model.ReviewComment{}).Count(&comments)
+	if likes != 0 || comments != 0 {
+		t.Errorf("expected likes and comments to be removed, got %d likes and %d comments", likes, comments)
+	}
+	if err := movieService.DeleteReview(user.ID, 789); !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("expected ErrReviewNotFound, got %v", err)
+	}
+}
+
+func TestMovieService_DeleteTrackCascades(t *testing.T) {
+	utils.Log = zap.NewNop()
+	db := setupMovieServiceTestDB(t)
+	repo := repository.NewMovieRepository(db)
+	movieService := NewMovieService(repo, NewTMDBService(nil))
+
+	repo.UpsertMovie(&model.Movie{TmdbID: 790, Title: "Sicario"})
+	user := &model.User{Username: "test4", Email: "test4@example.com"}
+	db.Create(user)
+
+	reviewText := "tense"
+	rating := float32(4.5)
+	if err := movieService.LogMovie(user.ID, 790, dto.LogMovieRequest{Rating: &rating, ReviewText: &reviewText}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := movieService.DeleteTrack(user.ID, 790); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var tracks, rates, reviews int64
+	db.Model(&model.Track{}).Count(&tracks)
+	db.Model(&model.Rate{}).Count(&rates)
+	db.Model(&model.Review{}).Count(&reviews)
+	if tracks != 0 || rates != 0 || reviews != 0 {
+		t.Errorf("expected diary entry to be fully removed, got %d tracks, %d rates, %d reviews", tracks, rates, reviews)
+	}
+
+	distribution, _ := repo.GetRatingDistribution(user.ID)
+	if len(distribution) != 0 {
+		t.Errorf("expected empty rating distribution, got %v", distribution)
+	}
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidNotificationType = errors.New("unknown notification type")
+
+type NotificationService struct {
+	notifRepo  *repository.NotificationRepository
+	followRepo *repository.FollowRepository
+	dispatcher *Dispatcher
+}
+
+func NewNotificationService(notifRepo *repository.NotificationRepository, followRepo *repository.FollowRepository, dispatcher *Dispatcher) *NotificationService {
+	return &NotificationService{
+		notifRepo:  notifRepo,
+		followRepo: followRepo,
+		dispatcher: dispatcher,
+	}
+}
+
+// FAN-OUT
+
+// notifie recipientIDs ; la livraison (préférences, insertion) se fait en
+// arrière-plan via le dispatcher. l'acteur ne reçoit jamais sa propre
+// notification. un *NotificationService nil ne notifie personne
+func (s *NotificationService) Notify(recipientIDs []uint, actorID *uint, payload model.NotificationPayload) {
+	if s == nil || len(recipientIDs) == 0 {
+		return
+	}
+
+	encoded, ok := encodePayload(payload)
+	if !ok {
+		return
+	}
+
+	recipients := append([]uint(nil), recipientIDs...)
+	s.dispatcher.Dispatch("notify:"+payload.NotificationType(), func() error {
+		return s.deliver(recipients, actorID, payload.NotificationType(), encoded)
+	})
+}
+
+// notifie les abonnés de actorID ; ils sont résolus en arrière-plan
+func (s *NotificationService) NotifyFollowers(actorID uint, payload model.NotificationPayload) {
+	if s == nil {
+		return
+	}
+
+	encoded, ok := encodePayload(payload)
+	if !ok {
+		return
+	}
+
+	s.dispatcher.Dispatch("notify_followers:"+payload.NotificationType(), func() error {
+		followers, err := s.followRepo.FollowerIDs(actorID)
+		if err != nil {
+			return fmt.Errorf("fetch followers: %w", err)
+		}
+		return s.deliver(followers, &actorID, payload.NotificationType(), encoded)
+	})
+}
+
+func (s *NotificationService) deliver(recipientIDs []uint, actorID *uint, notificationType, payload string) error {
+	seen := make(map[uint]bool, len(recipientIDs))
+	recipients := make([]uint, 0, len(recipientIDs))
+	for _, id := range recipientIDs {
+		if seen[id] || (actorID != nil && id == *actorID) {
+			continue
+		}
+		seen[id] = true
+		recipients = append(recipients, id)
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	optedOut, err := s.notifRepo.OptedOut(notificationType, recipients)
+	if err != nil {
+		return fmt.Errorf("fetch preferences: %w", err)
+	}
+
+	notifications := make([]model.Notification, 0, len(recipients))
+	for _, id := range recipients {
+		if optedOut[id] {
+			continue
+		}
+		notifications = append(notifications, model.Notification{
+			UserID:  id,
+			Type:    notificationType,
+			ActorID: actorID,
+			Payload: payload,
+		})
+	}
+
+	return s.notifRepo.CreateBatch(notifications)
+}
+
+func encodePayload(payload model.NotificationPayload) (string, bool) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		utils.Log.Error("Failed to encode notification payload",
+			zap.String("type", payload.NotificationType()),
+			zap.Error(err),
+		)
+		return "", false
+	}
+	return string(encoded), true
+}
+
+// INBOX
+
+func (s *NotificationService) GetNotifications(userID uint, unreadOnly bool, page, limit int) (*dto.PaginatedNotificationsResponse, error) {
+	notifications, total, err := s.notifRepo.List(userID, unreadOnly, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to fetch notifications")
+	}
+
+	unread, err := s.notifRepo.CountUnread(userID)
+	if err != nil {
+		return nil, errors.New("failed to fetch notifications")
+	}
+
+	response := &dto.PaginatedNotificationsResponse{
+		Notifications: make([]dto.NotificationResponse, 0, len(notifications)),
+		UnreadCount:   unread,
+		Total:         total,
+		Page:          page,
+		Limit:         limit,
+		TotalPages:    int((total + int64(limit) - 1) / int64(limit)),
+	}
+	for i := range notifications {
+		response.Notifications = append(response.Notifications, toNotificationResponse(&notifications[i]))
+	}
+	return response, nil
+}
+
+func (s *NotificationService) CountUnread(userID uint) (int64, error) {
+	count, err := s.notifRepo.CountUnread(userID)
+	if err != nil {
+		return 0, errors.New("failed to count notifications")
+	}
+	return count, nil
+}
+
+// ids inconnus ou appartenant à un autre utilisateur ignorés ; renvoie le nombre marqué
+func (s *NotificationService) MarkRead(userID uint, ids []uint) (int64, error) {
+	count, err := s.notifRepo.MarkRead(userID, ids)
+	if err != nil {
+		return 0, errors.New("failed to mark notifications as read")
+	}
+	return count, nil
+}
+
+func (s *NotificationService) MarkAllRead(userID uint) (int64, error) {
+	count, err := s.notifRepo.MarkRead(userID, nil)
+	if err != nil {
+		return 0, errors.New("failed to mark notifications as read")
+	}
+	return count, nil
+}
+
+// PREFERENCES
+
+// tous les types connus, activés par défaut
+func (s *NotificationService) GetPreferences(userID uint) ([]dto.NotificationPreferenceResponse, error) {
+	preferences, err := s.notifRepo.GetPreferences(userID)
+	if err != nil {
+		return nil, errors.New("failed to fetch notification preferences")
+	}
+
+	enabled := make(map[string]bool, len(preferences))
+	for _, preference := range preferences {
+		enabled[preference.Type] = preference.Enabled
+	}
+
+	response := make([]dto.NotificationPreferenceResponse, 0, len(model.NotificationTypes))
+	for _, notificationType := range model.NotificationTypes {
+		value, ok := enabled[notificationType]
+		response = append(response, dto.NotificationPreferenceResponse{
+			Type:    notificationType,
+			Enabled: !ok || value,
+		})
+	}
+	return response, nil
+}
+
+func (s *NotificationService) UpdatePreferences(userID uint, input dto.UpdateNotificationPreferencesRequest) ([]dto.NotificationPreferenceResponse, error) {
+	preferences := make([]model.NotificationPreference, 0, len(input.Preferences))
+	for _, preference := range input.Preferences {
+		if !model.IsValidNotificationType(preference.Type) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidNotificationType, preference.Type)
+		}
+		preferences = append(preferences, model.NotificationPreference{
+			UserID:  userID,
+			Type:    preference.Type,
+			Enabled: preference.Enabled,
+		})
+	}
+
+	if err := s.notifRepo.SavePreferences(preferences); err != nil {
+		return nil, errors.New("failed to update notification preferences")
+	}
+	return s.GetPreferences(userID)
+}
+
+func toNotificationResponse(notification *model.Notification) dto.NotificationResponse {
+	response := dto.NotificationResponse{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		Payload:   json.RawMessage(notification.Payload),
+		IsRead:    notification.ReadAt != nil,
+		ReadAt:    notification.ReadAt,
+		CreatedAt: notification.CreatedAt,
+	}
+	if len(response.Payload) == 0 {
+		response.Payload = json.RawMessage("{}")
+	}
+	if notification.Actor != nil {
+		response.Actor = &dto.ReviewAuthorResponse{
+			ID:                notification.Actor.ID,
+			Username:          notification.Actor.Username,
+			ProfilePictureURL: notification.Actor.ProfilePictureURL,
+		}
+	}
+	return response
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func setupNotificationServiceTest(t *testing.T) (*gorm.DB, *NotificationService, *MovieService) {
+	utils.Log = zap.NewNop()
+	db := setupMovieServiceTestDB(t)
+	if err := db.AutoMigrate(&model.Follow{}, &model.Notification{}, &model.NotificationPreference{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	// dispatcher nil : livraison immédiate, tests déterministes
+	notificationService := NewNotificationService(repository.NewNotificationRepository(db), repository.NewFollowRepository(db), nil)
+
+	movieRepo := repository.NewMovieRepository(db)
+	movieService := NewMovieService(movieRepo, NewTMDBService(nil))
+	movieService.SetNotificationService(notificationService)
+	movieRepo.UpsertMovie(&model.Movie{TmdbID: 603, Title: "The Matrix"})
+
+	return db, notificationService, movieService
+}
+
+func TestNotificationService_FollowersNotifiedOfNewReview(t *testing.T) {
+	db, notificationService, movieService := setupNotificationServiceTest(t)
+
+	author := &model.User{Username: "author", Email: "author@example.com"}
+	fan := &model.User{Username: "fan", Email: "fan@example.com"}
+	muted := &model.User{Username: "muted", Email: "muted@example.com"}
+	db.Create(author)
+	db.Create(fan)
+	db.Create(muted)
+	db.Create(&model.Follow{FollowerID: fan.ID, FollowedID: author.ID})
+	db.Create(&model.Follow{FollowerID: muted.ID, FollowedID: author.ID})
+
+	if _, err := notificationService.UpdatePreferences(muted.ID, dto.UpdateNotificationPreferencesRequest{
+		Preferences: []dto.NotificationPreferenceInput{{Type: model.NotificationFollowedReview, Enabled: false}},
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	text := "whoa"
+	if err := movieService.LogMovie(author.ID, 603, dto.LogMovieRequest{ReviewText: &text}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// réécrire la critique ne renotifie pas
+	text = "whoa, again"
+	movieService.LogMovie(author.ID, 603, dto.LogMovieRequest{ReviewText: &text})
+
+	inbox, err := notificationService.GetNotifications(fan.ID, false, 1, 20)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if inbox.Total != 1 || inbox.UnreadCount != 1 {
+		t.Fatalf("expected exactly one unread notification, got %+v", inbox)
+	}
+	notification := inbox.Notifications[0]
+	if notification.Type != model.NotificationFollowedReview || notification.Actor == nil || notification.Actor.Username != "author" {
+		t.Errorf("unexpected notification: %+v", notification)
+	}
+	if string(notification.Payload) != `{"tmdb_id":603,"movie_title":"The Matrix","is_spoiler":false}` {
+		t.Errorf("unexpected payload: %s", notification.Payload)
+	}
+
+	for _, userID := range []uint{muted.ID, author.ID} {
+		if count, _ := notificationService.CountUnread(userID); count != 0 {
+			t.Errorf("expected user %d not to be notified, got %d", userID, count)
+		}
+	}
+}
+
+func TestNotificationService_InboxReadState(t *testing.T) {
+	db, notificationService, _ := setupNotificationServiceTest(t)
+
+	user := &model.User{Username: "reader", Email: "reader@example.com"}
+	other := &model.User{Username: "other", Email: "other@example.com"}
+	db.Create(user)
+	db.Create(other)
+
+	for i := 0; i < 3; i++ {
+		notificationService.Notify([]uint{user.ID, user.ID}, nil, model.EmailVerifiedPayload{Email: "reader@example.com"})
+	}
+	notificationService.Notify([]uint{other.ID}, nil, model.EmailVerifiedPayload{})
+
+	inbox, _ := notificationService.GetNotifications(user.ID, false, 1, 20)
+	if inbox.Total != 3 {
+		t.Fatalf("expected duplicate recipients to be collapsed, got %d notifications", inbox.Total)
+	}
+
+	// les ids d'un autre utilisateur sont ignorés
+	otherInbox, _ := notificationService.GetNotifications(other.ID, false, 1, 20)
+	ids := []uint{inbox.Notifications[0].ID, otherInbox.Notifications[0].ID}
+	marked, err := notificationService.MarkRead(user.ID, ids)
+	if err != nil || marked != 1 {
+		t.Fatalf("expected one notification marked, got %d (%v)", marked, err)
+	}
+
+	unread, _ := notificationService.GetNotifications(user.ID, true, 1, 20)
+	if unread.Total != 2 || unread.UnreadCount != 2 {
+		t.Errorf("expected 2 unread notifications, got %+v", unread)
+	}
+
+	if marked, _ := notificationService.MarkAllRead(user.ID); marked != 2 {
+		t.Errorf("expected 2 notifications marked, got %d", marked)
+	}
+	if count, _ := notificationService.CountUnread(user.ID); count != 0 {
+		t.Errorf("expected empty unread inbox, got %d", count)
+	}
+	if count, _ := notificationService.CountUnread(other.ID); count != 1 {
+		t.Errorf("expected other user's notification to stay unread, got %d", count)
+	}
+}
+
+func TestNotificationService_Preferences(t *testing.T) {
+	_, notificationService, _ := setupNotificationServiceTest(t)
+
+	preferences, _ := notificationService.GetPreferences(1)
+	if len(preferences) != len(model.NotificationTypes) {
+		t.Fatalf("expected every type to be listed, got %+v", preferences)
+	}
+	for _, preference := range preferences {
+		if !preference.Enabled {
+			t.Errorf("expected %s to be enabled by default", preference.Type)
+		}
+	}
+
+	_, err := notificationService.UpdatePreferences(1, dto.UpdateNotificationPreferencesRequest{
+		Preferences: []dto.NotificationPreferenceInput{{Type: "unknown", Enabled: false}},
+	})
+	if !errors.Is(err, ErrInvalidNotificationType) {
+		t.Errorf("expected ErrInvalidNotificationType, got %v", err)
+	}
+
+	// enregistrer deux fois met à jour la préférence existante
+	for _, enabled := range []bool{false, true, false} {
+		preferences, err = notificationService.UpdatePreferences(1, dto.UpdateNotificationPreferencesRequest{
+			Preferences: []dto.NotificationPreferenceInput{{Type: model.NotificationEmailVerified, Enabled: enabled}},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if preferences[0].Type != model.NotificationEmailVerified || preferences[0].Enabled {
+		t.Errorf("expected email notifications to be disabled, got %+v", preferences)
+	}
+}
+
+func TestNotificationService_AccountChanges(t *testing.T) {
+	f := setupServiceFixture(t)
+	users := f.createUsers("admin", "member")
+	admin, member := users[0], users[1]
+	userRepo := repository.NewUserRepository(f.db)
+	roleService := NewRoleService(repository.NewRoleRepository(f.db), userRepo, NewAuthorizationService(userRepo, repository.NewRoleRepository(f.db)))
+	roleService.SetNotificationService(f.notifications)
+
+	until := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	if _, err := f.users.SuspendUser(admin.ID, member.ID, dto.SuspendUserRequest{Reason: "spam", Until: &until}, dto.RequestMeta{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	f.users.UnsuspendUser(admin.ID, member.ID, dto.RequestMeta{})
+	f.users.ForceVerifyUser(admin.ID, member.ID, dto.RequestMeta{})
+	if _, err := roleService.AssignRole(admin.ID, member.ID, model.RoleModerator, dto.RequestMeta{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	bio, location := "cinephile", "Lyon"
+	if _, err := f.users.UpdateProfile(member.ID, dto.UpdateProfileRequest{Bio: &bio, Location: &location}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	inbox, _ := f.notifications.GetNotifications(member.ID, false, 1, 20)
+	expected := []struct{ notificationType, payload string }{
+		{model.NotificationProfileUpdated, `{"fields":["bio","location"]}`},
+		{model.NotificationRoleChanged, `{"from":"user","to":"moderator"}`},
+		{model.NotificationAccountVerified, `{"email":"member@example.com"}`},
+		{model.NotificationAccountUnsuspended, `{}`},
+		{model.NotificationAccountSuspended, `{"reason":"spam","until":"` + until.Format(time.RFC3339) + `"}`},
+	}
+	if len(inbox.Notifications) != len(expected) {
+		t.Fatalf("expected %d notifications, got %+v", len(expected), inbox.Notifications)
+	}
+	for i, want := range expected {
+		notification := inbox.Notifications[i]
+		if notification.Type != want.notificationType || string(notification.Payload) != want.payload || notification.Actor != nil {
+			t.Errorf("expected %s %s, got %s %s", want.notificationType, want.payload, notification.Type, notification.Payload)
+		}
+	}
+
+	// désactivable comme les autres types
+	f.notifications.UpdatePreferences(member.ID, dto.UpdateNotificationPreferencesRequest{
+		Preferences: []dto.NotificationPreferenceInput{{Type: model.NotificationProfileUpdated, Enabled: false}},
+	})
+	f.users.UpdateProfile(member.ID, dto.UpdateProfileRequest{Bio: &bio})
+	if count, _ := f.notifications.CountUnread(member.ID); count != int64(len(expected)) {
+		t.Errorf("expected the disabled type to be skipped, got %d unread", count)
+	}
+	if count, _ := f.notifications.CountUnread(admin.ID); count != 0 {
+		t.Errorf("expected the admin not to be notified, got %d", count)
+	}
+}
+
+func TestDispatcher_RunsTasksInBackground(t *testing.T) {
+	utils.Log = zap.NewNop()
+	dispatcher := NewDispatcher(2, 10)
+	dispatcher.Start()
+
+	var done int32
+	release := make(chan struct{})
+	for i := 0; i < 5; i++ {
+		dispatcher.Dispatch("test", func() error {
+			<-release
+			atomic.AddInt32(&done, 1)
+			return nil
+		})
+	}
+	dispatcher.Dispatch("failing", func() error { return errors.New("boom") })
+	dispatcher.Dispatch("panicking", func() error { panic("boom") })
+
+	// Dispatch ne bloque pas l'appelant pendant l'exécution des tâches
+	if atomic.LoadInt32(&done) != 0 {
+		t.Fatal("expected tasks to run asynchronously")
+	}
+	close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := dispatcher.Stop(ctx); err != nil {
+		t.Fatalf("expected queued tasks to drain, got %v", err)
+	}
+	if atomic.LoadInt32(&done) != 5 {
+		t.Errorf("expected 5 tasks to run, got %d", done)
+	}
+
+	// après l'arrêt, les tâches sont abandonnées sans paniquer
+	dispatcher.Dispatch("late", func() error {
+		t.Error("expected task dispatched after stop to be dropped")
+		return nil
+	})
+}
+
+func TestDispatcher_DropsWhenQueueFull(t *testing.T) {
+	utils.Log = zap.NewNop()
+	dispatcher := NewDispatcher(1, 1) // pas démarré : rien ne vide la file
+
+	var ran int32
+	for i := 0; i < 3; i++ {
+		dispatcher.Dispatch("test", func() error {
+			atomic.AddInt32(&ran, 1)
+			return nil
+		})
+	}
+
+	dispatcher.Start()
+	dispatcher.Stop(context.Background())
+	if ran != 1 {
+		t.Errorf("expected only the queued task to run, got %d", ran)
+	}
+}
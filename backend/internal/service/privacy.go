@@ -0,0 +1,224 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+)
+
+func (s *UserService) GetPrivacySettings(userID uint) (*dto.PrivacySettingsResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	response := dto.ToPrivacySettingsResponse(user)
+	return &response, nil
+}
+
+func (s *UserService) UpdatePrivacySettings(userID uint, input dto.UpdatePrivacySettingsRequest) (*dto.PrivacySettingsResponse, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	// UpdateFields : les booléens à false doivent être écrits explicitement
+	updates := make(map[string]interface{})
+	if input.ProfileVisibility != nil {
+		updates["profile_visibility"] = *input.ProfileVisibility
+	}
+	if input.ShowWatchlist != nil {
+		updates["show_watchlist"] = *input.ShowWatchlist
+	}
+	if input.ShowRatings != nil {
+		updates["show_ratings"] = *input.ShowRatings
+	}
+	if input.ShowDiaryDates != nil {
+		updates["show_diary_dates"] = *input.ShowDiaryDates
+	}
+	if input.Discoverable != nil {
+		updates["discoverable"] = *input.Discoverable
+	}
+	if input.Comparable != nil {
+		updates["comparable"] = *input.Comparable
+	}
+	if len(updates) > 0 {
+		if err := s.userRepo.UpdateFields(userID, updates); err != nil {
+			return nil, errors.New("failed to update privacy settings")
+		}
+	}
+
+	return s.GetPrivacySettings(userID)
+}
+
+// charge userID vu par viewerID : introuvable en cas de blocage, visible
+// selon profile_visibility (toujours pour soi-même)
+func (s *UserService) profileAccess(viewerID, userID uint) (*model.User, bool, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, false, ErrUserNotFound
+	}
+	if viewerID == userID {
+		return user, true, nil
+	}
+
+	blocked, err := s.blocks.IsBlocked(viewerID, userID)
+	if err != nil {
+		return nil, false, errors.New("failed to fetch user")
+	}
+	if blocked {
+		return nil, false, ErrUserNotFound
+	}
+
+	switch user.ProfileVisibility {
+	case model.ProfileVisibilityPrivate:
+		return user, false, nil
+	case model.ProfileVisibilityFollowers:
+		return user, s.follows.IsFollowing(viewerID, userID), nil
+	default:
+		return user, true, nil
+	}
+}
+
+// profil d'un autre utilisateur ; s'il n'est pas visible, seule la carte
+// (nom, avatar, bio) est renvoyée avec Restricted
+func (s *UserService) GetUserProfile(viewerID, userID uint) (*dto.PublicProfileResponse, error) {
+	user, visible, err := s.profileAccess(viewerID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.PublicProfileResponse{
+		User:        dto.ToPublicUserResponse(user),
+		Restricted:  !visible,
+		IsFollowing: viewerID != userID && s.follows.IsFollowing(viewerID, userID),
+	}
+	if !visible {
+		return response, nil
+	}
+
+	self := viewerID == userID
+	response.ShowWatchlist = self || user.ShowWatchlist
+	response.Stats = s.getStats(user)
+	if !self && !user.ShowRatings {
+		response.Stats.RatingDistribution = nil
+	}
+	response.RecentActivity, _ = s.movieRepo.GetRecentWatched(userID, 4)
+	response.Favorites, _ = s.movieRepo.GetFavoriteMovies(userID, 4)
+
+	return response, nil
+}
+
+func (s *UserService) GetUserFilms(viewerID, userID uint, page, limit int) (*dto.PaginatedMoviesResponse, error) {
+	user, err := s.visibleProfile(viewerID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.GetMyFilms(userID, page, limit)
+	if err != nil || viewerID == userID {
+		return response, err
+	}
+	for i := range response.Movies {
+		if !user.ShowRatings {
+			response.Movies[i].UserRating = nil
+		}
+		if !user.ShowWatchlist {
+			response.Movies[i].IsWatchlist = false
+		}
+	}
+	return response, nil
+}
+
+// critiques d'un autre utilisateur, sans celles masquées par la modération
+func (s *UserService) GetUserReviews(viewerID, userID uint, page, limit int) (*dto.PaginatedReviewsResponse, error) {
+	user, err := s.visibleProfile(viewerID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	self := viewerID == userID
+	response, err := s.userReviews(userID, self, page, limit)
+	if err != nil || self {
+		return response, err
+	}
+
+	// mêmes règles spoiler que sur la page du film
+	movieIDs := make([]uint, 0, len(response.Reviews))
+	for _, review := range response.Reviews {
+		movieIDs = append(movieIDs, review.MovieID)
+	}
+	watched, err := s.movieRepo.WatchedMovieIDs(viewerID, movieIDs)
+	if err != nil {
+		return nil, errors.New("failed to fetch user reviews")
+	}
+	for i := range response.Reviews {
+		review := &response.Reviews[i]
+		if !watched[review.MovieID] {
+			review.Content, review.ContentHTML, review.IsRedacted = redactSpoilers(review.Content, review.ContentHTML, review.IsSpoiler)
+		}
+		if !user.ShowRatings {
+			review.Rating = nil
+		}
+		if !user.ShowDiaryDates {
+			review.WatchedDate = nil
+		}
+	}
+	return response, nil
+}
+
+func (s *UserService) GetUserWatchlist(viewerID, userID uint, page, limit int) (*dto.PaginatedMoviesResponse, error) {
+	user, err := s.visibleProfile(viewerID, userID)
+	if err != nil {
+		return nil, err
+	}
+	self := viewerID == userID
+	if !self && !user.ShowWatchlist {
+		return nil, ErrWatchlistPrivate
+	}
+
+	response, err := s.GetMyWatchlist(userID, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to fetch watchlist")
+	}
+	if !self && !user.ShowRatings {
+		for i := range response.Movies {
+			response.Movies[i].UserRating = nil
+		}
+	}
+	return &response, nil
+}
+
+// comme profileAccess, mais un profil non visible est une erreur
+func (s *UserService) visibleProfile(viewerID, userID uint) (*model.User, error) {
+	user, visible, err := s.profileAccess(viewerID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, ErrProfilePrivate
+	}
+	return user, nil
+}
+
+// recherche parmi les utilisateurs qui acceptent d'être trouvés
+func (s *UserService) SearchUsers(viewerID uint, query string, limit int) (*dto.UserSearchResponse, error) {
+	response := &dto.UserSearchResponse{Users: []dto.ReviewAuthorResponse{}}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return response, nil
+	}
+
+	users, err := s.userRepo.SearchDiscoverable(viewerID, query, limit)
+	if err != nil {
+		return nil, errors.New("failed to search users")
+	}
+	for _, user := range users {
+		response.Users = append(response.Users, dto.ReviewAuthorResponse{
+			ID:                user.ID,
+			Username:          user.Username,
+			ProfilePictureURL: user.ProfilePictureURL,
+		})
+	}
+	return response, nil
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+)
+
+// owner a vu, noté et critiqué le film, a wanted en watchlist et une liste
+// publique avec les deux films ; follower suit owner, stranger non
+func setupPrivacyServiceTest(t *testing.T) (serviceFixture, *model.User, *model.User, *model.User, *model.UserList) {
+	f := setupServiceFixture(t)
+	users := f.createUsers("owner", "follower", "stranger")
+	owner, follower, stranger := users[0], users[1], users[2]
+	wanted := &model.Movie{TmdbID: 603, Title: "The Matrix"}
+	f.db.Create(wanted)
+	f.db.Create(&model.Follow{FollowerID: follower.ID, FollowedID: owner.ID})
+
+	watchedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	f.db.Create(&model.Track{UserID: owner.ID, MovieID: f.movie.ID, IsWatched: true, WatchedDate: &watchedAt})
+	f.db.Create(&model.Track{UserID: owner.ID, MovieID: wanted.ID, IsWatchlist: true})
+	f.db.Create(&model.Rate{UserID: owner.ID, MovieID: f.movie.ID, Rating: 4.5})
+	f.db.Create(&model.Review{UserID: owner.ID, MovieID: f.movie.ID, Content: "dreamy"})
+
+	list := &model.UserList{UserID: owner.ID, Title: "Favourites", IsPublic: true}
+	f.db.Create(list)
+	f.db.Create(&model.UserListEntry{ListID: list.ID, MovieID: f.movie.ID, Position: 1})
+	f.db.Create(&model.UserListEntry{ListID: list.ID, MovieID: wanted.ID, Position: 2})
+
+	return f, owner, follower, stranger, list
+}
+
+func updatePrivacy(t *testing.T, f serviceFixture, userID uint, input dto.UpdatePrivacySettingsRequest) {
+	t.Helper()
+	if _, err := f.users.UpdatePrivacySettings(userID, input); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+// critique de authorID sur le film telle que viewer la voit (nil si absente)
+func movieReviewBy(t *testing.T, f serviceFixture, viewerID, authorID uint) *dto.MovieReviewItemResponse {
+	t.Helper()
+	response := f.movieReviews(t, viewerID)
+	for i := range response.Reviews {
+		if response.Reviews[i].Author.ID == authorID {
+			return &response.Reviews[i]
+		}
+	}
+	return nil
+}
+
+func TestPrivacy_ProfileVisibility(t *testing.T) {
+	viewers := []string{"self", "follower", "stranger"}
+	tests := []struct {
+		visibility string
+		visibleTo  map[string]bool
+	}{
+		{model.ProfileVisibilityPublic, map[string]bool{"self": true, "follower": true, "stranger": true}},
+		{model.ProfileVisibilityFollowers, map[string]bool{"self": true, "follower": true, "stranger": false}},
+		{model.ProfileVisibilityPrivate, map[string]bool{"self": true, "follower": false, "stranger": false}},
+	}
+
+	for _, tt := range tests {
+		for _, viewer := range viewers {
+			t.Run(tt.visibility+"/"+viewer, func(t *testing.T) {
+				f, owner, follower, stranger, list := setupPrivacyServiceTest(t)
+				visibility := tt.visibility
+				updatePrivacy(t, f, owner.ID, dto.UpdatePrivacySettingsRequest{ProfileVisibility: &visibility})
+				viewerID := map[string]*model.User{"self": owner, "follower": follower, "stranger": stranger}[viewer].ID
+				visible := tt.visibleTo[viewer]
+
+				profile, err := f.users.GetUserProfile(viewerID, owner.ID)
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if profile.Restricted == visible || (profile.Stats != nil) != visible {
+					t.Errorf("expected visible=%v, got restricted=%v stats=%v", visible, profile.Restricted, profile.Stats)
+				}
+				if profile.User.Username != owner.Username {
+					t.Errorf("expected the basic card to always be returned, got %+v", profile.User)
+				}
+
+				reads := map[string]error{}
+				_, reads["films"] = f.users.GetUserFilms(viewerID, owner.ID, 1, 20)
+				_, reads["reviews"] = f.users.GetUserReviews(viewerID, owner.ID, 1, 20)
+				_, reads["watchlist"] = f.users.GetUserWatchlist(viewerID, owner.ID, 1, 20)
+				for name, err := range reads {
+					if visible && err != nil {
+						t.Errorf("%s: expected no error, got %v", name, err)
+					}
+					if !visible && !errors.Is(err, ErrProfilePrivate) {
+						t.Errorf("%s: expected ErrProfilePrivate, got %v", name, err)
+					}
+				}
+
+				if review := movieReviewBy(t, f, viewerID, owner.ID); (review != nil) != visible {
+					t.Errorf("expected review on movie page visible=%v, got %v", visible, review != nil)
+				}
+
+				lists, err := f.lists.GetUserLists(viewerID, owner.ID, 1, 20)
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if (lists.Total == 1) != visible {
+					t.Errorf("expected lists visible=%v, got %d", visible, lists.Total)
+				}
+				if _, err := f.lists.GetList(viewerID, list.ID, 1, 50); visible != (err == nil) {
+					t.Errorf("expected list detail visible=%v, got %v", visible, err)
+				}
+			})
+		}
+	}
+}
+
+func TestPrivacy_RatingsHidden(t *testing.T) {
+	f, owner, _, stranger, list := setupPrivacyServiceTest(t)
+	hidden := false
+	updatePrivacy(t, f, owner.ID, dto.UpdatePrivacySettingsRequest{ShowRatings: &hidden})
+
+	for _, viewer := range []*model.User{owner, stranger} {
+		self := viewer.ID == owner.ID
+
+		films, err := f.users.GetUserFilms(viewer.ID, owner.ID, 1, 20)
+		if err != nil || len(films.Movies) != 1 {
+			t.Fatalf("expected one film, got %v, %v", films, err)
+		}
+		if (films.Movies[0].UserRating != nil) != self {
+			t.Errorf("%s: unexpected film rating %v", viewer.Username, films.Movies[0].UserRating)
+		}
+
+		reviews, err := f.users.GetUserReviews(viewer.ID, owner.ID, 1, 20)
+		if err != nil || len(reviews.Reviews) != 1 {
+			t.Fatalf("expected one review, got %v, %v", reviews, err)
+		}
+		if (reviews.Reviews[0].Rating != nil) != self {
+			t.Errorf("%s: unexpected review rating %v", viewer.Username, reviews.Reviews[0].Rating)
+		}
+
+		if review := movieReviewBy(t, f, viewer.ID, owner.ID); review == nil || (review.Rating != nil) != self {
+			t.Errorf("%s: unexpected movie page review %+v", viewer.Username, review)
+		}
+
+		profile, _ := f.users.GetUserProfile(viewer.ID, owner.ID)
+		if (profile.Stats.RatingDistribution != nil) != self {
+			t.Errorf("%s: unexpected rating distribution %v", viewer.Username, profile.Stats.RatingDistribution)
+		}
+
+		detail, err := f.lists.GetList(viewer.ID, list.ID, 1, 50)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if (detail.Entries[0].OwnerStatus.Rating != nil) != self {
+			t.Errorf("%s: unexpected list entry rating %v", viewer.Username, detail.Entries[0].OwnerStatus.Rating)
+		}
+	}
+}
+
+func TestPrivacy_WatchlistHidden(t *testing.T) {
+	f, owner, _, stranger, list := setupPrivacyServiceTest(t)
+
+	if watchlist, err := f.users.GetUserWatchlist(stranger.ID, owner.ID, 1, 20); err != nil || watchlist.Total != 1 {
+		t.Fatalf("expected the public watchlist, got %v, %v", watchlist, err)
+	}
+
+	hidden := false
+	updatePrivacy(t, f, owner.ID, dto.UpdatePrivacySettingsRequest{ShowWatchlist: &hidden})
+
+	if _, err := f.users.GetUserWatchlist(stranger.ID, owner.ID, 1, 20); !errors.Is(err, ErrWatchlistPrivate) {
+		t.Errorf("expected ErrWatchlistPrivate, got %v", err)
+	}
+	if watchlist, err := f.users.GetUserWatchlist(owner.ID, owner.ID, 1, 20); err != nil || watchlist.Total != 1 {
+		t.Errorf("expected owner to keep their watchlist, got %v, %v", watchlist, err)
+	}
+	if profile, _ := f.users.GetUserProfile(stranger.ID, owner.ID); profile.ShowWatchlist {
+		t.Error("expected profile to advertise the watchlist as hidden")
+	}
+
+	for _, viewer := range []*model.User{owner, stranger} {
+		detail, err := f.lists.GetList(viewer.ID, list.ID, 1, 50)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if detail.Entries[1].OwnerStatus.IsWatchlist != (viewer.ID == owner.ID) {
+			t.Errorf("%s: unexpected watchlist status on list entry", viewer.Username)
+		}
+	}
+}
+
+func TestPrivacy_ReviewSpoilersRedacted(t *testing.T) {
+	f, owner, _, stranger, _ := setupPrivacyServiceTest(t)
+	f.db.Model(&model.Review{}).Where("user_id = ?", owner.ID).Update("is_spoiler", true)
+	twist := &model.Movie{TmdbID: 1124, Title: "The Prestige"}
+	f.db.Create(twist)
+	content := "Great ending: ||they are twins||"
+	f.db.Create(&model.Review{UserID: owner.ID, MovieID: twist.ID, Content: content, ContentHTML: utils.RenderMarkdown(content)})
+
+	byMovie := func(viewerID uint) map[uint]dto.UserReviewResponse {
+		t.Helper()
+		reviews, err := f.users.GetUserReviews(viewerID, owner.ID, 1, 20)
+		if err != nil || len(reviews.Reviews) != 2 {
+			t.Fatalf("expected two reviews, got %v, %v", reviews, err)
+		}
+		result := make(map[uint]dto.UserReviewResponse)
+		for _, review := range reviews.Reviews {
+			result[review.MovieID] = review
+		}
+		return result
+	}
+
+	reviews := byMovie(stranger.ID)
+	if spoiler := reviews[f.movie.ID]; !spoiler.IsRedacted || spoiler.Content != "" || spoiler.ContentHTML != "" {
+		t.Errorf("expected spoiler review to be blanked, got %+v", spoiler)
+	}
+	if inline := reviews[twist.ID]; !inline.IsRedacted || strings.Contains(inline.Content, "twins") || strings.Contains(inline.ContentHTML, "twins") {
+		t.Errorf("expected inline spoiler to be stripped, got %+v", inline)
+	}
+
+	// le film vu : plus rien n'est masqué
+	f.db.Create(&model.Track{UserID: stranger.ID, MovieID: f.movie.ID, IsWatched: true})
+	if spoiler := byMovie(stranger.ID)[f.movie.ID]; spoiler.IsRedacted || spoiler.Content != "dreamy" {
+		t.Errorf("expected spoiler review once watched, got %+v", spoiler)
+	}
+	if inline := byMovie(owner.ID)[twist.ID]; inline.IsRedacted || !strings.Contains(inline.Content, "twins") {
+		t.Errorf("expected the author to see their own spoilers, got %+v", inline)
+	}
+}
+
+func TestPrivacy_DiaryDatesHidden(t *testing.T) {
+	f, owner, _, stranger, _ := setupPrivacyServiceTest(t)
+
+	reviews, _ := f.users.GetUserReviews(stranger.ID, owner.ID, 1, 20)
+	if reviews.Reviews[0].WatchedDate == nil {
+		t.Fatal("expected diary date to be visible by default")
+	}
+
+	hidden := false
+	updatePrivacy(t, f, owner.ID, dto.UpdatePrivacySettingsRequest{ShowDiaryDates: &hidden})
+
+	reviews, _ = f.users.GetUserReviews(stranger.ID, owner.ID, 1, 20)
+	if reviews.Reviews[0].WatchedDate != nil {
+		t.Error("expected diary date to be hidden from other users")
+	}
+	mine, _ := f.users.GetUserReviews(owner.ID, owner.ID, 1, 20)
+	if mine.Reviews[0].WatchedDate == nil {
+		t.Error("expected owner to keep their diary dates")
+	}
+}
+
+func TestPrivacy_Discoverable(t *testing.T) {
+	f, owner, _, stranger, _ := setupPrivacyServiceTest(t)
+
+	found := func() bool {
+		response, err := f.users.SearchUsers(stranger.ID, "OWN", 10)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		for _, user := range response.Users {
+			if user.ID == owner.ID {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !found() {
+		t.Fatal("expected owner to appear in search")
+	}
+	hidden := false
+	updatePrivacy(t, f, owner.ID, dto.UpdatePrivacySettingsRequest{Discoverable: &hidden})
+	if found() {
+		t.Error("expected owner to be hidden from search")
+	}
+	// toujours joignable par son profil
+	if _, err := f.users.GetUserProfile(stranger.ID, owner.ID); err != nil {
+		t.Errorf("expected profile to stay reachable, got %v", err)
+	}
+
+	wildcard, _ := f.users.SearchUsers(stranger.ID, "%", 10)
+	if len(wildcard.Users) != 0 {
+		t.Errorf("expected LIKE wildcards to be escaped, got %d users", len(wildcard.Users))
+	}
+}
+
+func TestPrivacy_NotificationsFromPrivateActor(t *testing.T) {
+	f, owner, follower, stranger, _ := setupPrivacyServiceTest(t)
+	for _, recipient := range []*model.User{follower, stranger} {
+		f.db.Create(&model.Notification{UserID: recipient.ID, Type: model.NotificationFollowedReview, ActorID: &owner.ID, Payload: "{}"})
+	}
+
+	visibility := model.ProfileVisibilityFollowers
+	updatePrivacy(t, f, owner.ID, dto.UpdatePrivacySettingsRequest{ProfileVisibility: &visibility})
+
+	for recipient, expected := range map[*model.User]int64{follower: 1, stranger: 0} {
+		inbox, err := f.notifications.GetNotifications(recipient.ID, false, 1, 20)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if inbox.Total != expected || inbox.UnreadCount != expected {
+			t.Errorf("%s: expected %d notifications, got %d (unread %d)", recipient.Username, expected, inbox.Total, inbox.UnreadCount)
+		}
+	}
+}
+
+func TestPrivacy_UpdateSettings(t *testing.T) {
+	f, owner, _, _, _ := setupPrivacyServiceTest(t)
+
+	settings, _ := f.users.GetPrivacySettings(owner.ID)
+	if settings.ProfileVisibility != model.ProfileVisibilityPublic || !settings.ShowWatchlist || !settings.ShowRatings || !settings.ShowDiaryDates || !settings.Discoverable {
+		t.Fatalf("expected open defaults, got %+v", settings)
+	}
+
+	visibility, hidden := model.ProfileVisibilityPrivate, false
+	settings, err := f.users.UpdatePrivacySettings(owner.ID, dto.UpdatePrivacySettingsRequest{ProfileVisibility: &visibility, ShowRatings: &hidden})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if settings.ProfileVisibility != visibility || settings.ShowRatings || !settings.ShowWatchlist {
+		t.Errorf("expected only the given fields to change, got %+v", settings)
+	}
+}
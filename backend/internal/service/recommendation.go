@@ -0,0 +1,500 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	recommendationLimit         = 50
+	recommendationLikedRating   = 3.5 // film aimé : base de la similarité de contenu
+	recommendationMinSupport    = 2   // membres ayant noté les deux films d'une paire
+	recommendationContentPool   = 500 // films candidats par similarité de contenu
+	recommendationPopularMin    = 3   // notes minimum d'un film populaire
+	recommendationCollaborative = 0.6 // poids du filtrage collaboratif, le reste au contenu
+	recommendationRefreshEvery  = 15 * time.Minute
+	recommendationMaxAge        = 24 * time.Hour // les notes des autres membres évoluent aussi
+	recommendationBatch         = 200
+)
+
+// poids d'une caractéristique commune dans la similarité de contenu
+var recommendationFeatureWeights = map[string]float64{
+	model.RecommendationReasonDirector: 3,
+	model.RecommendationReasonWriter:   2,
+	model.RecommendationReasonActor:    1.5,
+	model.RecommendationReasonGenre:    0.5,
+}
+
+// recommandations personnalisées, précalculées par une tâche périodique :
+//   - filtrage collaboratif item-item (cosinus ajusté) sur les notes de tous
+//     les membres découvrables ;
+//   - similarité de contenu (genres, réalisateurs, scénaristes, têtes
+//     d'affiche) avec les films aimés.
+//
+// les films déjà vus, notés ou critiqués sont exclus
+type RecommendationService struct {
+	recoRepo   *repository.RecommendationRepository
+	userRepo   repository.UserRepository
+	movies     *MovieService
+	dispatcher *Dispatcher
+}
+
+// movies sert à importer les métadonnées manquantes des films aimés
+func NewRecommendationService(recoRepo *repository.RecommendationRepository, userRepo repository.UserRepository, movies *MovieService) *RecommendationService {
+	return &RecommendationService{
+		recoRepo: recoRepo,
+		userRepo: userRepo,
+		movies:   movies,
+	}
+}
+
+func (s *RecommendationService) SetDispatcher(dispatcher *Dispatcher) {
+	s.dispatcher = dispatcher
+}
+
+// enregistre le recalcul périodique des recommandations
+func (s *RecommendationService) Schedule(scheduler *Scheduler) {
+	scheduler.Every("recommendations.refresh", recommendationRefreshEvery, s.refreshDue)
+}
+
+// à appeler après chaque modification de l'historique (suivi, note) ;
+// le prochain passage de la tâche recalcule les recommandations
+func (s *RecommendationService) HistoryChanged(userID uint) {
+	if s == nil {
+		return
+	}
+	if err := s.recoRepo.MarkChanged(userID, time.Now()); err != nil {
+		utils.Log.Warn("Failed to flag recommendations for refresh", zap.Uint("user_id", userID), zap.Error(err))
+	}
+}
+
+func (s *RecommendationService) GetRecommendations(userID uint, page, limit int) (*dto.RecommendationsResponse, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+	run, err := s.recoRepo.GetRun(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("failed to fetch recommendations")
+	}
+
+	// jamais calculées : premier calcul en tâche de fond (immédiat sans dispatcher)
+	if run == nil || run.ComputedAt == nil {
+		s.dispatcher.Dispatch("recommendations.compute", func() error {
+			return s.Refresh(userID)
+		})
+		if run, err = s.recoRepo.GetRun(userID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("failed to fetch recommendations")
+		}
+	}
+
+	recommendations, total, err := s.recoRepo.List(userID, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to fetch recommendations")
+	}
+
+	response := &dto.RecommendationsResponse{
+		Recommendations: make([]dto.RecommendationResponse, 0, len(recommendations)),
+		Pending:         run == nil || run.ComputedAt == nil,
+		Total:           total,
+		Page:            page,
+		Limit:           limit,
+		TotalPages:      int(math.Ceil(float64(total) / float64(limit))),
+	}
+	if run != nil {
+		response.ComputedAt = run.ComputedAt
+	}
+	for i := range recommendations {
+		response.Recommendations = append(response.Recommendations, dto.ToRecommendationResponse(&recommendations[i]))
+	}
+	return response, nil
+}
+
+// CALCUL
+
+// recalcule et remplace les recommandations de l'utilisateur
+func (s *RecommendationService) Refresh(userID uint) error {
+	startedAt := time.Now()
+	recommendations, err := s.compute(userID)
+	if err != nil {
+		return err
+	}
+	for i := range recommendations {
+		recommendations[i].ComputedAt = startedAt
+	}
+	return s.recoRepo.Replace(userID, recommendations, startedAt)
+}
+
+// un lot de membres à jour par passage ; un échec n'arrête pas le lot
+func (s *RecommendationService) refreshDue() error {
+	userIDs, err := s.recoRepo.UsersToRefresh(time.Now().Add(-recommendationMaxAge), recommendationBatch)
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		if err := s.Refresh(userID); err != nil {
+			utils.Log.Warn("Failed to refresh recommendations", zap.Uint("user_id", userID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+type recommendationCandidate struct {
+	movieID       uint
+	collaborative float64
+	content       float64
+	score         float64
+	rated         *model.RecommendationReason // film noté qui contribue le plus
+	ratedWeight   float64
+	similar       *model.RecommendationReason // caractéristique commune la plus forte
+}
+
+type recommendationCandidates map[uint]*recommendationCandidate
+
+func (c recommendationCandidates) get(movieID uint) *recommendationCandidate {
+	candidate, ok := c[movieID]
+	if !ok {
+		candidate = &recommendationCandidate{movieID: movieID}
+		c[movieID] = candidate
+	}
+	return candidate
+}
+
+func (s *RecommendationService) compute(userID uint) ([]model.Recommendation, error) {
+	seeds, err := s.recoRepo.Seeds(userID)
+	if err != nil {
+		return nil, err
+	}
+	seenIDs, err := s.recoRepo.SeenMovieIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[uint]bool, len(seenIDs))
+	for _, id := range seenIDs {
+		seen[id] = true
+	}
+
+	candidates := make(recommendationCandidates)
+	if err := s.collaborative(userID, seeds, seen, candidates); err != nil {
+		return nil, err
+	}
+	if err := s.content(userID, seeds, seen, candidates); err != nil {
+		return nil, err
+	}
+
+	// scores normalisés puis combinés ; sans candidat, films populaires
+	var maxCollaborative, maxContent float64
+	for _, candidate := range candidates {
+		maxCollaborative = math.Max(maxCollaborative, candidate.collaborative)
+		maxContent = math.Max(maxContent, candidate.content)
+	}
+	ranked := make([]*recommendationCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if maxCollaborative > 0 {
+			candidate.score += recommendationCollaborative * candidate.collaborative / maxCollaborative
+		}
+		if maxContent > 0 {
+			candidate.score += (1 - recommendationCollaborative) * candidate.content / maxContent
+		}
+		if candidate.score > 0 {
+			ranked = append(ranked, candidate)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].movieID < ranked[j].movieID
+	})
+	if len(ranked) == 0 {
+		return s.popular(userID, seen)
+	}
+
+	return s.keepExisting(ranked, func(candidate *recommendationCandidate) model.Recommendation {
+		reasons := []model.RecommendationReason{}
+		if candidate.rated != nil && candidate.collaborative > 0 {
+			reasons = append(reasons, *candidate.rated)
+		}
+		if candidate.similar != nil && candidate.content > 0 {
+			reasons = append(reasons, *candidate.similar)
+		}
+		return model.Recommendation{
+			UserID:  userID,
+			MovieID: candidate.movieID,
+			Score:   math.Round(candidate.score*1000) / 1000,
+			Reasons: reasons,
+		}
+	})
+}
+
+// filtrage collaboratif item-item : similarité cosinus entre films, sur les
+// notes centrées sur la moyenne de chaque membre, pondérée par l'écart de la
+// note de l'utilisateur à sa propre moyenne
+func (s *RecommendationService) collaborative(userID uint, seeds []repository.RecommendationSeed, seen map[uint]bool, candidates recommendationCandidates) error {
+	var rated []repository.RecommendationSeed
+	for _, seed := range seeds {
+		if seed.Rating != nil {
+			rated = append(rated, seed)
+		}
+	}
+	if len(rated) == 0 {
+		return nil
+	}
+
+	rows, err := s.recoRepo.CoRatings(userID)
+	if err != nil {
+		return err
+	}
+
+	// notes centrées : membre -> film -> écart à sa moyenne
+	byUser := make(map[uint]map[uint]float64)
+	for _, row := range rows {
+		if byUser[row.UserID] == nil {
+			byUser[row.UserID] = make(map[uint]float64)
+		}
+		byUser[row.UserID][row.MovieID] = row.Rating
+	}
+	byMovie := make(map[uint]map[uint]float64)
+	norms := make(map[uint]float64)
+	for memberID, ratings := range byUser {
+		var sum float64
+		for _, rating := range ratings {
+			sum += rating
+		}
+		mean := sum / float64(len(ratings))
+		for movieID, rating := range ratings {
+			centered := rating - mean
+			ratings[movieID] = centered
+			if byMovie[movieID] == nil {
+				byMovie[movieID] = make(map[uint]float64)
+			}
+			byMovie[movieID][memberID] = centered
+			norms[movieID] += centered * centered
+		}
+	}
+	for movieID, norm := range norms {
+		norms[movieID] = math.Sqrt(norm)
+	}
+
+	// peu de notes : écart au milieu de l'échelle plutôt qu'à la moyenne
+	baseline := 2.5
+	if len(rated) >= 3 {
+		var sum float64
+		for _, seed := range rated {
+			sum += float64(*seed.Rating)
+		}
+		baseline = sum / float64(len(rated))
+	}
+
+	for _, seed := range rated {
+		deviation := float64(*seed.Rating) - baseline
+		if deviation == 0 || norms[seed.MovieID] == 0 {
+			continue
+		}
+
+		dots := make(map[uint]float64)
+		support := make(map[uint]int)
+		for memberID, seedRating := range byMovie[seed.MovieID] {
+			for movieID, rating := range byUser[memberID] {
+				if seen[movieID] {
+					continue
+				}
+				dots[movieID] += seedRating * rating
+				support[movieID]++
+			}
+		}
+
+		for movieID, dot := range dots {
+			if support[movieID] < recommendationMinSupport || norms[movieID] == 0 {
+				continue
+			}
+			similarity := dot / (norms[seed.MovieID] * norms[movieID])
+			if similarity <= 0 {
+				continue
+			}
+			candidate := candidates.get(movieID)
+			contribution := similarity * deviation
+			candidate.collaborative += contribution
+			if contribution > candidate.ratedWeight {
+				candidate.ratedWeight = contribution
+				candidate.rated = &model.RecommendationReason{
+					Kind:   model.RecommendationReasonRated,
+					TmdbID: seed.TmdbID,
+					Title:  seed.Title,
+					Rating: seed.Rating,
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// similarité de contenu avec les films aimés (bien notés ou favoris)
+func (s *RecommendationService) content(userID uint, seeds []repository.RecommendationSeed, seen map[uint]bool, candidates recommendationCandidates) error {
+	liked := make(map[uint]repository.RecommendationSeed)
+	weights := make(map[uint]float64)
+	var likedIDs, unsynced []uint
+	for _, seed := range seeds {
+		weight := 0.0
+		if seed.Rating != nil && *seed.Rating >= recommendationLikedRating {
+			weight = float64(*seed.Rating) - 3
+		}
+		if seed.Favorite {
+			weight = math.Max(weight, 1)
+		}
+		if weight == 0 {
+			continue
+		}
+		liked[seed.MovieID], weights[seed.MovieID] = seed, weight
+		likedIDs = append(likedIDs, seed.MovieID)
+		if seed.MetadataSynced == nil {
+			unsynced = append(unsynced, seed.MovieID)
+		}
+	}
+	if len(likedIDs) == 0 {
+		return nil
+	}
+	if len(unsynced) > 0 && s.movies != nil {
+		if err := s.movies.SyncMissingMetadata(unsynced); err != nil {
+			utils.Log.Warn("Metadata sync incomplete", zap.Uint("user_id", userID), zap.Error(err))
+		}
+	}
+
+	features, err := s.recoRepo.Features(likedIDs)
+	if err != nil {
+		return err
+	}
+	// profil : poids de chaque caractéristique, et le film aimé qui l'illustre le mieux
+	profile := make(map[string]float64)
+	examples := make(map[string]repository.RecommendationSeed)
+	var personIDs []uint
+	people := make(map[uint]bool)
+	for _, feature := range features {
+		key := featureKey(feature)
+		weight := weights[feature.MovieID]
+		profile[key] += weight * recommendationFeatureWeights[feature.Kind]
+		if example, ok := examples[key]; !ok || weight > weights[example.MovieID] {
+			examples[key] = liked[feature.MovieID]
+		}
+		if feature.Kind != model.RecommendationReasonGenre && !people[feature.ID] {
+			people[feature.ID] = true
+			personIDs = append(personIDs, feature.ID)
+		}
+	}
+
+	pool, err := s.recoRepo.MoviesWithPeople(personIDs, recommendationContentPool)
+	if err != nil {
+		return err
+	}
+	var candidateIDs []uint
+	inPool := make(map[uint]bool)
+	for _, id := range pool {
+		if !seen[id] && !inPool[id] {
+			inPool[id] = true
+			candidateIDs = append(candidateIDs, id)
+		}
+	}
+	for id := range candidates {
+		if !inPool[id] {
+			inPool[id] = true
+			candidateIDs = append(candidateIDs, id)
+		}
+	}
+
+	candidateFeatures, err := s.recoRepo.Features(candidateIDs)
+	if err != nil {
+		return err
+	}
+	byMovie := make(map[uint][]repository.MovieFeature)
+	for _, feature := range candidateFeatures {
+		byMovie[feature.MovieID] = append(byMovie[feature.MovieID], feature)
+	}
+
+	for movieID, movieFeatures := range byMovie {
+		var total, best float64
+		var bestFeature *repository.MovieFeature
+		for i, feature := range movieFeatures {
+			weight := profile[featureKey(feature)]
+			total += weight
+			if weight > best {
+				best, bestFeature = weight, &movieFeatures[i]
+			}
+		}
+		if total == 0 {
+			continue
+		}
+
+		// les films au générique fourni ne sont pas avantagés
+		candidate := candidates.get(movieID)
+		candidate.content = total / math.Sqrt(float64(len(movieFeatures)))
+		example := examples[featureKey(*bestFeature)]
+		candidate.similar = &model.RecommendationReason{
+			Kind:   bestFeature.Kind,
+			TmdbID: example.TmdbID,
+			Title:  example.Title,
+			Name:   bestFeature.Name,
+		}
+	}
+	return nil
+}
+
+// une même personne compte séparément comme réalisateur et comme acteur
+func featureKey(feature repository.MovieFeature) string {
+	return feature.Kind + ":" + strconv.FormatUint(uint64(feature.ID), 10)
+}
+
+// sans historique exploitable : films les mieux notés par les membres
+func (s *RecommendationService) popular(userID uint, seen map[uint]bool) ([]model.Recommendation, error) {
+	rows, err := s.recoRepo.TopRated(recommendationPopularMin, recommendationLimit+len(seen))
+	if err != nil {
+		return nil, err
+	}
+	ranked := make([]*recommendationCandidate, 0, len(rows))
+	for _, row := range rows {
+		if !seen[row.MovieID] {
+			ranked = append(ranked, &recommendationCandidate{movieID: row.MovieID, score: row.Rating / 5})
+		}
+	}
+	return s.keepExisting(ranked, func(candidate *recommendationCandidate) model.Recommendation {
+		return model.Recommendation{
+			UserID:  userID,
+			MovieID: candidate.movieID,
+			Score:   math.Round(candidate.score*1000) / 1000,
+			Reasons: []model.RecommendationReason{{Kind: model.RecommendationReasonPopular}},
+		}
+	})
+}
+
+// retient les recommandationLimit premiers films encore en base, dans l'ordre
+func (s *RecommendationService) keepExisting(ranked []*recommendationCandidate, build func(*recommendationCandidate) model.Recommendation) ([]model.Recommendation, error) {
+	recommendations := make([]model.Recommendation, 0, recommendationLimit)
+	for start := 0; start < len(ranked) && len(recommendations) < recommendationLimit; start += recommendationLimit {
+		end := min(start+recommendationLimit, len(ranked))
+		ids := make([]uint, 0, end-start)
+		for _, candidate := range ranked[start:end] {
+			ids = append(ids, candidate.movieID)
+		}
+		movies, err := s.recoRepo.Movies(ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range ranked[start:end] {
+			if _, ok := movies[candidate.movieID]; !ok || len(recommendations) == recommendationLimit {
+				continue
+			}
+			recommendation := build(candidate)
+			recommendation.Position = len(recommendations) + 1
+			recommendations = append(recommendations, recommendation)
+		}
+	}
+	return recommendations, nil
+}
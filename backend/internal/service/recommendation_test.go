@@ -0,0 +1,141 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+)
+
+func TestRecommendationService_Recommendations(t *testing.T) {
+	// métadonnées déjà importées : pas d'appel TMDB
+	db, _ := setupCatalogTest(t, nil, &model.Recommendation{}, &model.RecommendationRun{})
+	synced := time.Now()
+	mann := &model.Person{TmdbID: 638, Name: "Michael Mann"}
+	jeunet := &model.Person{TmdbID: 2419, Name: "Jean-Pierre Jeunet"}
+	db.Create(mann)
+	db.Create(jeunet)
+	crime := model.Genre{ID: 80, Name: "Crime"}
+	comedy := model.Genre{ID: 35, Name: "Comedy"}
+	movie := func(tmdbID int, title string, director *model.Person, genre model.Genre) *model.Movie {
+		m := &model.Movie{TmdbID: tmdbID, Title: title, MetadataSyncedAt: &synced, Genres: []model.Genre{genre}}
+		db.Create(m)
+		db.Create(&model.MovieCrew{MovieID: m.ID, PersonID: director.ID, Job: "Director", Department: "Directing"})
+		return m
+	}
+	heat := movie(400, "Heat", mann, crime)
+	collateral := movie(401, "Collateral", mann, crime)
+	thief := movie(402, "Thief", mann, crime)
+	manhunter := movie(403, "Manhunter", mann, crime)
+	amelie := movie(404, "Amélie", jeunet, comedy)
+	delicatessen := movie(405, "Delicatessen", jeunet, comedy)
+	bland := movie(406, "Bland", jeunet, comedy)
+
+	user := &model.User{Username: "cinephile", Email: "cinephile@example.com"}
+	newcomer := &model.User{Username: "newcomer", Email: "newcomer@example.com"}
+	db.Create(user)
+	db.Create(newcomer)
+	rate := func(userID uint, m *model.Movie, rating float32) {
+		db.Create(&model.Rate{UserID: userID, MovieID: m.ID, Rating: rating})
+		db.Create(&model.Track{UserID: userID, MovieID: m.ID, IsWatched: true})
+	}
+	member := func(name string, ratings map[*model.Movie]float32) *model.User {
+		u := &model.User{Username: name, Email: name + "@example.com"}
+		db.Create(u)
+		for m, rating := range ratings {
+			rate(u.ID, m, rating)
+		}
+		return u
+	}
+	member("heatfan", map[*model.Movie]float32{heat: 5, collateral: 5, amelie: 1, delicatessen: 1})
+	member("crimefan", map[*model.Movie]float32{heat: 4.5, collateral: 4, amelie: 2, delicatessen: 2})
+	// ses notes ne nourrissent pas les recommandations des autres
+	hidden := member("hidden", map[*model.Movie]float32{heat: 5, delicatessen: 5, amelie: 1})
+	db.Model(hidden).Update("discoverable", false)
+
+	rate(user.ID, heat, 5)
+	rate(user.ID, amelie, 2)
+	rate(user.ID, bland, 3)
+	db.Create(&model.Track{UserID: user.ID, MovieID: manhunter.ID, IsWatched: true})
+
+	// dispatcher nil : le premier calcul est immédiat
+	recommendationService := NewRecommendationService(repository.NewRecommendationRepository(db), repository.NewUserRepository(db), nil)
+	response, err := recommendationService.GetRecommendations(user.ID, 1, 20)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if response.Pending || response.ComputedAt == nil {
+		t.Errorf("expected computed recommendations, got %+v", response)
+	}
+
+	// Collateral : apprécié par les membres qui aiment Heat, et même réalisateur ;
+	// Thief : même réalisateur seulement ; Delicatessen plaît à ceux qui n'aiment pas Amélie
+	if len(response.Recommendations) != 2 {
+		t.Fatalf("expected 2 recommendations, got %+v", response.Recommendations)
+	}
+	first, second := response.Recommendations[0], response.Recommendations[1]
+	if first.TmdbID != collateral.TmdbID || second.TmdbID != thief.TmdbID {
+		t.Fatalf("expected Collateral then Thief, got %d then %d", first.TmdbID, second.TmdbID)
+	}
+	if len(first.Reasons) != 2 || first.Reasons[0].Text != "Because you rated Heat 5.0" || first.Reasons[1].Text != "Directed by Michael Mann, like Heat" {
+		t.Errorf("unexpected reasons for Collateral: %+v", first.Reasons)
+	}
+	if len(second.Reasons) != 1 || second.Reasons[0].Kind != model.RecommendationReasonDirector || second.Score >= first.Score {
+		t.Errorf("unexpected Thief recommendation: %+v", second)
+	}
+
+	// historique modifié : recalculé au prochain passage de la tâche
+	due := func() bool {
+		t.Helper()
+		ids, err := recommendationService.recoRepo.UsersToRefresh(time.Now().Add(-recommendationMaxAge), recommendationBatch)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		for _, id := range ids {
+			if id == user.ID {
+				return true
+			}
+		}
+		return false
+	}
+	if due() {
+		t.Errorf("expected fresh recommendations not to be due")
+	}
+	rate(user.ID, collateral, 4)
+	recommendationService.HistoryChanged(user.ID)
+	if !due() {
+		t.Fatalf("expected recommendations due after a history change")
+	}
+	if err := recommendationService.refreshDue(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if due() {
+		t.Errorf("expected recommendations refreshed")
+	}
+	response, _ = recommendationService.GetRecommendations(user.ID, 1, 20)
+	if len(response.Recommendations) != 1 || response.Recommendations[0].TmdbID != thief.TmdbID {
+		t.Errorf("expected only Thief once Collateral is rated, got %+v", response.Recommendations)
+	}
+
+	// sans historique : films les mieux notés par au moins 3 membres découvrables
+	response, err = recommendationService.GetRecommendations(newcomer.ID, 1, 20)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var popular []int
+	for _, recommendation := range response.Recommendations {
+		popular = append(popular, recommendation.TmdbID)
+		if recommendation.Reasons[0].Text != "Highly rated by FrameRate members" {
+			t.Errorf("unexpected reason %+v", recommendation.Reasons)
+		}
+	}
+	if len(popular) != 3 || popular[0] != heat.TmdbID || popular[1] != collateral.TmdbID || popular[2] != amelie.TmdbID {
+		t.Errorf("expected Heat, Collateral, Amélie, got %v", popular)
+	}
+
+	if _, err := recommendationService.GetRecommendations(9999, 1, 20); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrReviewNotFound      = errors.New("review not found")
+	ErrCannotLikeOwnReview = errors.New("you cannot like your own review")
+	ErrReviewEmpty         = errors.New("review cannot be empty")
+	ErrReviewHistoryDenied = errors.New("only the author or a moderator can view this review's history")
+)
+
+const (
+	popularReviewsWindow     = 7 * 24 * time.Hour
+	popularReviewsCandidates = 200
+	popularReviewsCacheTTL   = 10 * time.Minute
+	popularReviewsCacheKey   = "reviews:popular"
+	// décroissance façon Hacker News : score = likes / (âge en heures + 2)^gravity
+	popularReviewsGravity = 1.5
+)
+
+type ReviewService struct {
+	reviewRepo *repository.ReviewRepository
+	movieRepo  *repository.MovieRepository
+	cache      *CacheService
+	authz      *AuthorizationService
+	blocks     *BlockService
+	now        func() time.Time
+}
+
+func NewReviewService(reviewRepo *repository.ReviewRepository, movieRepo *repository.MovieRepository, cache *CacheService) *ReviewService {
+	return &ReviewService{
+		reviewRepo: reviewRepo,
+		movieRepo:  movieRepo,
+		cache:      cache,
+		now:        time.Now,
+	}
+}
+
+func (s *ReviewService) SetAuthorizationService(authz *AuthorizationService) {
+	s.authz = authz
+}
+
+func (s *ReviewService) SetBlockService(blocks *BlockService) {
+	s.blocks = blocks
+}
+
+// critiques publiques d'un film ; les spoilers sont masqués sauf si le viewer
+// le demande (showSpoilers) ou a déjà vu le film
+func (s *ReviewService) GetMovieReviews(viewerID uint, tmdbID int, filter repository.MovieReviewFilter, showSpoilers bool, page, limit int) (*dto.PaginatedMovieReviewsResponse, error) {
+	response := &dto.PaginatedMovieReviewsResponse{
+		Reviews: []dto.MovieReviewItemResponse{},
+		Page:    page,
+		Limit:   limit,
+	}
+
+	movie, err := s.movieRepo.GetMovieByTmdbID(tmdbID)
+	if err != nil {
+		// film jamais loggé sur FrameRate => aucune critique
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response, nil
+		}
+		return nil, errors.New("failed to fetch movie")
+	}
+
+	filter.ViewerID = viewerID
+	results, total, err := s.reviewRepo.ListForMovie(movie.ID, filter, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to fetch reviews")
+	}
+
+	if !showSpoilers {
+		if showSpoilers, err = s.reviewRepo.HasWatched(viewerID, movie.ID); err != nil {
+			return nil, errors.New("failed to fetch reviews")
+		}
+	}
+
+	for _, result := range results {
+		response.Reviews = append(response.Reviews, toMovieReviewItem(result, viewerID, showSpoilers))
+	}
+
+	response.Total = total
+	response.TotalPages = int((total + int64(limit) - 1) / int64(limit))
+
+	return response, nil
+}
+
+// like d'une critique, identifiée par le film et son auteur
+func (s *ReviewService) LikeReview(userID uint, tmdbID int, reviewUserID uint) (*dto.ReviewLikeResponse, error) {
+	if userID == reviewUserID {
+		return nil, ErrCannotLikeOwnReview
+	}
+
+	movieID, err := s.findReview(userID, tmdbID, reviewUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.reviewRepo.Like(userID, reviewUserID, movieID); err != nil {
+		return nil, errors.New("failed to like review")
+	}
+	return s.likeResponse(true, reviewUserID, movieID)
+}
+
+func (s *ReviewService) UnlikeReview(userID uint, tmdbID int, reviewUserID uint) (*dto.ReviewLikeResponse, error) {
+	movieID, err := s.findReview(userID, tmdbID, reviewUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.reviewRepo.Unlike(userID, reviewUserID, movieID); err != nil {
+		return nil, errors.New("failed to unlike review")
+	}
+	return s.likeResponse(false, reviewUserID, movieID)
+}
+
+// critiques de la semaine classées par likes avec décroissance temporelle ;
+// le classement est mis en cache, le masquage des spoilers et des auteurs
+// bloqués ou masqués reste propre au viewer
+func (s *ReviewService) GetPopularReviews(viewerID uint, limit int) ([]dto.PopularReviewResponse, error) {
+	candidates, err := s.rankPopularReviews()
+	if err != nil {
+		return nil, err
+	}
+
+	hidden, err := s.blocks.HiddenUserIDs(viewerID)
+	if err != nil {
+		return nil, errors.New("failed to fetch popular reviews")
+	}
+	ranked := make([]repository.PopularReviewCandidate, 0, limit)
+	for _, candidate := range candidates {
+		if len(ranked) == limit {
+			break
+		}
+		if !hidden[candidate.UserID] {
+			ranked = append(ranked, candidate)
+		}
+	}
+
+	movieIDs := make([]uint, 0, len(ranked))
+	for _, candidate := range ranked {
+		movieIDs = append(movieIDs, candidate.MovieID)
+	}
+
+	watched, err := s.reviewRepo.WatchedMovieIDs(viewerID, movieIDs)
+	if err != nil {
+		return nil, errors.New("failed to fetch popular reviews")
+	}
+	liked, err := s.reviewRepo.LikedReviews(viewerID, movieIDs)
+	if err != nil {
+		return nil, errors.New("failed to fetch popular reviews")
+	}
+
+	responses := make([]dto.PopularReviewResponse, 0, len(ranked))
+	for _, candidate := range ranked {
+		candidate.LikedByViewer = liked[repository.ReviewKey{UserID: candidate.UserID, MovieID: candidate.MovieID}]
+
+		responses = append(responses, dto.PopularReviewResponse{
+			MovieReviewItemResponse: toMovieReviewItem(candidate.MovieReviewResult, viewerID, watched[candidate.MovieID]),
+			Movie: dto.PopularReviewMovieResponse{
+				TmdbID:      candidate.TmdbID,
+				Title:       candidate.Title,
+				ReleaseYear: candidate.ReleaseYear,
+				PosterURL:   candidate.PosterURL,
+			},
+		})
+	}
+
+	return responses, nil
+}
+
+// historique des modifications d'une critique, visible par son auteur et
+// par les modérateurs (litiges sur un signalement)
+func (s *ReviewService) GetReviewHistory(viewerID uint, tmdbID int, reviewUserID uint) (*dto.ReviewHistoryResponse, error) {
+	if viewerID != reviewUserID {
+		allowed := false
+		if s.authz != nil {
+			var err error
+			if allowed, err = s.authz.HasPermission(viewerID, model.PermReviewsModerate); err != nil {
+				return nil, errors.New("failed to check permissions")
+			}
+		}
+		if !allowed {
+			return nil, ErrReviewHistoryDenied
+		}
+	}
+
+	movie, err := s.movieRepo.GetMovieByTmdbID(tmdbID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, errors.New("failed to fetch movie")
+	}
+
+	// la modération voit aussi l'historique des critiques supprimées
+	moderator := viewerID != reviewUserID
+	review, err := s.reviewRepo.Get(reviewUserID, movie.ID)
+	if err != nil && (!moderator || !errors.Is(err, gorm.ErrRecordNotFound)) {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, errors.New("failed to fetch review")
+	}
+
+	revisions, err := s.reviewRepo.ListRevisions(reviewUserID, movie.ID, moderator)
+	if err != nil {
+		return nil, errors.New("failed to fetch review history")
+	}
+
+	response := &dto.ReviewHistoryResponse{
+		Revisions: make([]dto.ReviewRevisionResponse, 0, len(revisions)),
+	}
+	if review != nil {
+		response.Current = dto.ReviewRevisionResponse{
+			Content:   review.Content,
+			IsSpoiler: review.IsSpoiler,
+			WrittenAt: review.CreatedAt,
+		}
+		if review.EditedAt != nil {
+			response.Current.WrittenAt = *review.EditedAt
+		}
+	} else {
+		// supprimée : sa dernière version, archivée à la suppression
+		if len(revisions) == 0 {
+			return nil, ErrReviewNotFound
+		}
+		response.Current = toReviewRevisionResponse(revisions[0])
+		response.Deleted = true
+		revisions = revisions[1:]
+	}
+	for _, revision := range revisions {
+		response.Revisions = append(response.Revisions, toReviewRevisionResponse(revision))
+	}
+
+	return response, nil
+}
+
+func toReviewRevisionResponse(revision model.ReviewRevision) dto.ReviewRevisionResponse {
+	replacedAt := revision.ReplacedAt
+	return dto.ReviewRevisionResponse{
+		Content:    revision.Content,
+		IsSpoiler:  revision.IsSpoiler,
+		WrittenAt:  revision.WrittenAt,
+		ReplacedAt: &replacedAt,
+	}
+}
+
+// classement complet des candidates, commun à tous les viewers
+func (s *ReviewService) rankPopularReviews() ([]repository.PopularReviewCandidate, error) {
+	cacheKey := popularReviewsCacheKey
+
+	var ranked []repository.PopularReviewCandidate
+	if s.cache != nil {
+		if found, err := s.cache.Get(context.Background(), cacheKey, &ranked); err == nil && found {
+			return ranked, nil
+		}
+	}
+
+	now := s.now()
+	candidates, err := s.reviewRepo.ListPopularCandidates(now.Add(-popularReviewsWindow), popularReviewsCandidates)
+	if err != nil {
+		return nil, errors.New("failed to fetch popular reviews")
+	}
+
+	score := func(candidate repository.PopularReviewCandidate) float64 {
+		ageHours := math.Max(now.Sub(candidate.CreatedAt).Hours(), 0)
+		return float64(candidate.LikeCount) / math.Pow(ageHours+2, popularReviewsGravity)
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return score(candidates[a]) > score(candidates[b])
+	})
+	ranked = candidates
+
+	if s.cache != nil {
+		_ = s.cache.Set(context.Background(), cacheKey, ranked, popularReviewsCacheTTL)
+	}
+
+	return ranked, nil
+}
+
+// introuvable si l'auteur et le viewer se bloquent
+func (s *ReviewService) findReview(viewerID uint, tmdbID int, reviewUserID uint) (uint, error) {
+	movie, err := s.movieRepo.GetMovieByTmdbID(tmdbID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrReviewNotFound
+		}
+		return 0, errors.New("failed to fetch movie")
+	}
+
+	exists, err := s.reviewRepo.Exists(viewerID, reviewUserID, movie.ID)
+	if err != nil {
+		return 0, errors.New("failed to fetch review")
+	}
+	if !exists {
+		return 0, ErrReviewNotFound
+	}
+	return movie.ID, nil
+}
+
+func (s *ReviewService) likeResponse(liked bool, reviewUserID, movieID uint) (*dto.ReviewLikeResponse, error) {
+	count, err := s.reviewRepo.CountLikes(reviewUserID, movieID)
+	if err != nil {
+		return nil, errors.New("failed to count likes")
+	}
+	return &dto.ReviewLikeResponse{Liked: liked, LikeCount: count}, nil
+}
+
+// sa propre critique n'est jamais masquée
+func toMovieReviewItem(result repository.MovieReviewResult, viewerID uint, showSpoilers bool) dto.MovieReviewItemResponse {
+	item := dto.MovieReviewItemResponse{
+		Author: dto.ReviewAuthorResponse{
+			ID:                result.UserID,
+			Username:          result.Username,
+			ProfilePictureURL: result.ProfilePictureURL,
+		},
+		Rating:      result.Rating,
+		Content:     result.Content,
+		ContentHTML: result.ContentHTML,
+		IsSpoiler:   result.IsSpoiler,
+		LikeCount:   result.LikeCount,
+		LikedByMe:   result.LikedByViewer,
+		IsEdited:    result.EditedAt != nil,
+		EditedAt:    result.EditedAt,
+		CreatedAt:   result.CreatedAt,
+		UpdatedAt:   result.UpdatedAt,
+	}
+
+	if showSpoilers || result.UserID == viewerID {
+		return item
+	}
+	item.Content, item.ContentHTML, item.IsRedacted = redactSpoilers(result.Content, result.ContentHTML, result.IsSpoiler)
+	return item
+}
+
+// critique spoiler vidée, passages ||spoiler|| masqués sinon ; redacted si
+// le contenu a été modifié
+func redactSpoilers(content, contentHTML string, isSpoiler bool) (string, string, bool) {
+	switch {
+	case isSpoiler:
+		return "", "", true
+	case utils.HasSpoilerSpans(content):
+		return utils.RedactSpoilerSpans(content), utils.RenderMarkdownRedacted(content), true
+	}
+	return content, contentHTML, false
+}
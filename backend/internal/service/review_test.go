@@ -0,0 +1,319 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+func TestReviewService_GetMovieReviews_Spoilers(t *testing.T) {
+	utils.Log = zap.NewNop()
+	db := setupMovieServiceTestDB(t)
+	db.AutoMigrate(&model.Follow{})
+
+	movieRepo := repository.NewMovieRepository(db)
+	reviewService := NewReviewService(repository.NewReviewRepository(db), movieRepo, nil)
+
+	movie := &model.Movie{TmdbID: 27205, Title: "Inception"}
+	db.Create(movie)
+
+	author := &model.User{Username: "author", Email: "author@example.com"}
+	viewer := &model.User{Username: "viewer", Email: "viewer@example.com"}
+	db.Create(author)
+	db.Create(viewer)
+	db.Create(&model.Review{UserID: author.ID, MovieID: movie.ID, Content: "the top keeps spinning", IsSpoiler: true})
+
+	filter := repository.MovieReviewFilter{Sort: repository.ReviewSortRecent}
+
+	tests := []struct {
+		name         string
+		viewerID     uint
+		showSpoilers bool
+		watched      bool
+		redacted     bool
+	}{
+		{"hidden by default", viewer.ID, false, false, true},
+		{"shown on opt-in", viewer.ID, true, false, false},
+		{"author always sees their own review", author.ID, false, false, false},
+		{"shown once the film is logged", viewer.ID, false, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.watched {
+				db.Create(&model.Track{UserID: tt.viewerID, MovieID: movie.ID, IsWatched: true})
+			}
+
+			resp, err := reviewService.GetMovieReviews(tt.viewerID, movie.TmdbID, filter, tt.showSpoilers, 1, 20)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(resp.Reviews) != 1 {
+				t.Fatalf("expected 1 review, got %d", len(resp.Reviews))
+			}
+
+			review := resp.Reviews[0]
+			if review.IsRedacted != tt.redacted {
+				t.Errorf("expected redacted=%v, got %v", tt.redacted, review.IsRedacted)
+			}
+			if tt.redacted && review.Content != "" {
+				t.Errorf("expected redacted content to be empty, got %q", review.Content)
+			}
+		})
+	}
+}
+
+func TestReviewService_GetMovieReviews_UnknownMovie(t *testing.T) {
+	utils.Log = zap.NewNop()
+	db := setupMovieServiceTestDB(t)
+	reviewService := NewReviewService(repository.NewReviewRepository(db), repository.NewMovieRepository(db), nil)
+
+	resp, err := reviewService.GetMovieReviews(1, 999, repository.MovieReviewFilter{}, false, 1, 20)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.Total != 0 || len(resp.Reviews) != 0 {
+		t.Errorf("expected no reviews for a movie unknown to FrameRate")
+	}
+}
+
+func TestFollowService_FollowAndCounts(t *testing.T) {
+	utils.Log = zap.NewNop()
+	db := setupMovieServiceTestDB(t)
+	db.AutoMigrate(&model.Follow{})
+	userRepo := repository.NewUserRepository(db)
+	followService := NewFollowService(repository.NewFollowRepository(db), userRepo)
+
+	a := &model.User{Username: "a", Email: "a@example.com"}
+	b := &model.User{Username: "b", Email: "b@example.com"}
+	db.Create(a)
+	db.Create(b)
+
+	if err := followService.Follow(a.ID, a.ID); err != ErrCannotFollowSelf {
+		t.Errorf("expected ErrCannotFollowSelf, got %v", err)
+	}
+	if err := followService.Follow(a.ID, 999); err != ErrUserNotFound {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+
+	// suivre deux fois reste idempotent
+	followService.Follow(a.ID, b.ID)
+	if err := followService.Follow(a.ID, b.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	following, followers := followService.Counts(a.ID)
+	if following != 1 || followers != 0 {
+		t.Errorf("expected a to follow 1 and have 0 followers, got %d/%d", following, followers)
+	}
+	if _, followers := followService.Counts(b.ID); followers != 1 {
+		t.Errorf("expected b to have 1 follower, got %d", followers)
+	}
+
+	followService.Unfollow(a.ID, b.ID)
+	if following, _ := followService.Counts(a.ID); following != 0 {
+		t.Errorf("expected unfollow to remove the relation, got %d", following)
+	}
+}
+
+func TestReviewService_GetPopularReviews_TimeDecay(t *testing.T) {
+	utils.Log = zap.NewNop()
+	db := setupMovieServiceTestDB(t)
+	reviewService := NewReviewService(repository.NewReviewRepository(db), repository.NewMovieRepository(db), nil)
+
+	now := time.Now()
+	reviewService.now = func() time.Time { return now }
+
+	movie := &model.Movie{TmdbID: 550, Title: "Fight Club"}
+	db.Create(movie)
+
+	old := &model.User{Username: "old", Email: "old@example.com"}
+	fresh := &model.User{Username: "fresh", Email: "fresh@example.com"}
+	stale := &model.User{Username: "stale", Email: "stale@example.com"}
+	db.Create(old)
+	db.Create(fresh)
+	db.Create(stale)
+
+	// 5 likes il y a 5 jours vs 2 likes il y a une heure : la récente gagne
+	db.Create(&model.Review{UserID: old.ID, MovieID: movie.ID, Content: "old", CreatedAt: now.Add(-5 * 24 * time.Hour)})
+	db.Create(&model.Review{UserID: fresh.ID, MovieID: movie.ID, Content: "fresh", CreatedAt: now.Add(-time.Hour)})
+	// hors fenêtre de 7 jours
+	db.Create(&model.Review{UserID: stale.ID, MovieID: movie.ID, Content: "stale", CreatedAt: now.Add(-8 * 24 * time.Hour)})
+
+	for i := 0; i < 5; i++ {
+		liker := &model.User{Username: fmt.Sprintf("liker%d", i), Email: fmt.Sprintf("liker%d@example.com", i)}
+		db.Create(liker)
+		db.Create(&model.ReviewLike{UserID: liker.ID, ReviewUserID: old.ID, MovieID: movie.ID})
+		db.Create(&model.ReviewLike{UserID: liker.ID, ReviewUserID: stale.ID, MovieID: movie.ID})
+		if i < 2 {
+			db.Create(&model.ReviewLike{UserID: liker.ID, ReviewUserID: fresh.ID, MovieID: movie.ID})
+		}
+	}
+
+	reviews, err := reviewService.GetPopularReviews(old.ID, 10)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(reviews) != 2 {
+		t.Fatalf("expected 2 reviews in the weekly window, got %d", len(reviews))
+	}
+	if reviews[0].Author.ID != fresh.ID || reviews[1].Author.ID != old.ID {
+		t.Errorf("expected recent review to outrank older one, got %s then %s", reviews[0].Author.Username, reviews[1].Author.Username)
+	}
+	if reviews[0].Movie.TmdbID != movie.TmdbID || reviews[1].LikeCount != 5 {
+		t.Errorf("expected movie info and like counts, got %+v", reviews[1])
+	}
+}
+
+func TestReviewService_LikeReview(t *testing.T) {
+	utils.Log = zap.NewNop()
+	db := setupMovieServiceTestDB(t)
+	reviewService := NewReviewService(repository.NewReviewRepository(db), repository.NewMovieRepository(db), nil)
+
+	movie := &model.Movie{TmdbID: 680, Title: "Pulp Fiction"}
+	db.Create(movie)
+	author := &model.User{Username: "author", Email: "author@example.com"}
+	fan := &model.User{Username: "fan", Email: "fan@example.com"}
+	db.Create(author)
+	db.Create(fan)
+	db.Create(&model.Review{UserID: author.ID, MovieID: movie.ID, Content: "royale with cheese"})
+
+	if _, err := reviewService.LikeReview(author.ID, movie.TmdbID, author.ID); err != ErrCannotLikeOwnReview {
+		t.Errorf("expected ErrCannotLikeOwnReview, got %v", err)
+	}
+	if _, err := reviewService.LikeReview(fan.ID, movie.TmdbID, 999); err != ErrReviewNotFound {
+		t.Errorf("expected ErrReviewNotFound, got %v", err)
+	}
+
+	resp, err := reviewService.LikeReview(fan.ID, movie.TmdbID, author.ID)
+	if err != nil || !resp.Liked || resp.LikeCount != 1 {
+		t.Fatalf("expected 1 like, got %+v (%v)", resp, err)
+	}
+
+	resp, err = reviewService.UnlikeReview(fan.ID, movie.TmdbID, author.ID)
+	if err != nil || resp.Liked || resp.LikeCount != 0 {
+		t.Fatalf("expected 0 likes after unlike, got %+v (%v)", resp, err)
+	}
+}
+
+func TestReviewService_GetReviewHistory(t *testing.T) {
+	utils.Log = zap.NewNop()
+	db := setupMovieServiceTestDB(t)
+	db.AutoMigrate(&model.Role{})
+	for _, role := range model.DefaultRoles() {
+		db.Create(&role)
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	movieRepo := repository.NewMovieRepository(db)
+	reviewService := NewReviewService(repository.NewReviewRepository(db), movieRepo, nil)
+	reviewService.SetAuthorizationService(NewAuthorizationService(userRepo, repository.NewRoleRepository(db)))
+
+	movie := &model.Movie{TmdbID: 27205, Title: "Inception"}
+	db.Create(movie)
+
+	author := &model.User{Username: "author", Email: "author@example.com"}
+	moderator := &model.User{Username: "mod", Email: "mod@example.com", Role: model.RoleModerator}
+	stranger := &model.User{Username: "stranger", Email: "stranger@example.com"}
+	db.Create(author)
+	db.Create(moderator)
+	db.Create(stranger)
+
+	for _, content := range []string{"first draft", "second draft", "final cut"} {
+		if err := movieRepo.UpsertReview(&model.Review{UserID: author.ID, MovieID: movie.ID, Content: content}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	if _, err := reviewService.GetReviewHistory(stranger.ID, movie.TmdbID, author.ID); !errors.Is(err, ErrReviewHistoryDenied) {
+		t.Errorf("expected ErrReviewHistoryDenied, got %v", err)
+	}
+
+	for _, viewerID := range []uint{author.ID, moderator.ID} {
+		history, err := reviewService.GetReviewHistory(viewerID, movie.TmdbID, author.ID)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if history.Current.Content != "final cut" || history.Current.ReplacedAt != nil {
+			t.Errorf("unexpected current version: %+v", history.Current)
+		}
+		if len(history.Revisions) != 2 || history.Revisions[0].Content != "second draft" || history.Revisions[1].Content != "first draft" {
+			t.Errorf("expected revisions newest first, got %+v", history.Revisions)
+		}
+	}
+
+	resp, _ := reviewService.GetMovieReviews(stranger.ID, movie.TmdbID, repository.MovieReviewFilter{Sort: repository.ReviewSortRecent}, false, 1, 20)
+	if len(resp.Reviews) != 1 || !resp.Reviews[0].IsEdited || resp.Reviews[0].EditedAt == nil {
+		t.Errorf("expected review to be flagged as edited, got %+v", resp.Reviews)
+	}
+
+	// supprimée : l'historique reste lisible par la modération
+	if err := movieRepo.DeleteReview(author.ID, movie.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var kept int64
+	db.Unscoped().Model(&model.ReviewRevision{}).Where("user_id = ? AND movie_id = ?", author.ID, movie.ID).Count(&kept)
+	if kept != 3 {
+		t.Errorf("expected 3 revisions kept after deletion, got %d", kept)
+	}
+	if _, err := reviewService.GetReviewHistory(author.ID, movie.TmdbID, author.ID); !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("expected ErrReviewNotFound for the author, got %v", err)
+	}
+	history, err := reviewService.GetReviewHistory(moderator.ID, movie.TmdbID, author.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !history.Deleted || history.Current.Content != "final cut" || history.Current.ReplacedAt == nil {
+		t.Errorf("expected the deleted version as current, got %+v", history)
+	}
+	if len(history.Revisions) != 2 || history.Revisions[0].Content != "second draft" {
+		t.Errorf("expected older revisions kept, got %+v", history.Revisions)
+	}
+}
+
+func TestReviewService_GetMovieReviews_InlineSpoilers(t *testing.T) {
+	utils.Log = zap.NewNop()
+	db := setupMovieServiceTestDB(t)
+	db.AutoMigrate(&model.Follow{})
+
+	movieRepo := repository.NewMovieRepository(db)
+	movieService := NewMovieService(movieRepo, NewTMDBService(nil))
+	reviewService := NewReviewService(repository.NewReviewRepository(db), movieRepo, nil)
+
+	movie := &model.Movie{TmdbID: 1124, Title: "The Prestige"}
+	db.Create(movie)
+	author := &model.User{Username: "author", Email: "author@example.com"}
+	viewer := &model.User{Username: "viewer", Email: "viewer@example.com"}
+	db.Create(author)
+	db.Create(viewer)
+
+	text := "**Brilliant**. ||They are twins||, <b>obviously</b>."
+	if err := movieService.LogMovie(author.ID, movie.TmdbID, dto.LogMovieRequest{ReviewText: &text}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	filter := repository.MovieReviewFilter{Sort: repository.ReviewSortRecent}
+
+	resp, _ := reviewService.GetMovieReviews(viewer.ID, movie.TmdbID, filter, false, 1, 20)
+	review := resp.Reviews[0]
+	if !review.IsRedacted || strings.Contains(review.Content, "twins") || strings.Contains(review.ContentHTML, "twins") {
+		t.Errorf("expected inline spoiler to be redacted, got %+v", review)
+	}
+	if !strings.Contains(review.ContentHTML, "<strong>Brilliant</strong>") || strings.Contains(review.ContentHTML, "<b>") {
+		t.Errorf("expected sanitized markdown, got %q", review.ContentHTML)
+	}
+
+	resp, _ = reviewService.GetMovieReviews(viewer.ID, movie.TmdbID, filter, true, 1, 20)
+	review = resp.Reviews[0]
+	if review.IsRedacted || !strings.Contains(review.ContentHTML, `<span class="spoiler">They are twins</span>`) {
+		t.Errorf("expected inline spoiler to be shown on opt-in, got %+v", review)
+	}
+}
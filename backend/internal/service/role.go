@@ -0,0 +1,228 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoleNotFound        = errors.New("role not found")
+	ErrRoleNameTaken       = errors.New("role name already taken")
+	ErrRoleNameInvalid     = errors.New("role name must be lowercase letters, digits, '-' or '_'")
+	ErrRoleSystem          = errors.New("system roles cannot be modified")
+	ErrRoleInUse           = errors.New("role is still assigned to users")
+	ErrInvalidPermission   = errors.New("unknown permission")
+	ErrCannotChangeOwnRole = errors.New("you cannot change your own role")
+	roleNamePattern        = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,49}$`)
+)
+
+type RoleService struct {
+	roleRepo *repository.RoleRepository
+	userRepo repository.UserRepository
+	authz    *AuthorizationService
+	audit    *AuditService
+	notifier *NotificationService
+}
+
+func NewRoleService(roleRepo *repository.RoleRepository, userRepo repository.UserRepository, authz *AuthorizationService) *RoleService {
+	return &RoleService{
+		roleRepo: roleRepo,
+		userRepo: userRepo,
+		authz:    authz,
+	}
+}
+
+func (s *RoleService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
+func (s *RoleService) SetNotificationService(notifier *NotificationService) {
+	s.notifier = notifier
+}
+
+func (s *RoleService) ListRoles() ([]dto.RoleResponse, error) {
+	roles, err := s.roleRepo.List()
+	if err != nil {
+		return nil, errors.New("failed to fetch roles")
+	}
+
+	responses := make([]dto.RoleResponse, 0, len(roles))
+	for i := range roles {
+		responses = append(responses, dto.ToRoleResponse(&roles[i]))
+	}
+	return responses, nil
+}
+
+// crée un rôle personnalisé
+func (s *RoleService) CreateRole(adminID uint, input dto.CreateRoleRequest, meta dto.RequestMeta) (*dto.RoleResponse, error) {
+	if !roleNamePattern.MatchString(input.Name) {
+		return nil, ErrRoleNameInvalid
+	}
+	if err := validatePermissions(input.Permissions); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.roleRepo.GetByName(input.Name); err == nil {
+		return nil, ErrRoleNameTaken
+	}
+
+	role := &model.Role{
+		Name:        input.Name,
+		Description: input.Description,
+		Permissions: dedupePermissions(input.Permissions),
+	}
+	if err := s.roleRepo.Create(role); err != nil {
+		return nil, errors.New("failed to create role")
+	}
+	// un rôle inconnu a pu être mis en cache comme "aucune permission"
+	s.authz.InvalidateRole(role.Name)
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditAdminRoleCreated,
+		ActorID:  uintPtr(adminID),
+		Metadata: map[string]interface{}{"role": role.Name, "permissions": role.Permissions},
+	})
+
+	response := dto.ToRoleResponse(role)
+	return &response, nil
+}
+
+// met à jour la description et/ou les permissions d'un rôle personnalisé
+func (s *RoleService) UpdateRole(adminID, roleID uint, input dto.UpdateRoleRequest, meta dto.RequestMeta) (*dto.RoleResponse, error) {
+	role, err := s.getRole(roleID)
+	if err != nil {
+		return nil, err
+	}
+	if role.IsSystem {
+		return nil, ErrRoleSystem
+	}
+
+	if input.Description != nil {
+		role.Description = *input.Description
+	}
+	if input.Permissions != nil {
+		if err := validatePermissions(input.Permissions); err != nil {
+			return nil, err
+		}
+		role.Permissions = dedupePermissions(input.Permissions)
+	}
+
+	if err := s.roleRepo.Update(role); err != nil {
+		return nil, errors.New("failed to update role")
+	}
+	s.authz.InvalidateRole(role.Name)
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditAdminRoleUpdated,
+		ActorID:  uintPtr(adminID),
+		Metadata: map[string]interface{}{"role": role.Name, "permissions": role.Permissions},
+	})
+
+	response := dto.ToRoleResponse(role)
+	return &response, nil
+}
+
+// supprime un rôle personnalisé qui n'est plus attribué
+func (s *RoleService) DeleteRole(adminID, roleID uint, meta dto.RequestMeta) error {
+	role, err := s.getRole(roleID)
+	if err != nil {
+		return err
+	}
+	if role.IsSystem {
+		return ErrRoleSystem
+	}
+
+	count, err := s.roleRepo.CountUsers(role.Name)
+	if err != nil {
+		return errors.New("failed to delete role")
+	}
+	if count > 0 {
+		return ErrRoleInUse
+	}
+
+	if err := s.roleRepo.Delete(role.ID); err != nil {
+		return errors.New("failed to delete role")
+	}
+	s.authz.InvalidateRole(role.Name)
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditAdminRoleDeleted,
+		ActorID:  uintPtr(adminID),
+		Metadata: map[string]interface{}{"role": role.Name},
+	})
+	return nil
+}
+
+// attribue un rôle à un user ; un admin ne peut pas changer son propre rôle
+// (évite de se retirer l'accès par erreur)
+func (s *RoleService) AssignRole(adminID, userID uint, roleName string, meta dto.RequestMeta) (*dto.UserResponse, error) {
+	if adminID == userID {
+		return nil, ErrCannotChangeOwnRole
+	}
+
+	if _, err := s.roleRepo.GetByName(roleName); err != nil {
+		return nil, ErrRoleNotFound
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	previous := user.Role
+	if err := s.userRepo.UpdateFields(userID, map[string]interface{}{"role": roleName}); err != nil {
+		return nil, errors.New("failed to assign role")
+	}
+	user.Role = roleName
+	s.authz.InvalidateUser(userID)
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditAdminRoleAssigned,
+		ActorID:  uintPtr(adminID),
+		TargetID: uintPtr(userID),
+		Metadata: map[string]interface{}{"from": previous, "to": roleName},
+	})
+	if previous != roleName {
+		s.notifier.Notify([]uint{userID}, nil, model.RoleChangedPayload{From: previous, To: roleName})
+	}
+
+	response := dto.ToUserResponse(user)
+	return &response, nil
+}
+
+func (s *RoleService) getRole(roleID uint) (*model.Role, error) {
+	role, err := s.roleRepo.GetByID(roleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, errors.New("failed to fetch role")
+	}
+	return role, nil
+}
+
+func validatePermissions(permissions []string) error {
+	for _, p := range permissions {
+		if !model.IsValidPermission(p) {
+			return ErrInvalidPermission
+		}
+	}
+	return nil
+}
+
+func dedupePermissions(permissions []string) []string {
+	seen := make(map[string]bool, len(permissions))
+	result := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		if !seen[p] {
+			seen[p] = true
+			result = append(result, p)
+		}
+	}
+	return result
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func setupRoleServiceTest(t *testing.T) (*gorm.DB, *RoleService, *AuthorizationService) {
+	utils.Log = zap.NewNop()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	if err := db.AutoMigrate(&model.User{}, &model.Role{}, &model.AuditEvent{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	for _, role := range model.DefaultRoles() {
+		db.Create(&role)
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	authz := NewAuthorizationService(userRepo, roleRepo)
+	roleService := NewRoleService(roleRepo, userRepo, authz)
+	roleService.SetAuditService(NewAuditService(repository.NewAuditRepository(db)))
+
+	return db, roleService, authz
+}
+
+func TestAuthorizationService_DefaultRoles(t *testing.T) {
+	db, _, authz := setupRoleServiceTest(t)
+
+	moderator := &model.User{Username: "mod", Email: "mod@example.com", Role: model.RoleModerator}
+	admin := &model.User{Username: "admin", Email: "admin@example.com", Role: model.RoleAdmin}
+	member := &model.User{Username: "member", Email: "member@example.com", Role: model.RoleUser}
+	db.Create(moderator)
+	db.Create(admin)
+	db.Create(member)
+
+	tests := []struct {
+		name       string
+		userID     uint
+		permission string
+		want       bool
+	}{
+		{"moderator can moderate reviews", moderator.ID, model.PermReviewsModerate, true},
+		{"moderator cannot delete accounts", moderator.ID, model.PermUsersDelete, false},
+		{"admin can delete accounts", admin.ID, model.PermUsersDelete, true},
+		{"member has no admin permission", member.ID, model.PermUsersRead, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := authz.HasPermission(tt.userID, tt.permission)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := authz.HasPermission(9999, model.PermUsersRead); err != ErrUserNotFound {
+		t.Errorf("expected ErrUserNotFound for unknown user, got %v", err)
+	}
+}
+
+func TestAuthorizationService_CacheExpires(t *testing.T) {
+	db, _, authz := setupRoleServiceTest(t)
+	now := time.Now()
+	authz.now = func() time.Time { return now }
+
+	user := &model.User{Username: "cached", Email: "cached@example.com", Role: model.RoleUser}
+	db.Create(user)
+
+	if ok, _ := authz.HasPermission(user.ID, model.PermUsersRead); ok {
+		t.Fatal("expected no permission for a regular user")
+	}
+
+	// changement direct en base : le cache sert encore l'ancien rôle
+	db.Model(user).Update("role", model.RoleAdmin)
+	if ok, _ := authz.HasPermission(user.ID, model.PermUsersRead); ok {
+		t.Error("expected cached role to be used before TTL")
+	}
+
+	now = now.Add(authorizationCacheTTL + time.Second)
+	if ok, _ := authz.HasPermission(user.ID, model.PermUsersRead); !ok {
+		t.Error("expected role to be reloaded after TTL")
+	}
+}
+
+func TestRoleService_AssignRole(t *testing.T) {
+	db, roleService, authz := setupRoleServiceTest(t)
+
+	admin := &model.User{Username: "admin", Email: "admin@example.com", Role: model.RoleAdmin}
+	target := &model.User{Username: "target", Email: "target@example.com", Role: model.RoleUser}
+	db.Create(admin)
+	db.Create(target)
+
+	// met le rôle en cache avant l'attribution
+	authz.HasPermission(target.ID, model.PermReviewsModerate)
+
+	resp, err := roleService.AssignRole(admin.ID, target.ID, model.RoleModerator, dto.RequestMeta{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Role != model.RoleModerator {
+		t.Errorf("expected role moderator, got %s", resp.Role)
+	}
+
+	if ok, _ := authz.HasPermission(target.ID, model.PermReviewsModerate); !ok {
+		t.Error("expected cache to be invalidated after role assignment")
+	}
+
+	var events int64
+	db.Model(&model.AuditEvent{}).Where("action = ? AND target_id = ?", model.AuditAdminRoleAssigned, target.ID).Count(&events)
+	if events != 1 {
+		t.Errorf("expected role assignment to be audited, got %d events", events)
+	}
+
+	if _, err := roleService.AssignRole(admin.ID, admin.ID, model.RoleUser, dto.RequestMeta{}); err != ErrCannotChangeOwnRole {
+		t.Errorf("expected ErrCannotChangeOwnRole, got %v", err)
+	}
+	if _, err := roleService.AssignRole(admin.ID, target.ID, "ghost", dto.RequestMeta{}); err != ErrRoleNotFound {
+		t.Errorf("expected ErrRoleNotFound, got %v", err)
+	}
+}
+
+func TestRoleService_CustomRoleLifecycle(t *testing.T) {
+	db, roleService, authz := setupRoleServiceTest(t)
+
+	admin := &model.User{Username: "admin", Email: "admin@example.com", Role: model.RoleAdmin}
+	db.Create(admin)
+
+	if _, err := roleService.CreateRole(admin.ID, dto.CreateRoleRequest{Name: "Bad Name", Permissions: []string{}}, dto.RequestMeta{}); err != ErrRoleNameInvalid {
+		t.Errorf("expected ErrRoleNameInvalid, got %v", err)
+	}
+	if _, err := roleService.CreateRole(admin.ID, dto.CreateRoleRequest{Name: "auditor", Permissions: []string{"everything"}}, dto.RequestMeta{}); err != ErrInvalidPermission {
+		t.Errorf("expected ErrInvalidPermission, got %v", err)
+	}
+
+	role, err := roleService.CreateRole(admin.ID, dto.CreateRoleRequest{
+		Name:        "auditor",
+		Permissions: []string{model.PermAuditRead, model.PermAuditRead},
+	}, dto.RequestMeta{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(role.Permissions) != 1 {
+		t.Errorf("expected duplicate permissions to be removed, got %v", role.Permissions)
+	}
+
+	if _, err := roleService.CreateRole(admin.ID, dto.CreateRoleRequest{Name: "auditor", Permissions: []string{}}, dto.RequestMeta{}); err != ErrRoleNameTaken {
+		t.Errorf("expected ErrRoleNameTaken, got %v", err)
+	}
+
+	user := &model.User{Username: "auditor", Email: "auditor@example.com", Role: "auditor"}
+	db.Create(user)
+	if ok, _ := authz.HasPermission(user.ID, model.PermAuditRead); !ok {
+		t.Error("expected custom role permission to be granted")
+	}
+
+	_, err = roleService.UpdateRole(admin.ID, role.ID, dto.UpdateRoleRequest{Permissions: []string{model.PermUsersRead}}, dto.RequestMeta{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok, _ := authz.HasPermission(user.ID, model.PermAuditRead); ok {
+		t.Error("expected updated permissions to apply immediately")
+	}
+
+	if err := roleService.DeleteRole(admin.ID, role.ID, dto.RequestMeta{}); err != ErrRoleInUse {
+		t.Errorf("expected ErrRoleInUse, got %v", err)
+	}
+	db.Model(user).Update("role", model.RoleUser)
+	if err := roleService.DeleteRole(admin.ID, role.ID, dto.RequestMeta{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRoleService_SystemRolesImmutable(t *testing.T) {
+	db, roleService, _ := setupRoleServiceTest(t)
+
+	var adminRole model.Role
+	db.Where("name = ?", model.RoleAdmin).First(&adminRole)
+
+	if _, err := roleService.UpdateRole(1, adminRole.ID, dto.UpdateRoleRequest{Permissions: []string{}}, dto.RequestMeta{}); err != ErrRoleSystem {
+		t.Errorf("expected ErrRoleSystem on update, got %v", err)
+	}
+	if err := roleService.DeleteRole(1, adminRole.ID, dto.RequestMeta{}); err != ErrRoleSystem {
+		t.Errorf("expected ErrRoleSystem on delete, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+type scheduledJob struct {
+	name     string
+	interval time.Duration
+	run      func() error
+	running  atomic.Bool
+}
+
+// tâches périodiques (recalcul des recommandations...) ; chaque échéance est
+// confiée au Dispatcher, et sautée si l'exécution précédente n'est pas finie
+type Scheduler struct {
+	dispatcher *Dispatcher
+	jobs       []*scheduledJob
+}
+
+func NewScheduler(dispatcher *Dispatcher) *Scheduler {
+	return &Scheduler{dispatcher: dispatcher}
+}
+
+// à appeler avant Run ; un *Scheduler nil ignore la tâche
+func (s *Scheduler) Every(name string, interval time.Duration, run func() error) {
+	if s == nil || interval <= 0 {
+		return
+	}
+	s.jobs = append(s.jobs, &scheduledJob{name: name, interval: interval, run: run})
+}
+
+// déclenche les tâches à leur intervalle jusqu'à l'annulation de ctx
+func (s *Scheduler) Run(ctx context.Context) {
+	if s == nil {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func(job *scheduledJob) {
+			defer wg.Done()
+			ticker := time.NewTicker(job.interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					s.trigger(job)
+				}
+			}
+		}(job)
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) trigger(job *scheduledJob) {
+	if !job.running.CompareAndSwap(false, true) {
+		utils.Log.Warn("Scheduled job still running, tick skipped", zap.String("job", job.name))
+		return
+	}
+	accepted := s.dispatcher.Dispatch(job.name, func() error {
+		defer job.running.Store(false)
+		return job.run()
+	})
+	if !accepted {
+		job.running.Store(false)
+	}
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+)
+
+var (
+	ErrInvalidStatsYear     = errors.New("invalid year")
+	ErrInvalidActivityRange = errors.New("invalid date range")
+)
+
+const (
+	statsCacheTTL       = time.Hour
+	statsTopLimit       = 10
+	statsTopCastOrder   = 5 // seuls les premiers rôles comptent pour les acteurs
+	statsRewatchedLimit = 5
+	statsSyncBatch      = 50 // films enrichis par tâche de rattrapage
+	statsFirstYear      = 1900
+
+	activityDefaultDays = 365
+	activityMaxDays     = 5 * 366
+	dayLayout           = "2006-01-02"
+)
+
+type StatsService struct {
+	statsRepo  *repository.StatsRepository
+	userRepo   repository.UserRepository
+	movies     *MovieService
+	cache      *CacheService
+	dispatcher *Dispatcher
+}
+
+// movies sert à importer les métadonnées TMDB manquantes (films ajoutés avant leur import)
+func NewStatsService(statsRepo *repository.StatsRepository, userRepo repository.UserRepository, movies *MovieService, cache *CacheService) *StatsService {
+	return &StatsService{
+		statsRepo: statsRepo,
+		userRepo:  userRepo,
+		movies:    movies,
+		cache:     cache,
+	}
+}
+
+func (s *StatsService) SetDispatcher(dispatcher *Dispatcher) {
+	s.dispatcher = dispatcher
+}
+
+// bilan de l'année year, dans le fuseau de l'utilisateur ; mis en cache tant
+// que ses suivis et notes ne changent pas (et que toutes les métadonnées sont importées)
+func (s *StatsService) GetYearStats(userID uint, year int) (*dto.YearStatsResponse, error) {
+	if year < statsFirstYear || year > time.Now().Year()+1 {
+		return nil, ErrInvalidStatsYear
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	loc := user.Zone()
+
+	fingerprint, err := s.statsRepo.Fingerprint(userID)
+	if err != nil {
+		return nil, errors.New("failed to compute stats")
+	}
+	cacheKey := fmt.Sprintf("stats:year:%d:%d:%s:%d:%s:%d:%s", userID, year, loc,
+		fingerprint.Tracks, fingerprint.LastTrack, fingerprint.Rates, fingerprint.LastRate)
+
+	var cached dto.YearStatsResponse
+	if s.cache != nil {
+		if found, err := s.cache.Get(context.Background(), cacheKey, &cached); err == nil && found {
+			return &cached, nil
+		}
+	}
+
+	response, err := s.computeYearStats(userID, year, loc)
+	if err != nil {
+		return nil, errors.New("failed to compute stats")
+	}
+
+	if s.cache != nil && response.MetadataPending == 0 {
+		_ = s.cache.Set(context.Background(), cacheKey, response, statsCacheTTL)
+	}
+	return response, nil
+}
+
+func (s *StatsService) computeYearStats(userID uint, year int, loc *time.Location) (*dto.YearStatsResponse, error) {
+	from := time.Date(year, 1, 1, 0, 0, 0, 0, loc)
+	to := from.AddDate(1, 0, 0)
+
+	tracks, err := s.statsRepo.Tracks(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.YearStatsResponse{
+		Year:          year,
+		TotalFilms:    len(tracks),
+		Months:        make([]dto.MonthStats, 12),
+		Weekdays:      make([]dto.WeekdayStats, 7),
+		MostRewatched: []dto.RewatchedMovie{},
+	}
+	for i := range response.Months {
+		response.Months[i].Month = i + 1
+	}
+	for i := range response.Weekdays {
+		response.Weekdays[i].Weekday = time.Weekday((i + 1) % 7).String()
+	}
+
+	var (
+		minutes     int
+		ratingSum   float64
+		ratingCount int
+		decades     = make(map[int]int)
+		releases    = make(map[int]int)
+		days        []time.Time
+		unsynced    []uint
+	)
+	for _, track := range tracks {
+		watched := track.WatchedDate.In(loc)
+		month := int(watched.Month()) - 1
+		response.Months[month].Films++
+		response.Months[month].Hours += float64(track.DurationMinutes) / 60
+		response.Weekdays[(int(watched.Weekday())+6)%7].Films++
+		minutes += track.DurationMinutes
+		days = append(days, localDay(watched, loc))
+
+		if track.ReleaseYear > 0 {
+			decades[track.ReleaseYear/10*10]++
+			releases[track.ReleaseYear]++
+		}
+		if track.Rating != nil {
+			ratingSum += float64(*track.Rating)
+			ratingCount++
+		}
+		if track.WatchCount > 1 {
+			response.Rewatches += track.WatchCount - 1
+			response.MostRewatched = append(response.MostRewatched, dto.RewatchedMovie{
+				TmdbID:     track.TmdbID,
+				Title:      track.Title,
+				PosterURL:  track.PosterURL,
+				WatchCount: track.WatchCount,
+			})
+		}
+		if track.MetadataSynced == nil {
+			unsynced = append(unsynced, track.MovieID)
+		}
+	}
+
+	response.TotalHours = roundTenth(float64(minutes) / 60)
+	for i := range response.Months {
+		response.Months[i].Hours = roundTenth(response.Months[i].Hours)
+	}
+	if ratingCount > 0 {
+		average := roundTenth(ratingSum / float64(ratingCount))
+		response.AverageRating = &average
+	}
+	sort.SliceStable(response.MostRewatched, func(i, j int) bool {
+		return response.MostRewatched[i].WatchCount > response.MostRewatched[j].WatchCount
+	})
+	if len(response.MostRewatched) > statsRewatchedLimit {
+		response.MostRewatched = response.MostRewatched[:statsRewatchedLimit]
+	}
+	response.Decades = toDecadeStats(decades)
+	response.ReleaseYears = toReleaseYearStats(releases)
+	response.LongestStreak = longestStreak(days)
+
+	if response.TopGenres, err = s.topItems(s.statsRepo.TopGenres(userID, from, to, statsTopLimit)); err != nil {
+		return nil, err
+	}
+	if response.TopDirectors, err = s.topItems(s.statsRepo.TopDirectors(userID, from, to, statsTopLimit)); err != nil {
+		return nil, err
+	}
+	if response.TopActors, err = s.topItems(s.statsRepo.TopActors(userID, from, to, statsTopCastOrder, statsTopLimit)); err != nil {
+		return nil, err
+	}
+	if response.TopCountries, err = s.topItems(s.statsRepo.TopCountries(userID, from, to, statsTopLimit)); err != nil {
+		return nil, err
+	}
+	community, err := s.statsRepo.CommunityAverage(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if community != nil {
+		rounded := roundTenth(*community)
+		response.CommunityAverage = &rounded
+	}
+
+	response.MetadataPending = len(unsynced)
+	s.syncMetadata(unsynced)
+	return response, nil
+}
+
+// import en tâche de fond des métadonnées manquantes ; les stats suivantes
+// les prendront en compte
+func (s *StatsService) syncMetadata(movieIDs []uint) {
+	if len(movieIDs) == 0 || s.movies == nil {
+		return
+	}
+	if len(movieIDs) > statsSyncBatch {
+		movieIDs = movieIDs[:statsSyncBatch]
+	}
+	s.dispatcher.Dispatch("stats.sync_metadata", func() error {
+		return s.movies.SyncMissingMetadata(movieIDs)
+	})
+}
+
+func (s *StatsService) topItems(counts []repository.StatsCount, err error) ([]dto.StatsCountItem, error) {
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.StatsCountItem, 0, len(counts))
+	for _, count := range counts {
+		items = append(items, dto.StatsCountItem{ID: count.Key, Name: count.Name, ImageURL: count.Image, Films: count.Films})
+	}
+	return items, nil
+}
+
+func toDecadeStats(decades map[int]int) []dto.DecadeStats {
+	stats := make([]dto.DecadeStats, 0, len(decades))
+	for decade, films := range decades {
+		stats = append(stats, dto.DecadeStats{Decade: decade, Films: films})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Decade < stats[j].Decade })
+	return stats
+}
+
+func toReleaseYearStats(years map[int]int) []dto.ReleaseYearStats {
+	stats := make([]dto.ReleaseYearStats, 0, len(years))
+	for year, films := range years {
+		stats = append(stats, dto.ReleaseYearStats{Year: year, Films: films})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Year < stats[j].Year })
+	return stats
+}
+
+// activité jour par jour entre from et to inclus (AAAA-MM-JJ, dans le fuseau
+// de l'utilisateur ; par défaut les 365 derniers jours), avec les séries
+// calculées sur tout l'historique
+func (s *StatsService) GetActivity(userID uint, from, to string) (*dto.ActivityResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	loc := user.Zone()
+	today := localDay(time.Now(), loc)
+
+	end, start := today, today.AddDate(0, 0, 1-activityDefaultDays)
+	if to != "" {
+		if end, err = time.Parse(dayLayout, to); err != nil {
+			return nil, ErrInvalidActivityRange
+		}
+	}
+	if from != "" {
+		if start, err = time.Parse(dayLayout, from); err != nil {
+			return nil, ErrInvalidActivityRange
+		}
+	} else if to != "" {
+		start = end.AddDate(0, 0, 1-activityDefaultDays)
+	}
+	if end.Before(start) || end.Sub(start) >= activityMaxDays*24*time.Hour {
+		return nil, ErrInvalidActivityRange
+	}
+
+	dates, err := s.statsRepo.WatchedDates(userID)
+	if err != nil {
+		return nil, errors.New("failed to fetch activity")
+	}
+
+	response := &dto.ActivityResponse{
+		TimeZone: loc.String(),
+		From:     start.Format(dayLayout),
+		To:       end.Format(dayLayout),
+		Days:     []dto.ActivityDay{},
+	}
+	counts := make(map[time.Time]int)
+	var days []time.Time
+	for _, date := range dates {
+		day := localDay(date, loc)
+		if counts[day] == 0 {
+			days = append(days, day)
+		}
+		counts[day]++
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	for _, day := range days {
+		if day.Before(start) || day.After(end) {
+			continue
+		}
+		response.Days = append(response.Days, dto.ActivityDay{Date: day.Format(dayLayout), Films: counts[day]})
+		response.Total += counts[day]
+	}
+	response.CurrentStreak = currentStreak(counts, today)
+	response.LongestStreak = longestStreak(days)
+	return response, nil
+}
+
+// date calendaire de t dans loc, ramenée à minuit UTC : comparable et
+// sans effet des changements d'heure
+func localDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// plus longue suite de jours consécutifs avec au moins un film ; days triés (localDay)
+func longestStreak(days []time.Time) dto.StreakStats {
+	var best dto.StreakStats
+	var start, previous time.Time
+	length := 0
+	for _, day := range days {
+		switch {
+		case length > 0 && day.Equal(previous):
+			continue
+		case length > 0 && day.Equal(previous.AddDate(0, 0, 1)):
+			length++
+		default:
+			start, length = day, 1
+		}
+		previous = day
+		if length > best.Days {
+			best = dto.StreakStats{Days: length, Start: start.Format(dayLayout), End: day.Format(dayLayout)}
+		}
+	}
+	return best
+}
+
+// série en cours : jusqu'à aujourd'hui, ou jusqu'à hier si rien n'a encore été vu aujourd'hui
+func currentStreak(counts map[time.Time]int, today time.Time) int {
+	day := today
+	if counts[day] == 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for counts[day] > 0 {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+func roundTenth(value float64) float64 {
+	return math.Round(value*10) / 10
+}
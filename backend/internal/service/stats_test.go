@@ -0,0 +1,246 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Nowap83/FrameRate/backend/internal/dto"
+	"github.com/Nowap83/FrameRate/backend/internal/model"
+	"github.com/Nowap83/FrameRate/backend/internal/repository"
+)
+
+func TestStatsService_YearStats(t *testing.T) {
+	crime, drama, comedy := dto.TMDBGenre{ID: 80, Name: "Crime"}, dto.TMDBGenre{ID: 18, Name: "Drama"}, dto.TMDBGenre{ID: 35, Name: "Comedy"}
+	mann := dto.TMDBCrewMember{ID: 638, Name: "Michael Mann"}
+	catalog := map[int]dto.TMDBMovieDetails{
+		100: tmdbDetails(100, "Heat", "1995-12-15", 170, []dto.TMDBGenre{crime, drama}, "US",
+			[]dto.TMDBCastMember{{ID: 1158, Name: "Al Pacino", Order: 0}, {ID: 380, Name: "Robert De Niro", Order: 1}}, mann),
+		101: tmdbDetails(101, "Collateral", "2004-08-06", 120, []dto.TMDBGenre{crime}, "US",
+			[]dto.TMDBCastMember{{ID: 500, Name: "Tom Cruise", Order: 0}, {ID: 1158, Name: "Al Pacino", Order: 40}}, mann),
+		102: tmdbDetails(102, "Amélie", "2001-04-25", 122, []dto.TMDBGenre{comedy}, "FR",
+			[]dto.TMDBCastMember{{ID: 3, Name: "Audrey Tautou", Order: 0}}, dto.TMDBCrewMember{ID: 2419, Name: "Jean-Pierre Jeunet"}),
+		103: tmdbDetails(103, "Last Year", "2023-01-01", 100, []dto.TMDBGenre{drama}, "US", nil, mann),
+		104: tmdbDetails(104, "Thief", "1981-03-27", 120, []dto.TMDBGenre{crime}, "US", nil, mann),
+	}
+	db, tmdbService := setupCatalogTest(t, catalog)
+	movieRepo := repository.NewMovieRepository(db)
+	movieService := NewMovieService(movieRepo, tmdbService)
+	// dispatcher nil : l'import des métadonnées manquantes est immédiat
+	statsService := NewStatsService(repository.NewStatsRepository(db), repository.NewUserRepository(db), movieService, nil)
+
+	user := &model.User{Username: "cinephile", Email: "cinephile@example.com"}
+	other := &model.User{Username: "other", Email: "other@example.com"}
+	db.Create(user)
+	db.Create(other)
+
+	day := func(month time.Month, d int) *time.Time {
+		date := time.Date(2024, month, d, 20, 0, 0, 0, time.UTC)
+		return &date
+	}
+	rating := func(r float32) *float32 { return &r }
+	lastYear := time.Date(2023, time.December, 31, 12, 0, 0, 0, time.UTC)
+	logs := []struct {
+		tmdbID int
+		req    dto.LogMovieRequest
+	}{
+		{100, dto.LogMovieRequest{WatchedDate: day(time.March, 1), Rating: rating(4)}},
+		{100, dto.LogMovieRequest{WatchedDate: day(time.March, 2)}}, // revu
+		{101, dto.LogMovieRequest{WatchedDate: day(time.March, 3)}},
+		{102, dto.LogMovieRequest{WatchedDate: day(time.March, 4), Rating: rating(3)}},
+		{103, dto.LogMovieRequest{WatchedDate: &lastYear}},
+	}
+	for _, log := range logs {
+		if err := movieService.LogMovie(user.ID, log.tmdbID, log.req); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if err := movieService.LogMovie(other.ID, 100, dto.LogMovieRequest{WatchedDate: day(time.May, 1), Rating: rating(5)}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// film importé avant l'enrichissement : pas de métadonnées
+	thief := &model.Movie{TmdbID: 104, Title: "Thief", ReleaseYear: 1981, DurationMinutes: 120}
+	db.Create(thief)
+	db.Create(&model.Track{UserID: user.ID, MovieID: thief.ID, IsWatched: true, WatchedDate: day(time.June, 15)})
+
+	stats, err := statsService.GetYearStats(user.ID, 2024)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if stats.TotalFilms != 4 || stats.TotalHours != 8.9 || stats.Rewatches != 1 {
+		t.Errorf("unexpected totals: %d films, %.1f hours, %d rewatches", stats.TotalFilms, stats.TotalHours, stats.Rewatches)
+	}
+	if stats.Months[2].Films != 3 || stats.Months[5].Films != 1 || stats.Months[5].Hours != 2 {
+		t.Errorf("unexpected months: %+v", stats.Months)
+	}
+	// lundi d'abord : 2 mars et 15 juin sont des samedis
+	if stats.Weekdays[0].Weekday != "Monday" || stats.Weekdays[0].Films != 1 || stats.Weekdays[5].Films != 2 || stats.Weekdays[6].Films != 1 {
+		t.Errorf("unexpected weekdays: %+v", stats.Weekdays)
+	}
+	if len(stats.Decades) != 3 || stats.Decades[0] != (dto.DecadeStats{Decade: 1980, Films: 1}) || stats.Decades[2] != (dto.DecadeStats{Decade: 2000, Films: 2}) {
+		t.Errorf("unexpected decades: %+v", stats.Decades)
+	}
+	if stats.AverageRating == nil || *stats.AverageRating != 3.5 || stats.CommunityAverage == nil || *stats.CommunityAverage != 5 {
+		t.Errorf("unexpected averages: %v vs %v", stats.AverageRating, stats.CommunityAverage)
+	}
+	if len(stats.MostRewatched) != 1 || stats.MostRewatched[0].Title != "Heat" || stats.MostRewatched[0].WatchCount != 2 {
+		t.Errorf("unexpected rewatches: %+v", stats.MostRewatched)
+	}
+	if stats.LongestStreak.Days != 3 || stats.LongestStreak.Start != "2024-03-02" || stats.LongestStreak.End != "2024-03-04" {
+		t.Errorf("unexpected streak: %+v", stats.LongestStreak)
+	}
+	if stats.MetadataPending != 1 || stats.TopGenres[0].Name != "Crime" || stats.TopGenres[0].Films != 2 {
+		t.Errorf("expected Thief metadata to be pending, got %d pending, genres %+v", stats.MetadataPending, stats.TopGenres)
+	}
+	if len(stats.TopActors) != 4 {
+		t.Errorf("expected only leading roles to count, got %+v", stats.TopActors)
+	}
+
+	// l'import manquant a été fait pendant le premier calcul
+	stats, _ = statsService.GetYearStats(user.ID, 2024)
+	if stats.MetadataPending != 0 {
+		t.Errorf("expected metadata to be synced, got %d pending", stats.MetadataPending)
+	}
+	if stats.TopGenres[0].Name != "Crime" || stats.TopGenres[0].Films != 3 {
+		t.Errorf("unexpected genres: %+v", stats.TopGenres)
+	}
+	if stats.TopDirectors[0] != (dto.StatsCountItem{ID: "638", Name: "Michael Mann", Films: 3}) {
+		t.Errorf("unexpected directors: %+v", stats.TopDirectors)
+	}
+	if stats.TopCountries[0].ID != "US" || stats.TopCountries[0].Films != 3 || stats.TopCountries[1].ID != "FR" {
+		t.Errorf("unexpected countries: %+v", stats.TopCountries)
+	}
+
+	empty, err := statsService.GetYearStats(other.ID, 2020)
+	if err != nil || empty.TotalFilms != 0 || len(empty.Months) != 12 || empty.AverageRating != nil {
+		t.Errorf("unexpected empty year: %+v, %v", empty, err)
+	}
+	if _, err := statsService.GetYearStats(user.ID, 1800); !errors.Is(err, ErrInvalidStatsYear) {
+		t.Errorf("expected ErrInvalidStatsYear, got %v", err)
+	}
+}
+
+func TestStatsService_Activity(t *testing.T) {
+	db := setupMovieServiceTestDB(t)
+	statsService := NewStatsService(repository.NewStatsRepository(db), repository.NewUserRepository(db), nil, nil)
+
+	paris := &model.User{Username: "paris", Email: "paris@example.com"}
+	utc := &model.User{Username: "utc", Email: "utc@example.com", TimeZone: "UTC"}
+	db.Create(paris)
+	db.Create(utc)
+
+	now := time.Now().In(paris.Zone())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, now.Location())
+	watched := []time.Time{
+		time.Date(2024, time.March, 1, 23, 30, 0, 0, time.UTC), // 2 mars à Paris
+		time.Date(2024, time.March, 2, 10, 0, 0, 0, time.UTC),
+		time.Date(2024, time.March, 3, 10, 0, 0, 0, time.UTC),
+		today.AddDate(0, 0, -1),
+		today.AddDate(0, 0, -2),
+	}
+	for i, date := range watched {
+		movie := &model.Movie{TmdbID: 500 + i, Title: "Movie " + strconv.Itoa(i)}
+		db.Create(movie)
+		date := date
+		db.Create(&model.Track{UserID: paris.ID, MovieID: movie.ID, IsWatched: true, WatchedDate: &date})
+		db.Create(&model.Track{UserID: utc.ID, MovieID: movie.ID, IsWatched: true, WatchedDate: &date})
+	}
+
+	activity, err := statsService.GetActivity(paris.ID, "2024-03-01", "2024-03-31")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if activity.TimeZone != "Europe/Paris" || activity.Total != 3 || len(activity.Days) != 2 ||
+		activity.Days[0] != (dto.ActivityDay{Date: "2024-03-02", Films: 2}) {
+		t.Errorf("unexpected Paris activity: %+v", activity)
+	}
+	if activity.LongestStreak.Days != 2 || activity.CurrentStreak != 2 {
+		t.Errorf("unexpected Paris streaks: current %d, longest %+v", activity.CurrentStreak, activity.LongestStreak)
+	}
+
+	activity, err = statsService.GetActivity(utc.ID, "2024-03-01", "2024-03-31")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(activity.Days) != 3 || activity.Days[0] != (dto.ActivityDay{Date: "2024-03-01", Films: 1}) {
+		t.Errorf("unexpected UTC activity: %+v", activity.Days)
+	}
+	if activity.LongestStreak != (dto.StreakStats{Days: 3, Start: "2024-03-01", End: "2024-03-03"}) {
+		t.Errorf("unexpected UTC streak: %+v", activity.LongestStreak)
+	}
+
+	// par défaut : les 365 derniers jours
+	activity, _ = statsService.GetActivity(utc.ID, "", "")
+	if activity.Total != 2 || activity.To != time.Now().UTC().Format("2006-01-02") {
+		t.Errorf("unexpected default range: %+v", activity)
+	}
+
+	for _, r := range [][2]string{{"2024-03-31", "2024-03-01"}, {"03/01/2024", ""}, {"2010-01-01", "2024-01-01"}} {
+		if _, err := statsService.GetActivity(utc.ID, r[0], r[1]); !errors.Is(err, ErrInvalidActivityRange) {
+			t.Errorf("expected ErrInvalidActivityRange for %v, got %v", r, err)
+		}
+	}
+}
+
+// dates sans heure envoyées par le front (minuit UTC) : même jour quel que
+// soit le fuseau, y compris à l'ouest d'UTC
+func TestStatsService_ActivityDateOnlyEntries(t *testing.T) {
+	db, tmdbService := setupCatalogTest(t, nil)
+	movieRepo := repository.NewMovieRepository(db)
+	movieService := NewMovieService(movieRepo, tmdbService)
+	statsService := NewStatsService(repository.NewStatsRepository(db), repository.NewUserRepository(db), nil, nil)
+
+	for i, title := range []string{"Heat", "Alien", "Brazil"} {
+		db.Create(&model.Movie{TmdbID: 600 + i, Title: title})
+	}
+	newYork := &model.User{Username: "newyork", Email: "newyork@example.com", TimeZone: "America/New_York"}
+	paris := &model.User{Username: "paris", Email: "paris@example.com"}
+	db.Create(newYork)
+	db.Create(paris)
+
+	newYearsDay := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	march := time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC)
+	evening := time.Date(2024, time.March, 3, 1, 0, 0, 0, time.UTC) // 2 mars, 20h à New York
+	watched := true
+	for _, user := range []*model.User{newYork, paris} {
+		if err := movieService.LogMovie(user.ID, 600, dto.LogMovieRequest{WatchedDate: &newYearsDay}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		movieService.TrackMovie(user.ID, 601, dto.TrackMovieRequest{IsWatched: &watched, WatchedDate: &evening})
+		if err := movieService.UpdateTrack(user.ID, 601, dto.TrackMovieRequest{WatchedDate: &march}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		// une date avec heure reste un instant
+		movieService.TrackMovie(user.ID, 602, dto.TrackMovieRequest{IsWatched: &watched, WatchedDate: &evening})
+	}
+
+	expected := map[uint][]dto.ActivityDay{
+		newYork.ID: {{Date: "2024-01-01", Films: 1}, {Date: "2024-03-02", Films: 2}},
+		paris.ID:   {{Date: "2024-01-01", Films: 1}, {Date: "2024-03-02", Films: 1}, {Date: "2024-03-03", Films: 1}},
+	}
+	for userID, days := range expected {
+		activity, err := statsService.GetActivity(userID, "2024-01-01", "2024-03-31")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(activity.Days) != len(days) {
+			t.Fatalf("expected %+v for user %d, got %+v", days, userID, activity.Days)
+		}
+		for i, day := range days {
+			if activity.Days[i] != day {
+				t.Errorf("expected %+v for user %d, got %+v", days, userID, activity.Days)
+			}
+		}
+	}
+
+	// le 1er janvier compte dans l'année de l'utilisateur
+	stats, err := statsService.GetYearStats(newYork.ID, 2024)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if stats.Months[0].Films != 1 || stats.TotalFilms != 3 {
+		t.Errorf("expected New Year's Day in January, got %d films (%d in January)", stats.TotalFilms, stats.Months[0].Films)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"time"
 
 	"github.com/Nowap83/FrameRate/backend/internal/dto"
@@ -21,11 +23,29 @@ var (
 	ErrUserNotFound      = errors.New("user not found")
 	ErrUsernameTaken     = errors.New("username already taken")
 	ErrPasswordIncorrect = errors.New("current password is incorrect")
+
+	ErrCannotModerateSelf = errors.New("you cannot perform this action on your own account")
+	ErrUserNotDeleted     = errors.New("user is not deleted")
+	ErrRestoreConflict    = errors.New("username or email has been taken since deletion")
+	ErrSuspensionInPast   = errors.New("suspension end date must be in the future")
+
+	ErrProfilePrivate   = errors.New("this profile is private")
+	ErrWatchlistPrivate = errors.New("this watchlist is private")
+
+	deletedSuffixPattern = regexp.MustCompile(`_deleted_\d+$`)
 )
 
 type UserService struct {
 	userRepo  repository.UserRepository
 	movieRepo *repository.MovieRepository
+	audit     *AuditService
+	authz     *AuthorizationService
+	follows   *FollowService
+	blocks    *BlockService
+	events    *EventBroker
+	policy    *ContentPolicy
+	goals     *ChallengeService
+	notifier  *NotificationService
 }
 
 func NewUserService(userRepo repository.UserRepository, movieRepo *repository.MovieRepository) *UserService {
@@ -35,6 +55,41 @@ func NewUserService(userRepo repository.UserRepository, movieRepo *repository.Mo
 	}
 }
 
+func (s *UserService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
+func (s *UserService) SetFollowService(follows *FollowService) {
+	s.follows = follows
+}
+
+func (s *UserService) SetBlockService(blocks *BlockService) {
+	s.blocks = blocks
+}
+
+// pour invalider le cache de rôle/suspension après une action admin
+func (s *UserService) SetEventBroker(events *EventBroker) {
+	s.events = events
+}
+
+func (s *UserService) SetAuthorizationService(authz *AuthorizationService) {
+	s.authz = authz
+}
+
+func (s *UserService) SetContentPolicy(policy *ContentPolicy) {
+	s.policy = policy
+}
+
+// objectif annuel affiché dans les stats du profil
+func (s *UserService) SetChallengeService(goals *ChallengeService) {
+	s.goals = goals
+}
+
+// prévient l'utilisateur des changements sur son compte
+func (s *UserService) SetNotificationService(notifier *NotificationService) {
+	s.notifier = notifier
+}
+
 // fetches a user by their ID
 func (s *UserService) GetUserByID(userID uint) (*model.User, error) {
 	user, err := s.userRepo.GetByID(userID)
@@ -45,15 +100,15 @@ func (s *UserService) GetUserByID(userID uint) (*model.User, error) {
 }
 
 // fetches all users (for admin)
-func (s *UserService) GetAllUsers(page, limit int) (*dto.PaginatedUsersResponse, error) {
-	users, total, err := s.userRepo.GetAllUsers(page, limit)
+func (s *UserService) GetAllUsers(filter repository.UserFilter, page, limit int) (*dto.PaginatedUsersResponse, error) {
+	users, total, err := s.userRepo.GetAllUsers(filter, page, limit)
 	if err != nil {
 		return nil, errors.New("failed to fetch users")
 	}
 
-	var userResponses []dto.UserResponse
+	userResponses := make([]dto.AdminUserResponse, 0, len(users))
 	for _, user := range users {
-		userResponses = append(userResponses, dto.ToUserResponse(user))
+		userResponses = append(userResponses, dto.ToAdminUserResponse(user))
 	}
 
 	totalPages := int((total + int64(limit) - 1) / int64(limit))
@@ -69,6 +124,20 @@ func (s *UserService) GetAllUsers(page, limit int) (*dto.PaginatedUsersResponse,
 	return response, nil
 }
 
+// (admin) détail d'un compte, y compris supprimé, avec ses stats
+func (s *UserService) GetUserDetail(userID uint) (*dto.AdminUserDetailResponse, error) {
+	user, err := s.userRepo.GetByIDUnscoped(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	return &dto.AdminUserDetailResponse{
+		User:        dto.ToAdminUserResponse(user),
+		Stats:       s.getStats(user),
+		LastLoginAt: s.audit.LastLogin(userID),
+	}, nil
+}
+
 // fetches user profile with statistics
 func (s *UserService) GetProfile(userID uint) (*dto.ProfileResponse, error) {
 	user, err := s.userRepo.GetByID(userID)
@@ -76,22 +145,9 @@ func (s *UserService) GetProfile(userID uint) (*dto.ProfileResponse, error) {
 		return nil, ErrUserNotFound
 	}
 
-	// fetch stats
-	watchedCount, _ := s.movieRepo.CountWatched(userID)
-	watchedYearCount, _ := s.movieRepo.CountWatchedThisYear(userID)
-	reviewsCount, _ := s.movieRepo.CountReviews(userID)
-	ratingDist, _ := s.movieRepo.GetRatingDistribution(userID)
-
 	response := &dto.ProfileResponse{
-		User: dto.ToUserResponse(user),
-		Stats: &dto.UserStats{
-			TotalFilms:         watchedCount,
-			MoviesThisYear:     watchedYearCount,
-			Reviews:            reviewsCount,
-			Following:          0,
-			Followers:          0,
-			RatingDistribution: ratingDist,
-		},
+		User:  dto.ToUserResponse(user),
+		Stats: s.getStats(user),
 	}
 
 	// fetch recent activity
@@ -105,6 +161,26 @@ func (s *UserService) GetProfile(userID uint) (*dto.ProfileResponse, error) {
 	return response, nil
 }
 
+// "cette année" commence au 1er janvier dans le fuseau de l'utilisateur
+func (s *UserService) getStats(user *model.User) *dto.UserStats {
+	userID := user.ID
+	watchedCount, _ := s.movieRepo.CountWatched(userID)
+	watchedYearCount, _ := s.movieRepo.CountWatchedThisYear(userID, user.Zone())
+	reviewsCount, _ := s.movieRepo.CountReviews(userID)
+	ratingDist, _ := s.movieRepo.GetRatingDistribution(userID)
+	following, followers := s.follows.Counts(userID)
+
+	return &dto.UserStats{
+		TotalFilms:         watchedCount,
+		MoviesThisYear:     watchedYearCount,
+		Reviews:            reviewsCount,
+		Following:          following,
+		Followers:          followers,
+		RatingDistribution: ratingDist,
+		Goal:               s.goals.CurrentGoal(user, watchedYearCount),
+	}
+}
+
 // fetches paginated watched films with their ratings for a given user
 func (s *UserService) GetMyFilms(userID uint, page, limit int) (*dto.PaginatedMoviesResponse, error) {
 	moviesWithRatings, total, err := s.movieRepo.GetWatchedFilmsWithRatings(userID, page, limit)
@@ -142,7 +218,11 @@ func (s *UserService) GetMyFilms(userID uint, page, limit int) (*dto.PaginatedMo
 
 // fetches paginated full reviews for a given user
 func (s *UserService) GetMyReviews(userID uint, page, limit int) (*dto.PaginatedReviewsResponse, error) {
-	reviews, total, err := s.movieRepo.GetReviews(userID, page, limit)
+	return s.userReviews(userID, true, page, limit)
+}
+
+func (s *UserService) userReviews(userID uint, includeHidden bool, page, limit int) (*dto.PaginatedReviewsResponse, error) {
+	reviews, total, err := s.movieRepo.GetReviews(userID, includeHidden, page, limit)
 	if err != nil {
 		return nil, errors.New("failed to fetch user reviews")
 	}
@@ -157,7 +237,11 @@ func (s *UserService) GetMyReviews(userID uint, page, limit int) (*dto.Paginated
 			PosterURL:   r.PosterURL,
 			Rating:      r.Rating,
 			Content:     r.Content,
+			ContentHTML: r.ContentHTML,
 			IsSpoiler:   r.IsSpoiler,
+			LikeCount:   r.LikeCount,
+			IsEdited:    r.EditedAt != nil,
+			EditedAt:    r.EditedAt,
 			WatchedDate: r.WatchedDate,
 			ReviewedAt:  r.ReviewedAt,
 		})
@@ -223,6 +307,24 @@ func (s *UserService) UpdateProfile(userID uint, input dto.UpdateProfileRequest)
 		}
 	}
 
+	// politique de contenu sur les champs publics modifiés ; rien n'est
+	// enregistré si l'un d'eux est refusé
+	var verdicts []PolicyResult
+	if input.Username != nil && *input.Username != user.Username {
+		verdict, err := s.policy.Check(ContentFieldUsername, *input.Username)
+		if err != nil {
+			return nil, err
+		}
+		verdicts = append(verdicts, verdict)
+	}
+	if input.Bio != nil {
+		verdict, err := s.policy.Check(ContentFieldBio, *input.Bio)
+		if err != nil {
+			return nil, err
+		}
+		verdicts = append(verdicts, verdict)
+	}
+
 	updates := make(map[string]interface{})
 	if input.Username != nil {
 		updates["username"] = *input.Username
@@ -245,6 +347,9 @@ func (s *UserService) UpdateProfile(userID uint, input dto.UpdateProfileRequest)
 	if input.Website != nil {
 		updates["website"] = *input.Website
 	}
+	if input.TimeZone != nil {
+		updates["time_zone"] = *input.TimeZone
+	}
 
 	if err := s.userRepo.UpdateFields(userID, updates); err != nil {
 		return nil, errors.New("failed to update profile")
@@ -258,11 +363,47 @@ func (s *UserService) UpdateProfile(userID uint, input dto.UpdateProfileRequest)
 		}
 	}
 
-	return s.GetProfile(userID)
+	fields := make([]string, 0, len(updates)+1)
+	for field := range updates {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	if input.FavoriteFilms != nil {
+		fields = append(fields, "favorite_films")
+	}
+	if len(fields) > 0 {
+		s.notifier.Notify([]uint{userID}, nil, model.ProfileUpdatedPayload{Fields: fields})
+	}
+
+	profile, err := s.GetProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+	s.flagProfile(verdicts, profile)
+	s.events.Publish(userID, EventProfileUpdated, profile)
+	return profile, nil
+}
+
+// un seul signalement pour le profil, avec les règles de tous les champs
+func (s *UserService) flagProfile(verdicts []PolicyResult, profile *dto.ProfileResponse) {
+	merged := PolicyResult{Verdict: PolicyAllow}
+	for _, verdict := range verdicts {
+		if verdict.Verdict == PolicyFlag {
+			merged.Verdict = PolicyFlag
+			merged.Rules = append(merged.Rules, verdict.Rules...)
+		}
+	}
+
+	user := profile.User
+	snapshot := user.Username
+	if user.Bio != nil {
+		snapshot += "\n" + *user.Bio
+	}
+	s.policy.Flag(merged, ContentTarget{Type: model.ReportTargetProfile, UserID: user.ID, Snapshot: snapshot})
 }
 
 // changes the user's password
-func (s *UserService) ChangePassword(userID uint, input dto.ChangePasswordRequest) error {
+func (s *UserService) ChangePassword(userID uint, input dto.ChangePasswordRequest, meta dto.RequestMeta) error {
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		return ErrUserNotFound
@@ -281,11 +422,199 @@ func (s *UserService) ChangePassword(userID uint, input dto.ChangePasswordReques
 		return errors.New("failed to update password")
 	}
 
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditPasswordChanged,
+		ActorID:  uintPtr(userID),
+		TargetID: uintPtr(userID),
+	})
+
+	return nil
+}
+
+// deletes the user's own account
+func (s *UserService) DeleteAccount(userID uint, meta dto.RequestMeta) error {
+	if err := s.deleteAccount(userID); err != nil {
+		return err
+	}
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditAccountDeleted,
+		ActorID:  uintPtr(userID),
+		TargetID: uintPtr(userID),
+	})
 	return nil
 }
 
-// deletes the user's account
-func (s *UserService) DeleteAccount(userID uint) error {
+// (admin) supprime le compte d'un autre utilisateur
+func (s *UserService) DeleteUserByAdmin(adminID, userID uint, meta dto.RequestMeta) error {
+	if adminID == userID {
+		return ErrCannotModerateSelf
+	}
+	if err := s.deleteAccount(userID); err != nil {
+		return err
+	}
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditAdminUserDeleted,
+		ActorID:  uintPtr(adminID),
+		TargetID: uintPtr(userID),
+	})
+	return nil
+}
+
+// (admin) suspend un compte, indéfiniment ou jusqu'à input.Until
+func (s *UserService) SuspendUser(adminID, userID uint, input dto.SuspendUserRequest, meta dto.RequestMeta) (*dto.AdminUserResponse, error) {
+	if adminID == userID {
+		return nil, ErrCannotModerateSelf
+	}
+	now := time.Now()
+	if input.Until != nil && !input.Until.After(now) {
+		return nil, ErrSuspensionInPast
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	reason := input.Reason
+	if err := s.userRepo.UpdateFields(userID, map[string]interface{}{
+		"suspended_at":      now,
+		"suspended_until":   input.Until,
+		"suspension_reason": reason,
+	}); err != nil {
+		return nil, errors.New("failed to suspend user")
+	}
+	user.SuspendedAt = &now
+	user.SuspendedUntil = input.Until
+	user.SuspensionReason = &reason
+	s.authz.InvalidateUser(userID)
+
+	metadata := map[string]interface{}{"reason": reason}
+	if input.Until != nil {
+		metadata["until"] = input.Until.UTC().Format(time.RFC3339)
+	}
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditAdminUserSuspended,
+		ActorID:  uintPtr(adminID),
+		TargetID: uintPtr(userID),
+		Metadata: metadata,
+	})
+	s.notifier.Notify([]uint{userID}, nil, model.AccountSuspendedPayload{Reason: reason, Until: input.Until})
+
+	response := dto.ToAdminUserResponse(user)
+	return &response, nil
+}
+
+// (admin) lève la suspension d'un compte
+func (s *UserService) UnsuspendUser(adminID, userID uint, meta dto.RequestMeta) (*dto.AdminUserResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if err := s.userRepo.UpdateFields(userID, map[string]interface{}{
+		"suspended_at":      nil,
+		"suspended_until":   nil,
+		"suspension_reason": nil,
+	}); err != nil {
+		return nil, errors.New("failed to unsuspend user")
+	}
+	user.SuspendedAt = nil
+	user.SuspendedUntil = nil
+	user.SuspensionReason = nil
+	s.authz.InvalidateUser(userID)
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditAdminUserUnsuspended,
+		ActorID:  uintPtr(adminID),
+		TargetID: uintPtr(userID),
+	})
+	s.notifier.Notify([]uint{userID}, nil, model.AccountUnsuspendedPayload{})
+
+	response := dto.ToAdminUserResponse(user)
+	return &response, nil
+}
+
+// (admin) marque l'email comme vérifié sans passer par le lien
+func (s *UserService) ForceVerifyUser(adminID, userID uint, meta dto.RequestMeta) (*dto.AdminUserResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if !user.IsVerified {
+		if err := s.userRepo.UpdateFields(userID, map[string]interface{}{
+			"is_verified":        true,
+			"verification_token": nil,
+			"token_expires_at":   nil,
+		}); err != nil {
+			return nil, errors.New("failed to verify user")
+		}
+		user.IsVerified = true
+
+		s.audit.Record(meta, model.AuditEvent{
+			Action:   model.AuditAdminUserVerified,
+			ActorID:  uintPtr(adminID),
+			TargetID: uintPtr(userID),
+		})
+		s.notifier.Notify([]uint{userID}, nil, model.AccountVerifiedPayload{Email: user.Email})
+	}
+
+	response := dto.ToAdminUserResponse(user)
+	return &response, nil
+}
+
+// (admin) annule le soft-delete ; les identifiants anonymisés retrouvent leur
+// valeur d'origine, sauf s'ils ont été repris entre-temps
+func (s *UserService) RestoreUser(adminID, userID uint, meta dto.RequestMeta) (*dto.AdminUserResponse, error) {
+	user, err := s.userRepo.GetByIDUnscoped(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.DeletedAt.Valid {
+		return nil, ErrUserNotDeleted
+	}
+
+	// identifiants conservés à la suppression ; suffixe retiré pour les
+	// comptes supprimés avant l'ajout de ces colonnes
+	username := deletedSuffixPattern.ReplaceAllString(user.Username, "")
+	if user.DeletedUsername != nil {
+		username = *user.DeletedUsername
+	}
+	email := deletedSuffixPattern.ReplaceAllString(user.Email, "")
+	if user.DeletedEmail != nil {
+		email = *user.DeletedEmail
+	}
+
+	if _, err := s.userRepo.GetByUsername(username); err == nil {
+		return nil, ErrRestoreConflict
+	}
+	if _, err := s.userRepo.GetByEmail(email); err == nil {
+		return nil, ErrRestoreConflict
+	}
+
+	if err := s.userRepo.Restore(userID, username, email); err != nil {
+		return nil, errors.New("failed to restore user")
+	}
+	s.authz.InvalidateUser(userID)
+
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditAdminUserRestored,
+		ActorID:  uintPtr(adminID),
+		TargetID: uintPtr(userID),
+	})
+	s.notifier.Notify([]uint{userID}, nil, model.AccountRestoredPayload{Username: username})
+
+	restored, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	response := dto.ToAdminUserResponse(restored)
+	return &response, nil
+}
+
+func (s *UserService) deleteAccount(userID uint) error {
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		return ErrUserNotFound
@@ -307,8 +636,10 @@ func (s *UserService) DeleteAccount(userID uint) error {
 	newEmail += timestamp
 
 	if err := s.userRepo.UpdateFields(userID, map[string]interface{}{
-		"username": newUsername,
-		"email":    newEmail,
+		"username":         newUsername,
+		"email":            newEmail,
+		"deleted_username": user.Username,
+		"deleted_email":    user.Email,
 	}); err != nil {
 		return errors.New("failed to anonymize account details before deletion")
 	}
@@ -316,11 +647,12 @@ func (s *UserService) DeleteAccount(userID uint) error {
 	if err := s.userRepo.Delete(userID); err != nil {
 		return errors.New("failed to delete account")
 	}
+	s.authz.InvalidateUser(userID)
 	return nil
 }
 
 // updates the user's avatar
-func (s *UserService) UpdateAvatar(userID uint, file *multipart.FileHeader) (string, error) {
+func (s *UserService) UpdateAvatar(userID uint, file *multipart.FileHeader, meta dto.RequestMeta) (string, error) {
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		return "", ErrUserNotFound
@@ -361,11 +693,28 @@ func (s *UserService) UpdateAvatar(userID uint, file *multipart.FileHeader) (str
 		return "", errors.New("failed to update profile picture in database")
 	}
 
+	s.audit.Record(meta, model.AuditEvent{
+		Action:   model.AuditAvatarUploaded,
+		ActorID:  uintPtr(userID),
+		TargetID: uintPtr(userID),
+		Metadata: map[string]interface{}{"url": fileURL},
+	})
+	s.notifier.Notify([]uint{userID}, nil, model.ProfileUpdatedPayload{Fields: []string{"profile_picture_url"}})
+
+	if s.events != nil {
+		if profile, err := s.GetProfile(userID); err == nil {
+			s.events.Publish(userID, EventProfileUpdated, profile)
+		}
+	}
+
 	return fileURL, nil
 }
 
 // checks if a username is available
 func (s *UserService) CheckUsernameAvailability(username string) (bool, error) {
+	if _, err := s.policy.Check(ContentFieldUsername, username); err != nil {
+		return false, nil
+	}
 	_, err := s.userRepo.GetByUsername(username)
 	if err == nil {
 		return false, nil
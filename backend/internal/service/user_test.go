@@ -5,6 +5,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -24,7 +25,7 @@ func setupUserServiceTestDB(t *testing.T) *gorm.DB {
 		t.Fatalf("Failed to open test database: %v", err)
 	}
 
-	err = db.AutoMigrate(&model.User{}, &model.Movie{}, &model.Track{}, &model.Rate{}, &model.Review{})
+	err = db.AutoMigrate(&model.User{}, &model.Movie{}, &model.Track{}, &model.Rate{}, &model.Review{}, &model.ReviewLike{}, &model.AuditEvent{})
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
@@ -63,7 +64,7 @@ func TestUserService_GetAllUsers(t *testing.T) {
 		db.Create(&model.User{Username: "user" + string(rune(i)), Email: "test" + string(rune(i)) + "@test.com"})
 	}
 
-	resp, err := userService.GetAllUsers(1, 3)
+	resp, err := userService.GetAllUsers(repository.UserFilter{}, 1, 3)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -132,7 +133,7 @@ func TestUserService_ChangePassword(t *testing.T) {
 	db.Create(user)
 
 	req := dto.ChangePasswordRequest{CurrentPassword: "oldpass", NewPassword: "newpass"}
-	err := userService.ChangePassword(user.ID, req)
+	err := userService.ChangePassword(user.ID, req, dto.RequestMeta{})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -144,6 +145,31 @@ func TestUserService_ChangePassword(t *testing.T) {
 	}
 }
 
+func TestUserService_ChangePassword_Audited(t *testing.T) {
+	utils.Log = zap.NewNop()
+	db := setupUserServiceTestDB(t)
+	userService := NewUserService(repository.NewUserRepository(db), repository.NewMovieRepository(db))
+	userService.SetAuditService(NewAuditService(repository.NewAuditRepository(db)))
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("oldpass"), bcrypt.DefaultCost)
+	user := &model.User{Username: "audituser", Email: "audit@example.com", PasswordHash: string(hash)}
+	db.Create(user)
+
+	meta := dto.RequestMeta{IP: "203.0.113.7", UserAgent: "test-agent"}
+	req := dto.ChangePasswordRequest{CurrentPassword: "oldpass", NewPassword: "newpass"}
+	if err := userService.ChangePassword(user.ID, req, meta); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var event model.AuditEvent
+	if err := db.Where("action = ?", model.AuditPasswordChanged).First(&event).Error; err != nil {
+		t.Fatalf("expected password change to be audited, got %v", err)
+	}
+	if event.ActorID == nil || *event.ActorID != user.ID || event.IP != meta.IP || event.UserAgent != meta.UserAgent {
+		t.Errorf("unexpected audit event: %+v", event)
+	}
+}
+
 func TestUserService_CheckUsernameAvailability(t *testing.T) {
 	utils.Log = zap.NewNop()
 	db := setupUserServiceTestDB(t)
@@ -175,7 +201,7 @@ func TestUserService_DeleteAccount(t *testing.T) {
 	user := &model.User{Username: "todelete", Email: "delete@example.com"}
 	db.Create(user)
 
-	err := userService.DeleteAccount(user.ID)
+	err := userService.DeleteAccount(user.ID, dto.RequestMeta{})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -186,6 +212,134 @@ func TestUserService_DeleteAccount(t *testing.T) {
 	}
 }
 
+func TestUserService_SuspendUser(t *testing.T) {
+	utils.Log = zap.NewNop()
+	db := setupUserServiceTestDB(t)
+	db.AutoMigrate(&model.Role{})
+	userRepo := repository.NewUserRepository(db)
+	userService := NewUserService(userRepo, repository.NewMovieRepository(db))
+	userService.SetAuditService(NewAuditService(repository.NewAuditRepository(db)))
+	authz := NewAuthorizationService(userRepo, repository.NewRoleRepository(db))
+	userService.SetAuthorizationService(authz)
+
+	admin := &model.User{Username: "admin", Email: "admin@example.com", Role: model.RoleAdmin}
+	target := &model.User{Username: "target", Email: "target@example.com"}
+	db.Create(admin)
+	db.Create(target)
+
+	if suspended, _ := authz.IsSuspended(target.ID); suspended {
+		t.Fatal("expected user not to be suspended yet")
+	}
+
+	past := time.Now().Add(-time.Hour)
+	if _, err := userService.SuspendUser(admin.ID, target.ID, dto.SuspendUserRequest{Reason: "spam", Until: &past}, dto.RequestMeta{}); err != ErrSuspensionInPast {
+		t.Errorf("expected ErrSuspensionInPast, got %v", err)
+	}
+	if _, err := userService.SuspendUser(admin.ID, admin.ID, dto.SuspendUserRequest{Reason: "oops"}, dto.RequestMeta{}); err != ErrCannotModerateSelf {
+		t.Errorf("expected ErrCannotModerateSelf, got %v", err)
+	}
+
+	resp, err := userService.SuspendUser(admin.ID, target.ID, dto.SuspendUserRequest{Reason: "spam"}, dto.RequestMeta{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !resp.IsSuspended || resp.SuspensionReason == nil || *resp.SuspensionReason != "spam" {
+		t.Errorf("expected suspended response with reason, got %+v", resp)
+	}
+	if suspended, _ := authz.IsSuspended(target.ID); !suspended {
+		t.Error("expected suspension to apply immediately (cache invalidated)")
+	}
+
+	if _, err := userService.UnsuspendUser(admin.ID, target.ID, dto.RequestMeta{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if suspended, _ := authz.IsSuspended(target.ID); suspended {
+		t.Error("expected user to be unsuspended")
+	}
+
+	var events int64
+	db.Model(&model.AuditEvent{}).
+		Where("target_id = ? AND action IN ?", target.ID, []string{model.AuditAdminUserSuspended, model.AuditAdminUserUnsuspended}).
+		Count(&events)
+	if events != 2 {
+		t.Errorf("expected 2 audit events, got %d", events)
+	}
+}
+
+func TestUserService_ForceVerifyUser(t *testing.T) {
+	utils.Log = zap.NewNop()
+	db := setupUserServiceTestDB(t)
+	userService := NewUserService(repository.NewUserRepository(db), repository.NewMovieRepository(db))
+
+	token := "pending-token"
+	user := &model.User{Username: "pending", Email: "pending@example.com", VerificationToken: &token}
+	db.Create(user)
+
+	resp, err := userService.ForceVerifyUser(99, user.ID, dto.RequestMeta{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !resp.IsVerified {
+		t.Error("expected user to be verified")
+	}
+
+	var updated model.User
+	db.First(&updated, user.ID)
+	if !updated.IsVerified || updated.VerificationToken != nil {
+		t.Error("expected verification token to be cleared")
+	}
+}
+
+func TestUserService_RestoreUser(t *testing.T) {
+	utils.Log = zap.NewNop()
+	db := setupUserServiceTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+	userService := NewUserService(userRepo, repository.NewMovieRepository(db))
+
+	user := &model.User{Username: "comeback", Email: "comeback@example.com"}
+	db.Create(user)
+
+	if _, err := userService.RestoreUser(1, user.ID, dto.RequestMeta{}); err != ErrUserNotDeleted {
+		t.Errorf("expected ErrUserNotDeleted, got %v", err)
+	}
+
+	if err := userService.DeleteUserByAdmin(999, user.ID, dto.RequestMeta{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	detail, err := userService.GetUserDetail(user.ID)
+	if err != nil || detail.User.DeletedAt == nil {
+		t.Fatalf("expected deleted user to be visible in admin detail, got %v", err)
+	}
+
+	resp, err := userService.RestoreUser(1, user.ID, dto.RequestMeta{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.Username != "comeback" || resp.Email != "comeback@example.com" || resp.DeletedAt != nil {
+		t.Errorf("expected original identifiers to be restored, got %+v", resp)
+	}
+
+	// identifiants longs, tronqués par l'anonymisation
+	long := &model.User{Username: strings.Repeat("a", 45), Email: strings.Repeat("b", 240) + "@example.com"}
+	db.Create(long)
+	userService.DeleteUserByAdmin(999, long.ID, dto.RequestMeta{})
+	resp, err = userService.RestoreUser(1, long.ID, dto.RequestMeta{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.Username != long.Username || resp.Email != long.Email {
+		t.Errorf("expected long identifiers to be restored intact, got %s %s", resp.Username, resp.Email)
+	}
+
+	// username repris pendant la suppression => conflit
+	userService.DeleteUserByAdmin(999, user.ID, dto.RequestMeta{})
+	db.Create(&model.User{Username: "comeback", Email: "other@example.com"})
+	if _, err := userService.RestoreUser(1, user.ID, dto.RequestMeta{}); err != ErrRestoreConflict {
+		t.Errorf("expected ErrRestoreConflict, got %v", err)
+	}
+}
+
 func TestUserService_UpdateAvatar(t *testing.T) {
 	utils.Log = zap.NewNop()
 	db := setupUserServiceTestDB(t)
@@ -207,7 +361,7 @@ func TestUserService_UpdateAvatar(t *testing.T) {
 	_ = req.ParseMultipartForm(10 << 20)
 	_, fileHeader, _ := req.FormFile("avatar")
 
-	fileURL, err := userService.UpdateAvatar(user.ID, fileHeader)
+	fileURL, err := userService.UpdateAvatar(user.ID, fileHeader, dto.RequestMeta{})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
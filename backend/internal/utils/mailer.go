@@ -80,10 +80,69 @@ func (s *EmailService) SendVerificationEmail(to, username, token string) error {
         </html>
     `, username, verifyURL, verifyURL)
 
+	if err := s.send(to, "Verify your FrameRate account", html); err != nil {
+		return err
+	}
+
+	Log.Info("Verification email sent", zap.String("to", to))
+	return nil
+}
+
+func (s *EmailService) SendMagicLinkEmail(to, username, token string) error {
+	loginURL := fmt.Sprintf("%s/magic-link?token=%s", s.frontendURL, token)
+
+	html := fmt.Sprintf(`
+        <!DOCTYPE html>
+        <html>
+        <head>
+            <meta charset="UTF-8">
+            <style>
+                body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+                .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+                .header { background: linear-gradient(135deg, #667eea 0%%, #764ba2 100%%); color: white; padding: 30px; text-align: center; border-radius: 10px 10px 0 0; }
+                .content { background: #f9f9f9; padding: 30px; border-radius: 0 0 10px 10px; }
+                .button { display: inline-block; background: #667eea; color: white; padding: 15px 30px; text-decoration: none; border-radius: 5px; margin: 20px 0; }
+                .footer { text-align: center; margin-top: 20px; color: #888; font-size: 12px; }
+            </style>
+        </head>
+        <body>
+            <div class="container">
+                <div class="header">
+                    <h1>🎬 Log in to FrameRate</h1>
+                </div>
+                <div class="content">
+                    <p>Hi <strong>%s</strong>,</p>
+                    <p>Click the button below to log in. No password needed.</p>
+                    <p style="text-align: center;">
+                        <a href="%s" class="button">Log in</a>
+                    </p>
+                    <p>Or copy this link:</p>
+                    <p style="background: white; padding: 10px; border-left: 3px solid #667eea; word-break: break-all;">
+                        %s
+                    </p>
+                    <p><small>This link expires in 15 minutes and can only be used once.</small></p>
+                </div>
+                <div class="footer">
+                    <p>If you didn't request this link, you can safely ignore this email.</p>
+                </div>
+            </div>
+        </body>
+        </html>
+    `, username, loginURL, loginURL)
+
+	if err := s.send(to, "Your FrameRate login link", html); err != nil {
+		return err
+	}
+
+	Log.Info("Magic link email sent", zap.String("to", to))
+	return nil
+}
+
+func (s *EmailService) send(to, subject, html string) error {
 	params := &resend.SendEmailRequest{
 		From:    s.fromAddress,
 		To:      []string{to},
-		Subject: "Verify your FrameRate account",
+		Subject: subject,
 		Html:    html,
 	}
 
@@ -95,7 +154,5 @@ func (s *EmailService) SendVerificationEmail(to, username, token string) error {
 		)
 		return fmt.Errorf("failed to send email: %w", err)
 	}
-
-	Log.Info("Verification email sent", zap.String("to", to))
 	return nil
 }
@@ -0,0 +1,225 @@
+package utils
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// sous-ensemble Markdown des critiques :
+//   - **gras** / __gras__, *italique* / _italique_
+//   - [texte](https://lien) (http et https uniquement)
+//   - > citations (imbricables)
+//   - ||spoiler|| en ligne
+//
+// Tout le reste est échappé : aucune balise HTML de l'utilisateur ne passe,
+// le HTML produit ne contient que p, br, strong, em, a, blockquote et span.
+
+// contenu d'un passage spoiler masqué, côté source
+const RedactedSpoilerText = "[spoiler]"
+
+const redactedSpoilerHTML = `<span class="spoiler" data-redacted="true"></span>`
+
+var spoilerSpanRegex = regexp.MustCompile(`\|\|[^\n|][^\n]*?\|\|`)
+
+// RenderMarkdown convertit le source d'une critique en HTML sûr
+func RenderMarkdown(src string) string {
+	return renderMarkdownBlocks(normalizeNewlines(src), false)
+}
+
+// RenderMarkdownRedacted est RenderMarkdown avec les passages ||spoiler|| vidés ;
+// le source est d'abord expurgé pour qu'un balisage mal imbriqué ne fasse rien fuiter
+func RenderMarkdownRedacted(src string) string {
+	return renderMarkdownBlocks(RedactSpoilerSpans(normalizeNewlines(src)), true)
+}
+
+func HasSpoilerSpans(src string) bool {
+	return spoilerSpanRegex.MatchString(src)
+}
+
+// remplace le contenu des passages ||spoiler|| du source par RedactedSpoilerText
+func RedactSpoilerSpans(src string) string {
+	return spoilerSpanRegex.ReplaceAllString(src, "||"+RedactedSpoilerText+"||")
+}
+
+func normalizeNewlines(src string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(src, "\r\n", "\n"), "\r", "\n")
+}
+
+// découpe en paragraphes (lignes vides) et en citations (lignes en ">")
+func renderMarkdownBlocks(src string, redact bool) string {
+	var blocks []string
+	var paragraph, quote []string
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, "<p>"+renderInline(strings.Join(paragraph, "\n"), redact, false)+"</p>")
+			paragraph = nil
+		}
+	}
+	flushQuote := func() {
+		if len(quote) > 0 {
+			blocks = append(blocks, "<blockquote>\n"+renderMarkdownBlocks(strings.Join(quote, "\n"), redact)+"\n</blockquote>")
+			quote = nil
+		}
+	}
+
+	for _, line := range strings.Split(src, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flushParagraph()
+			flushQuote()
+		case strings.HasPrefix(trimmed, ">"):
+			flushParagraph()
+			quote = append(quote, strings.TrimPrefix(strings.TrimPrefix(trimmed, ">"), " "))
+		default:
+			flushQuote()
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flushParagraph()
+	flushQuote()
+
+	return strings.Join(blocks, "\n")
+}
+
+// rendu d'une ligne ; inLink empêche les liens imbriqués
+func renderInline(s string, redact, inLink bool) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); {
+		rest := s[i:]
+
+		// échappement d'un caractère de syntaxe
+		if rest[0] == '\\' && len(rest) > 1 && strings.IndexByte(`\*_[]()|>`, rest[1]) >= 0 {
+			writeEscapedByte(&b, rest[1])
+			i += 2
+			continue
+		}
+
+		if strings.HasPrefix(rest, "||") {
+			if inner, ok := delimited(rest, "||"); ok && !strings.Contains(inner, "\n") {
+				if redact {
+					b.WriteString(redactedSpoilerHTML)
+				} else {
+					b.WriteString(`<span class="spoiler">` + renderInline(inner, redact, inLink) + `</span>`)
+				}
+				i += len(inner) + 4
+				continue
+			}
+		}
+
+		if strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__") {
+			if inner, ok := delimited(rest, rest[:2]); ok && opensEmphasis(s, i, inner) {
+				b.WriteString("<strong>" + renderInline(inner, redact, inLink) + "</strong>")
+				i += len(inner) + 4
+				continue
+			}
+		}
+
+		if rest[0] == '*' || rest[0] == '_' {
+			if inner, ok := delimited(rest, rest[:1]); ok && opensEmphasis(s, i, inner) {
+				b.WriteString("<em>" + renderInline(inner, redact, inLink) + "</em>")
+				i += len(inner) + 2
+				continue
+			}
+		}
+
+		if rest[0] == '[' && !inLink {
+			if label, href, n, ok := parseLink(rest); ok {
+				b.WriteString(`<a href="` + escapeHTML(href) + `" rel="nofollow ugc noopener noreferrer" target="_blank">` + renderInline(label, redact, true) + "</a>")
+				i += n
+				continue
+			}
+		}
+
+		if rest[0] == '\n' {
+			b.WriteString("<br>\n")
+		} else {
+			writeEscapedByte(&b, rest[0])
+		}
+		i++
+	}
+
+	return b.String()
+}
+
+// contenu entre delim en tête de s et sa prochaine occurrence
+func delimited(s, delim string) (string, bool) {
+	end := strings.Index(s[len(delim):], delim)
+	if end <= 0 {
+		return "", false
+	}
+	return s[len(delim) : len(delim)+end], true
+}
+
+// pas d'emphase sur "* liste" ni au milieu d'un mot_avec_underscores
+func opensEmphasis(s string, i int, inner string) bool {
+	if strings.TrimSpace(inner) != inner {
+		return false
+	}
+	if s[i] == '_' && i > 0 && isWordByte(s[i-1]) {
+		return false
+	}
+	return true
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// [label](href) ; n = longueur consommée
+func parseLink(s string) (label, href string, n int, ok bool) {
+	closeLabel := strings.Index(s, "](")
+	if closeLabel <= 1 || strings.Contains(s[:closeLabel], "\n") {
+		return "", "", 0, false
+	}
+	closeHref := strings.IndexByte(s[closeLabel+2:], ')')
+	if closeHref <= 0 {
+		return "", "", 0, false
+	}
+
+	href = strings.TrimSpace(s[closeLabel+2 : closeLabel+2+closeHref])
+	if !isSafeLink(href) {
+		return "", "", 0, false
+	}
+	return s[1:closeLabel], href, closeLabel + 3 + closeHref, true
+}
+
+func isSafeLink(href string) bool {
+	if strings.ContainsAny(href, " \n\t|") {
+		return false
+	}
+	parsed, err := url.Parse(href)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+func escapeHTML(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		writeEscapedByte(&b, s[i])
+	}
+	return b.String()
+}
+
+// les octets UTF-8 non ASCII passent tels quels
+func writeEscapedByte(b *strings.Builder, c byte) {
+	switch c {
+	case '&':
+		b.WriteString("&amp;")
+	case '<':
+		b.WriteString("&lt;")
+	case '>':
+		b.WriteString("&gt;")
+	case '"':
+		b.WriteString("&#34;")
+	case '\'':
+		b.WriteString("&#39;")
+	default:
+		b.WriteByte(c)
+	}
+}
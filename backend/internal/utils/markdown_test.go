@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"emphasis", "**bold** and *italic* and __also__ _this_", "<p><strong>bold</strong> and <em>italic</em> and <strong>also</strong> <em>this</em></p>"},
+		{"nested emphasis", "**very *nested* text**", "<p><strong>very <em>nested</em> text</strong></p>"},
+		{"intraword underscores", "snake_case_name", "<p>snake_case_name</p>"},
+		{"line breaks and paragraphs", "line one\nline two\n\nsecond", "<p>line one<br>\nline two</p>\n<p>second</p>"},
+		{"quote", "> quoted **text**\n> more\n\nafter", "<blockquote>\n<p>quoted <strong>text</strong><br>\nmore</p>\n</blockquote>\n<p>after</p>"},
+		{"nested quote", ">> deep", "<blockquote>\n<blockquote>\n<p>deep</p>\n</blockquote>\n</blockquote>"},
+		{"link", "[TMDB](https://www.themoviedb.org/movie/603)", `<p><a href="https://www.themoviedb.org/movie/603" rel="nofollow ugc noopener noreferrer" target="_blank">TMDB</a></p>`},
+		{"emphasis in link", "[**bold** link](https://a.com)", `<p><a href="https://a.com" rel="nofollow ugc noopener noreferrer" target="_blank"><strong>bold</strong> link</a></p>`},
+		{"spoiler", "the end is ||a dream||", `<p>the end is <span class="spoiler">a dream</span></p>`},
+		{"escaped syntax", `\*not italic\*`, "<p>*not italic*</p>"},
+		{"unclosed markers", "2 * 3 and ||open", "<p>2 * 3 and ||open</p>"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RenderMarkdown(tt.input))
+		})
+	}
+}
+
+func TestRenderMarkdown_Sanitizes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"raw html", `<script>alert("x")</script>`, "<p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;</p>"},
+		{"javascript link", "[click](javascript:alert(1))", "<p>[click](javascript:alert(1))</p>"},
+		{"data link", "[click](data:text/html;base64,PHNjcmlwdD4=)", "<p>[click](data:text/html;base64,PHNjcmlwdD4=)</p>"},
+		{"attribute injection", `[x](https://a.com/"onmouseover="alert(1))`, `<p><a href="https://a.com/&#34;onmouseover=&#34;alert(1" rel="nofollow ugc noopener noreferrer" target="_blank">x</a>)</p>`},
+		{"html inside emphasis", "**<img src=x onerror=alert(1)>**", "<p><strong>&lt;img src=x onerror=alert(1)&gt;</strong></p>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RenderMarkdown(tt.input))
+		})
+	}
+}
+
+func TestSpoilerRedaction(t *testing.T) {
+	src := "great film, ||he was dead all along|| and **||the twist||**"
+
+	assert.True(t, HasSpoilerSpans(src))
+	assert.False(t, HasSpoilerSpans("no spoilers || here"))
+
+	assert.Equal(t, "great film, ||[spoiler]|| and **||[spoiler]||**", RedactSpoilerSpans(src))
+	assert.Equal(t,
+		`<p>great film, <span class="spoiler" data-redacted="true"></span> and <strong><span class="spoiler" data-redacted="true"></span></strong></p>`,
+		RenderMarkdownRedacted(src))
+
+	// un spoiler mal imbriqué ne doit pas fuiter dans le HTML expurgé
+	leaky := "**a ||secret** text||"
+	assert.NotContains(t, RenderMarkdownRedacted(leaky), "secret")
+	assert.NotContains(t, RenderMarkdownRedacted("[x](https://a.com/||secret||)"), "secret")
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 )
 
@@ -13,3 +14,9 @@ func GenerateVerificationToken() (string, error) {
 	}
 	return hex.EncodeToString(bytes), nil
 }
+
+// hash sha256 d'un token avant stockage en base
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}